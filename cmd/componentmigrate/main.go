@@ -0,0 +1,67 @@
+// Command componentmigrate rewrites stored component state to the
+// newest registered schema version and codec:
+//
+//	componentmigrate -type on_fire            # one type
+//	componentmigrate -all -batch 1000         # every registered type
+//
+// Reads DATABASE_URL from the environment or .env, like the server. It
+// is safe to run against a live database and to re-run after an
+// interruption.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/dukerupert/walking-drum/internal/db"
+	"github.com/dukerupert/walking-drum/internal/envfile"
+	"github.com/dukerupert/walking-drum/internal/game"
+)
+
+func main() {
+	typ := flag.String("type", "", "component type to migrate")
+	all := flag.Bool("all", false, "migrate every registered component type")
+	batch := flag.Int("batch", game.DefaultMigrateBatchSize, "rows per transaction")
+	flag.Parse()
+
+	if (*typ == "") == !*all {
+		fmt.Fprintln(os.Stderr, "componentmigrate: pass exactly one of -type or -all")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := envfile.Load(".env"); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Fatalf("load .env: %v", err)
+	}
+	dbURL := os.Getenv("DATABASE_URL")
+	if dbURL == "" {
+		log.Fatalf("DATABASE_URL is not set")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	pool, err := db.Connect(ctx, dbURL)
+	if err != nil {
+		log.Fatalf("connect: %v", err)
+	}
+	defer pool.Close()
+
+	types := []string{*typ}
+	if *all {
+		types = game.RegisteredComponentTypes()
+	}
+	for _, t := range types {
+		rep, err := game.MigrateComponents(ctx, pool, t, int32(*batch))
+		if err != nil {
+			log.Fatalf("%s: %v (rewrote %d rows before failing)", t, err, rep.Rewritten)
+		}
+		fmt.Printf("%s: v%d %s, rewrote %d of %d rows in %d batches\n", t, rep.Version, rep.Codec, rep.Rewritten, rep.Scanned, rep.Batches)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"io/fs"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -14,7 +16,10 @@ import (
 	"github.com/pressly/goose/v3"
 
 	"github.com/dukerupert/walking-drum/internal/db"
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
 	"github.com/dukerupert/walking-drum/internal/envfile"
+	"github.com/dukerupert/walking-drum/internal/game"
+	"github.com/dukerupert/walking-drum/internal/season"
 )
 
 func main() {
@@ -40,7 +45,54 @@ func main() {
 		log.Fatalf("migrations: %v", err)
 	}
 
+	if err := game.VerifyComponentRegistry(ctx, sqlc.New(pool)); err != nil {
+		log.Fatalf("component registry: %v", err)
+	}
+
+	prefabDir := os.Getenv("PREFAB_DIR")
+	if prefabDir == "" {
+		prefabDir = "content/prefabs"
+	}
+	prefabs, err := game.LoadPrefabs(os.DirFS(prefabDir))
+	if err != nil {
+		log.Fatalf("prefabs: %v", err)
+	}
+	log.Printf("loaded %d prefabs from %s", len(prefabs.Keys()), prefabDir)
+
+	// Startup is bounded by ctx; the server itself runs until it's
+	// told to stop.
+	srvCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	costs := game.NewActionCosts(season.NewResolver(season.DefaultModifiers()))
+	if err := costs.Load(ctx, sqlc.New(pool)); err != nil {
+		log.Fatalf("action costs: %v", err)
+	}
+	log.Printf("loaded %d action costs", len(costs.Base()))
+
+	// LISTEN ties up its connection, so the hot reload gets its own.
+	listenConn, err := pool.Acquire(ctx)
+	if err != nil {
+		log.Fatalf("action costs: acquire listener: %v", err)
+	}
+	listening := make(chan error, 1)
+	go func() {
+		defer listenConn.Release()
+		listening <- costs.Listen(srvCtx, listenConn.Conn(), func(err error) {
+			log.Printf("action costs: %v", err)
+		})
+	}()
+
 	fmt.Println("ok")
+
+	select {
+	case <-srvCtx.Done():
+		if err := <-listening; err != nil {
+			log.Printf("action costs: %v", err)
+		}
+	case err := <-listening:
+		log.Fatalf("action costs: listener stopped: %v", err)
+	}
 }
 
 // runMigrations applies all pending goose migrations. Goose needs a
@@ -99,6 +99,25 @@ func (q *Queries) GetAccountByID(ctx context.Context, id pgtype.UUID) (Account,
 	return i, err
 }
 
+const hasAccountFlag = `-- name: HasAccountFlag :one
+SELECT EXISTS (
+  SELECT 1 FROM account_flags
+  WHERE account_id = $1 AND flag_type = $2
+)
+`
+
+type HasAccountFlagParams struct {
+	AccountID pgtype.UUID
+	FlagType  string
+}
+
+func (q *Queries) HasAccountFlag(ctx context.Context, arg HasAccountFlagParams) (bool, error) {
+	row := q.db.QueryRow(ctx, hasAccountFlag, arg.AccountID, arg.FlagType)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
 const softDeleteAccount = `-- name: SoftDeleteAccount :exec
 UPDATE accounts
 SET status = 'deleted', deleted_at = NOW()
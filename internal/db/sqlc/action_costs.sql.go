@@ -0,0 +1,58 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: action_costs.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const listActionCosts = `-- name: ListActionCosts :many
+SELECT action, cost, updated_at FROM action_costs
+ORDER BY action
+`
+
+func (q *Queries) ListActionCosts(ctx context.Context) ([]ActionCost, error) {
+	rows, err := q.db.Query(ctx, listActionCosts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ActionCost{}
+	for rows.Next() {
+		var i ActionCost
+		if err := rows.Scan(&i.Action, &i.Cost, &i.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setActionCost = `-- name: SetActionCost :one
+INSERT INTO action_costs (action, cost)
+VALUES ($1, $2)
+ON CONFLICT (action) DO UPDATE
+SET cost = EXCLUDED.cost,
+    updated_at = NOW()
+RETURNING action, cost, updated_at
+`
+
+type SetActionCostParams struct {
+	Action string
+	Cost   int32
+}
+
+// Creates or retunes an action's base cost. The notify trigger tells
+// running servers to reload.
+func (q *Queries) SetActionCost(ctx context.Context, arg SetActionCostParams) (ActionCost, error) {
+	row := q.db.QueryRow(ctx, setActionCost, arg.Action, arg.Cost)
+	var i ActionCost
+	err := row.Scan(&i.Action, &i.Cost, &i.UpdatedAt)
+	return i, err
+}
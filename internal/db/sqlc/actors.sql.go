@@ -0,0 +1,255 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: actors.sql
+
+package sqlc
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createActor = `-- name: CreateActor :one
+INSERT INTO actors (
+  entity_id, energy, energy_regen, energy_cap, updated_at_tick
+) VALUES (
+  $1, $2, $3,
+  $2, $4
+)
+RETURNING entity_id, energy, energy_regen, energy_cap, next_ready_tick, updated_at_tick
+`
+
+type CreateActorParams struct {
+	EntityID      pgtype.UUID
+	EnergyCap     int32
+	EnergyRegen   int32
+	UpdatedAtTick int64
+}
+
+// Inserts an actor, idle and at full energy.
+func (q *Queries) CreateActor(ctx context.Context, arg CreateActorParams) (Actor, error) {
+	row := q.db.QueryRow(ctx, createActor,
+		arg.EntityID,
+		arg.EnergyCap,
+		arg.EnergyRegen,
+		arg.UpdatedAtTick,
+	)
+	var i Actor
+	err := row.Scan(
+		&i.EntityID,
+		&i.Energy,
+		&i.EnergyRegen,
+		&i.EnergyCap,
+		&i.NextReadyTick,
+		&i.UpdatedAtTick,
+	)
+	return i, err
+}
+
+const getActor = `-- name: GetActor :one
+SELECT entity_id, energy, energy_regen, energy_cap, next_ready_tick, updated_at_tick FROM actors
+WHERE entity_id = $1
+`
+
+func (q *Queries) GetActor(ctx context.Context, entityID pgtype.UUID) (Actor, error) {
+	row := q.db.QueryRow(ctx, getActor, entityID)
+	var i Actor
+	err := row.Scan(
+		&i.EntityID,
+		&i.Energy,
+		&i.EnergyRegen,
+		&i.EnergyCap,
+		&i.NextReadyTick,
+		&i.UpdatedAtTick,
+	)
+	return i, err
+}
+
+const getActorForUpdate = `-- name: GetActorForUpdate :one
+SELECT entity_id, energy, energy_regen, energy_cap, next_ready_tick, updated_at_tick FROM actors
+WHERE entity_id = $1
+FOR UPDATE
+`
+
+// GetActor, locking the row for a read-modify-write of energy.
+func (q *Queries) GetActorForUpdate(ctx context.Context, entityID pgtype.UUID) (Actor, error) {
+	row := q.db.QueryRow(ctx, getActorForUpdate, entityID)
+	var i Actor
+	err := row.Scan(
+		&i.EntityID,
+		&i.Energy,
+		&i.EnergyRegen,
+		&i.EnergyCap,
+		&i.NextReadyTick,
+		&i.UpdatedAtTick,
+	)
+	return i, err
+}
+
+const listReadyActors = `-- name: ListReadyActors :many
+SELECT entity_id, energy, energy_regen, energy_cap, next_ready_tick, updated_at_tick FROM actors
+WHERE next_ready_tick <= $1::BIGINT
+ORDER BY next_ready_tick, entity_id
+LIMIT $2
+`
+
+type ListReadyActorsParams struct {
+	Tick    int64
+	MaxRows int32
+}
+
+// The scheduler's query: actors due at or before a tick, soonest first,
+// ties broken by entity_id so every run sees the same order. Served by
+// actors_ready_idx.
+func (q *Queries) ListReadyActors(ctx context.Context, arg ListReadyActorsParams) ([]Actor, error) {
+	rows, err := q.db.Query(ctx, listReadyActors, arg.Tick, arg.MaxRows)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Actor{}
+	for rows.Next() {
+		var i Actor
+		if err := rows.Scan(
+			&i.EntityID,
+			&i.Energy,
+			&i.EnergyRegen,
+			&i.EnergyCap,
+			&i.NextReadyTick,
+			&i.UpdatedAtTick,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markActorReady = `-- name: MarkActorReady :one
+UPDATE actors
+SET next_ready_tick = LEAST(next_ready_tick,
+  CASE
+    WHEN energy >= 0 THEN $1::BIGINT
+    WHEN energy_regen > 0 THEN GREATEST($1::BIGINT,
+      updated_at_tick + (energy_regen - 1 - energy) / energy_regen)
+  END)
+WHERE entity_id = $2
+  AND EXISTS (
+    SELECT 1 FROM entities e
+    WHERE e.id = actors.entity_id AND e.destroyed_at_tick IS NULL
+  )
+RETURNING entity_id, energy, energy_regen, energy_cap, next_ready_tick, updated_at_tick
+`
+
+type MarkActorReadyParams struct {
+	Tick     int64
+	EntityID pgtype.UUID
+}
+
+// Schedules an actor at sqlc.arg(tick), or at the first tick after that
+// its energy is back to zero if it's in debt. An actor in debt that
+// doesn't regenerate stays unscheduled. An actor already scheduled
+// earlier keeps its place (LEAST ignores NULLs). A destroyed entity's
+// actor matches no row, so it can't be put back on the schedule.
+// Mirrors game.Actor.ReadyTick; call it through game.MarkActorReady.
+func (q *Queries) MarkActorReady(ctx context.Context, arg MarkActorReadyParams) (Actor, error) {
+	row := q.db.QueryRow(ctx, markActorReady, arg.Tick, arg.EntityID)
+	var i Actor
+	err := row.Scan(
+		&i.EntityID,
+		&i.Energy,
+		&i.EnergyRegen,
+		&i.EnergyCap,
+		&i.NextReadyTick,
+		&i.UpdatedAtTick,
+	)
+	return i, err
+}
+
+const restoreActor = `-- name: RestoreActor :exec
+INSERT INTO actors (
+  entity_id, energy, energy_regen, energy_cap, next_ready_tick, updated_at_tick
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+`
+
+type RestoreActorParams struct {
+	EntityID      pgtype.UUID
+	Energy        int32
+	EnergyRegen   int32
+	EnergyCap     int32
+	NextReadyTick *int64
+	UpdatedAtTick int64
+}
+
+// Puts back an actors row exactly as archived, for
+// game.RestoreArchivedEntity.
+func (q *Queries) RestoreActor(ctx context.Context, arg RestoreActorParams) error {
+	_, err := q.db.Exec(ctx, restoreActor,
+		arg.EntityID,
+		arg.Energy,
+		arg.EnergyRegen,
+		arg.EnergyCap,
+		arg.NextReadyTick,
+		arg.UpdatedAtTick,
+	)
+	return err
+}
+
+const saveActorEnergy = `-- name: SaveActorEnergy :one
+UPDATE actors
+SET energy = $2,
+    next_ready_tick = $3,
+    updated_at_tick = $4
+WHERE entity_id = $1
+RETURNING entity_id, energy, energy_regen, energy_cap, next_ready_tick, updated_at_tick
+`
+
+type SaveActorEnergyParams struct {
+	EntityID      pgtype.UUID
+	Energy        int32
+	NextReadyTick *int64
+	UpdatedAtTick int64
+}
+
+// Writes back the result of game.Actor.Spend.
+func (q *Queries) SaveActorEnergy(ctx context.Context, arg SaveActorEnergyParams) (Actor, error) {
+	row := q.db.QueryRow(ctx, saveActorEnergy,
+		arg.EntityID,
+		arg.Energy,
+		arg.NextReadyTick,
+		arg.UpdatedAtTick,
+	)
+	var i Actor
+	err := row.Scan(
+		&i.EntityID,
+		&i.Energy,
+		&i.EnergyRegen,
+		&i.EnergyCap,
+		&i.NextReadyTick,
+		&i.UpdatedAtTick,
+	)
+	return i, err
+}
+
+const unscheduleActor = `-- name: UnscheduleActor :execrows
+UPDATE actors
+SET next_ready_tick = NULL
+WHERE entity_id = $1
+  AND next_ready_tick IS NOT NULL
+`
+
+// Takes an actor out of the scheduler until the next MarkActorReady.
+func (q *Queries) UnscheduleActor(ctx context.Context, entityID pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, unscheduleActor, entityID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
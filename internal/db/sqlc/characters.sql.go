@@ -0,0 +1,245 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: characters.sql
+
+package sqlc
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const applyCharacterDamage = `-- name: ApplyCharacterDamage :one
+UPDATE characters
+SET hp = hp - $2
+WHERE entity_id = $1
+  AND died_at_tick IS NULL
+RETURNING entity_id, account_id, season_id, character_name, hp, hp_max, created_at_tick, died_at_tick
+`
+
+type ApplyCharacterDamageParams struct {
+	EntityID pgtype.UUID
+	Amount   int32
+}
+
+// Subtracts $2 from a living character's HP. HP may go to zero or
+// below; the death sweep picks it up from there. No rows means the
+// character is missing or already dead.
+func (q *Queries) ApplyCharacterDamage(ctx context.Context, arg ApplyCharacterDamageParams) (Character, error) {
+	row := q.db.QueryRow(ctx, applyCharacterDamage, arg.EntityID, arg.Amount)
+	var i Character
+	err := row.Scan(
+		&i.EntityID,
+		&i.AccountID,
+		&i.SeasonID,
+		&i.CharacterName,
+		&i.Hp,
+		&i.HpMax,
+		&i.CreatedAtTick,
+		&i.DiedAtTick,
+	)
+	return i, err
+}
+
+const createCharacter = `-- name: CreateCharacter :one
+INSERT INTO characters (
+  entity_id, account_id, season_id, character_name, hp, hp_max, created_at_tick
+) VALUES (
+  $1, $2, $3,
+  $4, $5, $5, $6
+)
+RETURNING entity_id, account_id, season_id, character_name, hp, hp_max, created_at_tick, died_at_tick
+`
+
+type CreateCharacterParams struct {
+	EntityID      pgtype.UUID
+	AccountID     pgtype.UUID
+	SeasonID      int32
+	CharacterName string
+	HpMax         int32
+	CreatedAtTick int64
+}
+
+// Inserts just the characters row. game.CreateCharacter wraps it with
+// the entity, position, and participation writes. Characters start at
+// full HP.
+func (q *Queries) CreateCharacter(ctx context.Context, arg CreateCharacterParams) (Character, error) {
+	row := q.db.QueryRow(ctx, createCharacter,
+		arg.EntityID,
+		arg.AccountID,
+		arg.SeasonID,
+		arg.CharacterName,
+		arg.HpMax,
+		arg.CreatedAtTick,
+	)
+	var i Character
+	err := row.Scan(
+		&i.EntityID,
+		&i.AccountID,
+		&i.SeasonID,
+		&i.CharacterName,
+		&i.Hp,
+		&i.HpMax,
+		&i.CreatedAtTick,
+		&i.DiedAtTick,
+	)
+	return i, err
+}
+
+const getCharacter = `-- name: GetCharacter :one
+SELECT entity_id, account_id, season_id, character_name, hp, hp_max, created_at_tick, died_at_tick FROM characters
+WHERE entity_id = $1
+`
+
+func (q *Queries) GetCharacter(ctx context.Context, entityID pgtype.UUID) (Character, error) {
+	row := q.db.QueryRow(ctx, getCharacter, entityID)
+	var i Character
+	err := row.Scan(
+		&i.EntityID,
+		&i.AccountID,
+		&i.SeasonID,
+		&i.CharacterName,
+		&i.Hp,
+		&i.HpMax,
+		&i.CreatedAtTick,
+		&i.DiedAtTick,
+	)
+	return i, err
+}
+
+const getLivingCharacter = `-- name: GetLivingCharacter :one
+SELECT entity_id, account_id, season_id, character_name, hp, hp_max, created_at_tick, died_at_tick FROM characters
+WHERE account_id = $1
+  AND season_id = $2
+  AND died_at_tick IS NULL
+`
+
+type GetLivingCharacterParams struct {
+	AccountID pgtype.UUID
+	SeasonID  int32
+}
+
+// The account's living character in a season, if any. Served by
+// characters_account_active_idx.
+func (q *Queries) GetLivingCharacter(ctx context.Context, arg GetLivingCharacterParams) (Character, error) {
+	row := q.db.QueryRow(ctx, getLivingCharacter, arg.AccountID, arg.SeasonID)
+	var i Character
+	err := row.Scan(
+		&i.EntityID,
+		&i.AccountID,
+		&i.SeasonID,
+		&i.CharacterName,
+		&i.Hp,
+		&i.HpMax,
+		&i.CreatedAtTick,
+		&i.DiedAtTick,
+	)
+	return i, err
+}
+
+const listDyingCharacters = `-- name: ListDyingCharacters :many
+SELECT entity_id, account_id, season_id, character_name, hp, hp_max, created_at_tick, died_at_tick FROM characters
+WHERE hp <= 0
+  AND died_at_tick IS NULL
+ORDER BY entity_id
+LIMIT $1
+`
+
+// Living characters at or below zero HP: the death sweep's work list,
+// via characters_hp_zero_idx.
+func (q *Queries) ListDyingCharacters(ctx context.Context, limit int32) ([]Character, error) {
+	rows, err := q.db.Query(ctx, listDyingCharacters, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Character{}
+	for rows.Next() {
+		var i Character
+		if err := rows.Scan(
+			&i.EntityID,
+			&i.AccountID,
+			&i.SeasonID,
+			&i.CharacterName,
+			&i.Hp,
+			&i.HpMax,
+			&i.CreatedAtTick,
+			&i.DiedAtTick,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markCharacterDead = `-- name: MarkCharacterDead :one
+UPDATE characters
+SET died_at_tick = $2
+WHERE entity_id = $1
+  AND died_at_tick IS NULL
+RETURNING entity_id, account_id, season_id, character_name, hp, hp_max, created_at_tick, died_at_tick
+`
+
+type MarkCharacterDeadParams struct {
+	EntityID   pgtype.UUID
+	DiedAtTick *int64
+}
+
+// Sets died_at_tick. The caller soft-deletes the entity in the same
+// transaction. No rows means the character is missing or already dead.
+func (q *Queries) MarkCharacterDead(ctx context.Context, arg MarkCharacterDeadParams) (Character, error) {
+	row := q.db.QueryRow(ctx, markCharacterDead, arg.EntityID, arg.DiedAtTick)
+	var i Character
+	err := row.Scan(
+		&i.EntityID,
+		&i.AccountID,
+		&i.SeasonID,
+		&i.CharacterName,
+		&i.Hp,
+		&i.HpMax,
+		&i.CreatedAtTick,
+		&i.DiedAtTick,
+	)
+	return i, err
+}
+
+const restoreCharacter = `-- name: RestoreCharacter :exec
+INSERT INTO characters (
+  entity_id, account_id, season_id, character_name, hp, hp_max, created_at_tick, died_at_tick
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+)
+`
+
+type RestoreCharacterParams struct {
+	EntityID      pgtype.UUID
+	AccountID     pgtype.UUID
+	SeasonID      int32
+	CharacterName string
+	Hp            int32
+	HpMax         int32
+	CreatedAtTick int64
+	DiedAtTick    *int64
+}
+
+// Puts back a characters row exactly as archived, for
+// game.RestoreArchivedEntity.
+func (q *Queries) RestoreCharacter(ctx context.Context, arg RestoreCharacterParams) error {
+	_, err := q.db.Exec(ctx, restoreCharacter,
+		arg.EntityID,
+		arg.AccountID,
+		arg.SeasonID,
+		arg.CharacterName,
+		arg.Hp,
+		arg.HpMax,
+		arg.CreatedAtTick,
+		arg.DiedAtTick,
+	)
+	return err
+}
@@ -11,6 +11,14 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type CopyComponentsParams struct {
+	EntityID      pgtype.UUID
+	ComponentType string
+	State         []byte
+	CreatedAtTick int64
+	UpdatedAtTick int64
+}
+
 const deleteComponent = `-- name: DeleteComponent :exec
 DELETE FROM components
 WHERE entity_id = $1 AND component_type = $2
@@ -26,6 +34,51 @@ func (q *Queries) DeleteComponent(ctx context.Context, arg DeleteComponentParams
 	return err
 }
 
+const deleteComponentsBatch = `-- name: DeleteComponentsBatch :execrows
+DELETE FROM components c
+USING (
+  SELECT
+    unnest($1::UUID[]) AS entity_id,
+    unnest($2::TEXT[]) AS component_type
+) u
+WHERE c.entity_id = u.entity_id
+  AND c.component_type = u.component_type
+`
+
+type DeleteComponentsBatchParams struct {
+	EntityIds      []pgtype.UUID
+	ComponentTypes []string
+}
+
+func (q *Queries) DeleteComponentsBatch(ctx context.Context, arg DeleteComponentsBatchParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteComponentsBatch, arg.EntityIds, arg.ComponentTypes)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteComponentsOfTypes = `-- name: DeleteComponentsOfTypes :execrows
+DELETE FROM components
+WHERE entity_id = $1
+  AND component_type = ANY($2::TEXT[])
+`
+
+type DeleteComponentsOfTypesParams struct {
+	EntityID       pgtype.UUID
+	ComponentTypes []string
+}
+
+// Strips the given component types from one entity; the destroy helper
+// uses it to drop transient components.
+func (q *Queries) DeleteComponentsOfTypes(ctx context.Context, arg DeleteComponentsOfTypesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteComponentsOfTypes, arg.EntityID, arg.ComponentTypes)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const getComponent = `-- name: GetComponent :one
 SELECT entity_id, component_type, state, created_at_tick, updated_at_tick FROM components
 WHERE entity_id = $1 AND component_type = $2
@@ -49,6 +102,156 @@ func (q *Queries) GetComponent(ctx context.Context, arg GetComponentParams) (Com
 	return i, err
 }
 
+const getComponentForUpdate = `-- name: GetComponentForUpdate :one
+SELECT entity_id, component_type, state, created_at_tick, updated_at_tick FROM components
+WHERE entity_id = $1 AND component_type = $2
+FOR UPDATE
+`
+
+type GetComponentForUpdateParams struct {
+	EntityID      pgtype.UUID
+	ComponentType string
+}
+
+// GetComponent plus a row lock, so the state read is the state the
+// caller's following write replaces.
+func (q *Queries) GetComponentForUpdate(ctx context.Context, arg GetComponentForUpdateParams) (Component, error) {
+	row := q.db.QueryRow(ctx, getComponentForUpdate, arg.EntityID, arg.ComponentType)
+	var i Component
+	err := row.Scan(
+		&i.EntityID,
+		&i.ComponentType,
+		&i.State,
+		&i.CreatedAtTick,
+		&i.UpdatedAtTick,
+	)
+	return i, err
+}
+
+const hasComponent = `-- name: HasComponent :one
+SELECT EXISTS (
+  SELECT 1 FROM components
+  WHERE entity_id = $1 AND component_type = $2
+)
+`
+
+type HasComponentParams struct {
+	EntityID      pgtype.UUID
+	ComponentType string
+}
+
+func (q *Queries) HasComponent(ctx context.Context, arg HasComponentParams) (bool, error) {
+	row := q.db.QueryRow(ctx, hasComponent, arg.EntityID, arg.ComponentType)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listComponentsForEntities = `-- name: ListComponentsForEntities :many
+SELECT entity_id, component_type, state
+FROM components
+WHERE entity_id = ANY($1::UUID[])
+  AND component_type = ANY($2::TEXT[])
+ORDER BY entity_id, component_type
+`
+
+type ListComponentsForEntitiesParams struct {
+	EntityIds      []pgtype.UUID
+	ComponentTypes []string
+}
+
+type ListComponentsForEntitiesRow struct {
+	EntityID      pgtype.UUID
+	ComponentType string
+	State         []byte
+}
+
+// The given component types for a page of entities, by PK lookup.
+func (q *Queries) ListComponentsForEntities(ctx context.Context, arg ListComponentsForEntitiesParams) ([]ListComponentsForEntitiesRow, error) {
+	rows, err := q.db.Query(ctx, listComponentsForEntities, arg.EntityIds, arg.ComponentTypes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListComponentsForEntitiesRow{}
+	for rows.Next() {
+		var i ListComponentsForEntitiesRow
+		if err := rows.Scan(&i.EntityID, &i.ComponentType, &i.State); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listComponentsInSeason = `-- name: ListComponentsInSeason :many
+SELECT c.entity_id, c.component_type, c.state, c.created_at_tick, c.updated_at_tick
+FROM components c
+JOIN entities e ON e.id = c.entity_id
+WHERE e.season_id = $1
+  AND e.destroyed_at_tick IS NULL
+ORDER BY c.entity_id, c.component_type
+`
+
+// Every component of every live entity in a season, for the World
+// loader. Seasons are loaded once at startup, so a full pass is fine.
+func (q *Queries) ListComponentsInSeason(ctx context.Context, seasonID int32) ([]Component, error) {
+	rows, err := q.db.Query(ctx, listComponentsInSeason, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Component{}
+	for rows.Next() {
+		var i Component
+		if err := rows.Scan(
+			&i.EntityID,
+			&i.ComponentType,
+			&i.State,
+			&i.CreatedAtTick,
+			&i.UpdatedAtTick,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDistinctComponentTypes = `-- name: ListDistinctComponentTypes :many
+SELECT DISTINCT component_type FROM components
+ORDER BY component_type
+`
+
+// Every component_type present in the table, for the startup check
+// that the binary has a registered Go type for each. Runs once per
+// boot, so a full scan is fine.
+func (q *Queries) ListDistinctComponentTypes(ctx context.Context) ([]string, error) {
+	rows, err := q.db.Query(ctx, listDistinctComponentTypes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var component_type string
+		if err := rows.Scan(&component_type); err != nil {
+			return nil, err
+		}
+		items = append(items, component_type)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listEntitiesWithComponent = `-- name: ListEntitiesWithComponent :many
 SELECT c.entity_id, c.component_type, c.state, c.created_at_tick, c.updated_at_tick
 FROM components c
@@ -87,6 +290,162 @@ func (q *Queries) ListEntitiesWithComponent(ctx context.Context, componentType s
 	return items, nil
 }
 
+const listStaleComponents = `-- name: ListStaleComponents :many
+SELECT entity_id, state FROM components
+WHERE component_type = $1
+  AND entity_id > $2
+  AND (
+    jsonb_typeof(state) <> 'object'
+    OR NOT $3::BOOLEAN
+    OR COALESCE((state->>'_v')::INT, 1) < $4::INT
+  )
+ORDER BY entity_id
+LIMIT $5
+FOR UPDATE
+`
+
+type ListStaleComponentsParams struct {
+	ComponentType string
+	After         pgtype.UUID
+	JsonCodec     bool
+	Version       int32
+	BatchSize     int32
+}
+
+type ListStaleComponentsRow struct {
+	EntityID pgtype.UUID
+	State    []byte
+}
+
+// One keyset page of a type's rows that may need rewriting, locked.
+// JSON objects are filtered on their _v key (missing = version 1).
+// Binary rows keep the version inside a base64 string SQL can't read,
+// so they're always returned, as is every object row when the type has
+// moved off JSON; the caller makes the final call on each. The caller
+// passes the last entity_id it saw; the zero UUID starts from the top.
+func (q *Queries) ListStaleComponents(ctx context.Context, arg ListStaleComponentsParams) ([]ListStaleComponentsRow, error) {
+	rows, err := q.db.Query(ctx, listStaleComponents,
+		arg.ComponentType,
+		arg.After,
+		arg.JsonCodec,
+		arg.Version,
+		arg.BatchSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListStaleComponentsRow{}
+	for rows.Next() {
+		var i ListStaleComponentsRow
+		if err := rows.Scan(&i.EntityID, &i.State); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const queryEntitiesByComponents = `-- name: QueryEntitiesByComponents :many
+SELECT e.id, e.entity_type, e.created_at_tick
+FROM components c
+JOIN entities e ON e.id = c.entity_id
+WHERE c.component_type = $1
+  AND c.entity_id > $2
+  AND e.season_id = $3
+  AND e.destroyed_at_tick IS NULL
+  AND (cardinality($4::TEXT[]) = 0
+       OR e.entity_type = ANY($4::TEXT[]))
+  AND NOT EXISTS (
+    SELECT 1 FROM unnest($5::TEXT[]) AS w(component_type)
+    WHERE NOT EXISTS (
+      SELECT 1 FROM components o
+      WHERE o.entity_id = c.entity_id
+        AND o.component_type = w.component_type
+    )
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM components x
+    WHERE x.entity_id = c.entity_id
+      AND x.component_type = ANY($6::TEXT[])
+  )
+ORDER BY c.entity_id
+LIMIT $7
+`
+
+type QueryEntitiesByComponentsParams struct {
+	LeadType     string
+	After        pgtype.UUID
+	SeasonID     int32
+	EntityTypes  []string
+	WithTypes    []string
+	WithoutTypes []string
+	PageSize     int32
+}
+
+type QueryEntitiesByComponentsRow struct {
+	ID            pgtype.UUID
+	EntityType    string
+	CreatedAtTick int64
+}
+
+// One keyset page of live entities in a season carrying lead_type, every
+// one of with_types and none of without_types, optionally narrowed to
+// some entity types (an empty array means any). The keyset walks
+// components_type_entity_idx for lead_type from the cursor, so LIMIT
+// stops it as soon as the page is full; each candidate's entity row and
+// remaining types are probed by PK.
+func (q *Queries) QueryEntitiesByComponents(ctx context.Context, arg QueryEntitiesByComponentsParams) ([]QueryEntitiesByComponentsRow, error) {
+	rows, err := q.db.Query(ctx, queryEntitiesByComponents,
+		arg.LeadType,
+		arg.After,
+		arg.SeasonID,
+		arg.EntityTypes,
+		arg.WithTypes,
+		arg.WithoutTypes,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []QueryEntitiesByComponentsRow{}
+	for rows.Next() {
+		var i QueryEntitiesByComponentsRow
+		if err := rows.Scan(&i.ID, &i.EntityType, &i.CreatedAtTick); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rewriteComponentState = `-- name: RewriteComponentState :exec
+UPDATE components
+SET state = $3
+WHERE entity_id = $1 AND component_type = $2
+`
+
+type RewriteComponentStateParams struct {
+	EntityID      pgtype.UUID
+	ComponentType string
+	State         []byte
+}
+
+// Replaces stored state without touching updated_at_tick: a schema
+// upgrade isn't a gameplay change, and the broadcast layer shouldn't
+// see one.
+func (q *Queries) RewriteComponentState(ctx context.Context, arg RewriteComponentStateParams) error {
+	_, err := q.db.Exec(ctx, rewriteComponentState, arg.EntityID, arg.ComponentType, arg.State)
+	return err
+}
+
 const setComponent = `-- name: SetComponent :one
 INSERT INTO components (
   entity_id, component_type, state, created_at_tick, updated_at_tick
@@ -129,3 +488,42 @@ func (q *Queries) SetComponent(ctx context.Context, arg SetComponentParams) (Com
 	)
 	return i, err
 }
+
+const upsertComponentsBatch = `-- name: UpsertComponentsBatch :execrows
+INSERT INTO components (entity_id, component_type, state, created_at_tick, updated_at_tick)
+SELECT u.entity_id, u.component_type, u.state, u.tick, u.tick
+FROM (
+  SELECT
+    unnest($1::UUID[]) AS entity_id,
+    unnest($2::TEXT[]) AS component_type,
+    unnest($3::JSONB[]) AS state,
+    unnest($4::BIGINT[]) AS tick
+) u
+JOIN entities e ON e.id = u.entity_id AND e.destroyed_at_tick IS NULL
+ON CONFLICT (entity_id, component_type) DO UPDATE
+SET state = EXCLUDED.state,
+    updated_at_tick = EXCLUDED.updated_at_tick
+`
+
+type UpsertComponentsBatchParams struct {
+	EntityIds      []pgtype.UUID
+	ComponentTypes []string
+	States         [][]byte
+	Ticks          []int64
+}
+
+// Write-behind counterpart to SetComponent, with the same live-entity
+// guard as UpsertEntityPositionsBatch. created_at_tick survives the
+// conflict path, as in SetComponent.
+func (q *Queries) UpsertComponentsBatch(ctx context.Context, arg UpsertComponentsBatchParams) (int64, error) {
+	result, err := q.db.Exec(ctx, upsertComponentsBatch,
+		arg.EntityIds,
+		arg.ComponentTypes,
+		arg.States,
+		arg.Ticks,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,121 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: copyfrom.go
+
+package sqlc
+
+import (
+	"context"
+)
+
+// iteratorForCopyComponents implements pgx.CopyFromSource.
+type iteratorForCopyComponents struct {
+	rows                 []CopyComponentsParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForCopyComponents) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForCopyComponents) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].EntityID,
+		r.rows[0].ComponentType,
+		r.rows[0].State,
+		r.rows[0].CreatedAtTick,
+		r.rows[0].UpdatedAtTick,
+	}, nil
+}
+
+func (r iteratorForCopyComponents) Err() error {
+	return nil
+}
+
+// COPY-based bulk insert for game.CreateEntities.
+func (q *Queries) CopyComponents(ctx context.Context, arg []CopyComponentsParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"components"}, []string{"entity_id", "component_type", "state", "created_at_tick", "updated_at_tick"}, &iteratorForCopyComponents{rows: arg})
+}
+
+// iteratorForCopyEntities implements pgx.CopyFromSource.
+type iteratorForCopyEntities struct {
+	rows                 []CopyEntitiesParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForCopyEntities) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForCopyEntities) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].ID,
+		r.rows[0].SeasonID,
+		r.rows[0].EntityType,
+		r.rows[0].CreatedAtTick,
+	}, nil
+}
+
+func (r iteratorForCopyEntities) Err() error {
+	return nil
+}
+
+// COPY-based bulk insert for game.CreateEntities (world generation).
+func (q *Queries) CopyEntities(ctx context.Context, arg []CopyEntitiesParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"entities"}, []string{"id", "season_id", "entity_type", "created_at_tick"}, &iteratorForCopyEntities{rows: arg})
+}
+
+// iteratorForCopyEntityPositions implements pgx.CopyFromSource.
+type iteratorForCopyEntityPositions struct {
+	rows                 []CopyEntityPositionsParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForCopyEntityPositions) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForCopyEntityPositions) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].EntityID,
+		r.rows[0].RegionID,
+		r.rows[0].X,
+		r.rows[0].Y,
+		r.rows[0].UpdatedAtTick,
+	}, nil
+}
+
+func (r iteratorForCopyEntityPositions) Err() error {
+	return nil
+}
+
+// COPY-based bulk insert for game.CreateEntities. Plain insert, not an
+// upsert: the entities are brand new.
+func (q *Queries) CopyEntityPositions(ctx context.Context, arg []CopyEntityPositionsParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"entity_positions"}, []string{"entity_id", "region_id", "x", "y", "updated_at_tick"}, &iteratorForCopyEntityPositions{rows: arg})
+}
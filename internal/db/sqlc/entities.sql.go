@@ -11,6 +11,49 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type CopyEntitiesParams struct {
+	ID            pgtype.UUID
+	SeasonID      int32
+	EntityType    string
+	CreatedAtTick int64
+}
+
+const countSweepableEntitiesByType = `-- name: CountSweepableEntitiesByType :many
+SELECT entity_type, count(*) AS eligible
+FROM entities
+WHERE destroyed_at_tick IS NOT NULL
+  AND destroyed_at_tick < $1
+GROUP BY entity_type
+ORDER BY entity_type
+`
+
+type CountSweepableEntitiesByTypeRow struct {
+	EntityType string
+	Eligible   int64
+}
+
+// What a sweep at this cutoff would delete, per entity type. Backs the
+// sweep runner's dry run.
+func (q *Queries) CountSweepableEntitiesByType(ctx context.Context, destroyedAtTick *int64) ([]CountSweepableEntitiesByTypeRow, error) {
+	rows, err := q.db.Query(ctx, countSweepableEntitiesByType, destroyedAtTick)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CountSweepableEntitiesByTypeRow{}
+	for rows.Next() {
+		var i CountSweepableEntitiesByTypeRow
+		if err := rows.Scan(&i.EntityType, &i.Eligible); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createEntity = `-- name: CreateEntity :one
 INSERT INTO entities (
   id, season_id, entity_type, created_at_tick
@@ -66,6 +109,41 @@ func (q *Queries) GetEntityByID(ctx context.Context, id pgtype.UUID) (Entity, er
 	return i, err
 }
 
+const insertEntitiesBatch = `-- name: InsertEntitiesBatch :execrows
+INSERT INTO entities (id, season_id, entity_type, created_at_tick)
+SELECT
+  unnest($1::UUID[]),
+  unnest($2::INT[]),
+  unnest($3::TEXT[]),
+  unnest($4::BIGINT[])
+ON CONFLICT (id) DO NOTHING
+`
+
+type InsertEntitiesBatchParams struct {
+	Ids            []pgtype.UUID
+	SeasonIds      []int32
+	EntityTypes    []string
+	CreatedAtTicks []int64
+}
+
+// Write-behind insert for entities created in the in-memory World. One
+// array per column, zipped by parallel unnest() calls (equal lengths
+// zip row by row), so a flush is one statement no matter how many rows.
+// Rows already present are skipped, which makes a retried flush
+// harmless.
+func (q *Queries) InsertEntitiesBatch(ctx context.Context, arg InsertEntitiesBatchParams) (int64, error) {
+	result, err := q.db.Exec(ctx, insertEntitiesBatch,
+		arg.Ids,
+		arg.SeasonIds,
+		arg.EntityTypes,
+		arg.CreatedAtTicks,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const listEntitiesByTypeInSeason = `-- name: ListEntitiesByTypeInSeason :many
 SELECT id, season_id, entity_type, created_at_tick, destroyed_at_tick FROM entities
 WHERE season_id = $1
@@ -106,6 +184,41 @@ func (q *Queries) ListEntitiesByTypeInSeason(ctx context.Context, arg ListEntiti
 	return items, nil
 }
 
+const listLiveEntitiesInSeason = `-- name: ListLiveEntitiesInSeason :many
+SELECT id, season_id, entity_type, created_at_tick, destroyed_at_tick FROM entities
+WHERE season_id = $1
+  AND destroyed_at_tick IS NULL
+ORDER BY id
+`
+
+// Every live entity in a season, for warming the in-memory World.
+// Ordered so loads are reproducible.
+func (q *Queries) ListLiveEntitiesInSeason(ctx context.Context, seasonID int32) ([]Entity, error) {
+	rows, err := q.db.Query(ctx, listLiveEntitiesInSeason, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entity{}
+	for rows.Next() {
+		var i Entity
+		if err := rows.Scan(
+			&i.ID,
+			&i.SeasonID,
+			&i.EntityType,
+			&i.CreatedAtTick,
+			&i.DestroyedAtTick,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const softDeleteEntity = `-- name: SoftDeleteEntity :one
 UPDATE entities
 SET destroyed_at_tick = $2
@@ -119,8 +232,8 @@ type SoftDeleteEntityParams struct {
 }
 
 // Sets destroyed_at_tick. Application code is responsible for also
-// deleting the entity_positions row in the same transaction (the
-// death/destroy helper does this); we don't cascade on soft-delete
+// deleting the entity_positions row in the same transaction
+// (game.DestroyEntity does this); we don't cascade on soft-delete
 // so the audit log (Layer 6) can still inspect the entity's history.
 func (q *Queries) SoftDeleteEntity(ctx context.Context, arg SoftDeleteEntityParams) (Entity, error) {
 	row := q.db.QueryRow(ctx, softDeleteEntity, arg.ID, arg.DestroyedAtTick)
@@ -136,19 +249,123 @@ func (q *Queries) SoftDeleteEntity(ctx context.Context, arg SoftDeleteEntityPara
 }
 
 const sweepDestroyedEntities = `-- name: SweepDestroyedEntities :execrows
+WITH doomed AS (
+  SELECT d.id, d.season_id, d.entity_type, d.created_at_tick, d.destroyed_at_tick FROM entities d
+  WHERE d.destroyed_at_tick IS NOT NULL
+    AND d.destroyed_at_tick < $1
+  FOR UPDATE
+), archived AS (
+  INSERT INTO entity_archive (
+    season_id, entity_id, entity_type, destroyed_at_tick, archived_at_tick, snapshot
+  )
+  SELECT d.season_id, d.id, d.entity_type, d.destroyed_at_tick, $2,
+    jsonb_build_object(
+      'entity', to_jsonb(d),
+      'position', (SELECT to_jsonb(p) FROM entity_positions p WHERE p.entity_id = d.id),
+      'character', (SELECT to_jsonb(ch) FROM characters ch WHERE ch.entity_id = d.id),
+      'actor', (SELECT to_jsonb(ac) FROM actors ac WHERE ac.entity_id = d.id),
+      'components', COALESCE(
+        (SELECT jsonb_agg(to_jsonb(c) ORDER BY c.component_type)
+         FROM components c WHERE c.entity_id = d.id),
+        '[]'::jsonb)
+    )
+  FROM doomed d
+  RETURNING entity_id
+)
 DELETE FROM entities
-WHERE destroyed_at_tick IS NOT NULL
-  AND destroyed_at_tick < $1
+WHERE id IN (SELECT a.entity_id FROM archived a)
 `
 
+type SweepDestroyedEntitiesParams struct {
+	Cutoff         *int64
+	ArchivedAtTick int64
+}
+
 // Hard-deletes entities that have been soft-deleted longer than the
-// retention window. Cascading FKs (entity_positions, components, etc.)
-// carry the deletion through. Returns rows affected so the caller can
-// log/alert. Gated by config in the Go wrapper — see DESIGN.md §6.6.
-func (q *Queries) SweepDestroyedEntities(ctx context.Context, destroyedAtTick *int64) (int64, error) {
-	result, err := q.db.Exec(ctx, sweepDestroyedEntities, destroyedAtTick)
+// retention window, archiving each into entity_archive first — in the
+// same statement, so nothing is deleted without its archive row.
+// Cascading FKs (entity_positions, components, etc.) carry the deletion
+// through. Returns rows affected so the caller can log/alert. Gated by
+// config in the Go wrapper — see DESIGN.md §6.6.
+func (q *Queries) SweepDestroyedEntities(ctx context.Context, arg SweepDestroyedEntitiesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, sweepDestroyedEntities, arg.Cutoff, arg.ArchivedAtTick)
 	if err != nil {
 		return 0, err
 	}
 	return result.RowsAffected(), nil
 }
+
+const sweepDestroyedEntitiesBatch = `-- name: SweepDestroyedEntitiesBatch :execrows
+WITH doomed AS (
+  SELECT d.id, d.season_id, d.entity_type, d.created_at_tick, d.destroyed_at_tick FROM entities d
+  WHERE d.destroyed_at_tick IS NOT NULL
+    AND d.destroyed_at_tick < $1
+  ORDER BY d.destroyed_at_tick
+  LIMIT $2
+  FOR UPDATE SKIP LOCKED
+), archived AS (
+  INSERT INTO entity_archive (
+    season_id, entity_id, entity_type, destroyed_at_tick, archived_at_tick, snapshot
+  )
+  SELECT d.season_id, d.id, d.entity_type, d.destroyed_at_tick, $3,
+    jsonb_build_object(
+      'entity', to_jsonb(d),
+      'position', (SELECT to_jsonb(p) FROM entity_positions p WHERE p.entity_id = d.id),
+      'character', (SELECT to_jsonb(ch) FROM characters ch WHERE ch.entity_id = d.id),
+      'actor', (SELECT to_jsonb(ac) FROM actors ac WHERE ac.entity_id = d.id),
+      'components', COALESCE(
+        (SELECT jsonb_agg(to_jsonb(c) ORDER BY c.component_type)
+         FROM components c WHERE c.entity_id = d.id),
+        '[]'::jsonb)
+    )
+  FROM doomed d
+  RETURNING entity_id
+)
+DELETE FROM entities
+WHERE id IN (SELECT a.entity_id FROM archived a)
+`
+
+type SweepDestroyedEntitiesBatchParams struct {
+	Cutoff         *int64
+	BatchSize      int32
+	ArchivedAtTick int64
+}
+
+// One bounded batch of SweepDestroyedEntities, oldest destructions
+// first so the walk follows entities_destroyed_idx. SKIP LOCKED keeps
+// a sweep from queueing behind a transaction still touching a row; the
+// next batch or run picks it up.
+func (q *Queries) SweepDestroyedEntitiesBatch(ctx context.Context, arg SweepDestroyedEntitiesBatchParams) (int64, error) {
+	result, err := q.db.Exec(ctx, sweepDestroyedEntitiesBatch, arg.Cutoff, arg.BatchSize, arg.ArchivedAtTick)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const undeleteEntity = `-- name: UndeleteEntity :one
+UPDATE entities e
+SET destroyed_at_tick = NULL
+FROM entities prev
+WHERE e.id = $1
+  AND prev.id = e.id
+  AND e.destroyed_at_tick IS NOT NULL
+RETURNING prev.id, prev.season_id, prev.entity_type, prev.created_at_tick, prev.destroyed_at_tick
+`
+
+// Clears destroyed_at_tick. Counterpart to SoftDeleteEntity; the
+// application restores the position row and anything else destroy
+// removed. Returns the row as it was before the update, for the audit
+// trail; no rows means the entity is missing or already live.
+func (q *Queries) UndeleteEntity(ctx context.Context, id pgtype.UUID) (Entity, error) {
+	row := q.db.QueryRow(ctx, undeleteEntity, id)
+	var i Entity
+	err := row.Scan(
+		&i.ID,
+		&i.SeasonID,
+		&i.EntityType,
+		&i.CreatedAtTick,
+		&i.DestroyedAtTick,
+	)
+	return i, err
+}
@@ -0,0 +1,130 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: entity_archive.sql
+
+package sqlc
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const getArchivedEntity = `-- name: GetArchivedEntity :one
+SELECT season_id, entity_id, entity_type, destroyed_at_tick, archived_at_tick, archived_at, snapshot FROM entity_archive
+WHERE season_id = $1 AND entity_id = $2
+`
+
+type GetArchivedEntityParams struct {
+	SeasonID int32
+	EntityID pgtype.UUID
+}
+
+func (q *Queries) GetArchivedEntity(ctx context.Context, arg GetArchivedEntityParams) (EntityArchive, error) {
+	row := q.db.QueryRow(ctx, getArchivedEntity, arg.SeasonID, arg.EntityID)
+	var i EntityArchive
+	err := row.Scan(
+		&i.SeasonID,
+		&i.EntityID,
+		&i.EntityType,
+		&i.DestroyedAtTick,
+		&i.ArchivedAtTick,
+		&i.ArchivedAt,
+		&i.Snapshot,
+	)
+	return i, err
+}
+
+const isEntityArchived = `-- name: IsEntityArchived :one
+SELECT EXISTS (
+  SELECT 1 FROM entity_archive
+  WHERE entity_id = $1
+)
+`
+
+// Whether entity $1 was swept, in any season.
+func (q *Queries) IsEntityArchived(ctx context.Context, entityID pgtype.UUID) (bool, error) {
+	row := q.db.QueryRow(ctx, isEntityArchived, entityID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listArchivedEntities = `-- name: ListArchivedEntities :many
+SELECT season_id, entity_id, entity_type, destroyed_at_tick, archived_at_tick, archived_at, snapshot FROM entity_archive
+WHERE season_id = $1
+  AND destroyed_at_tick BETWEEN $2 AND $3
+ORDER BY destroyed_at_tick, entity_id
+LIMIT $4
+`
+
+type ListArchivedEntitiesParams struct {
+	SeasonID int32
+	FromTick int64
+	ToTick   int64
+	MaxRows  int32
+}
+
+// A season's archived entities destroyed in [from_tick, to_tick], for a
+// moderator reconstructing what happened around an incident.
+func (q *Queries) ListArchivedEntities(ctx context.Context, arg ListArchivedEntitiesParams) ([]EntityArchive, error) {
+	rows, err := q.db.Query(ctx, listArchivedEntities,
+		arg.SeasonID,
+		arg.FromTick,
+		arg.ToTick,
+		arg.MaxRows,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []EntityArchive{}
+	for rows.Next() {
+		var i EntityArchive
+		if err := rows.Scan(
+			&i.SeasonID,
+			&i.EntityID,
+			&i.EntityType,
+			&i.DestroyedAtTick,
+			&i.ArchivedAtTick,
+			&i.ArchivedAt,
+			&i.Snapshot,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const takeArchivedEntity = `-- name: TakeArchivedEntity :one
+DELETE FROM entity_archive
+WHERE season_id = $1 AND entity_id = $2
+RETURNING season_id, entity_id, entity_type, destroyed_at_tick, archived_at_tick, archived_at, snapshot
+`
+
+type TakeArchivedEntityParams struct {
+	SeasonID int32
+	EntityID pgtype.UUID
+}
+
+// Removes and returns an archive row, for restoring it. Deleting up
+// front means two concurrent restores can't both succeed.
+func (q *Queries) TakeArchivedEntity(ctx context.Context, arg TakeArchivedEntityParams) (EntityArchive, error) {
+	row := q.db.QueryRow(ctx, takeArchivedEntity, arg.SeasonID, arg.EntityID)
+	var i EntityArchive
+	err := row.Scan(
+		&i.SeasonID,
+		&i.EntityID,
+		&i.EntityType,
+		&i.DestroyedAtTick,
+		&i.ArchivedAtTick,
+		&i.ArchivedAt,
+		&i.Snapshot,
+	)
+	return i, err
+}
@@ -0,0 +1,65 @@
+package sqlc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+	"github.com/dukerupert/walking-drum/internal/testdb"
+)
+
+// A new season gets its archive partition from the seasons trigger, so
+// the sweep can archive its entities without any setup.
+func TestEntityArchivePartitionPerSeason(t *testing.T) {
+	q, _ := testdb.WithTx(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	if _, err := q.CreateSeason(ctx, sqlc.CreateSeasonParams{
+		ID:        77,
+		WorldSeed: 1,
+		Modifiers: []byte(`{}`),
+		StartsAt:  pgtype.Timestamptz{Time: now, Valid: true},
+		EndsAt:    pgtype.Timestamptz{Time: now.Add(time.Hour), Valid: true},
+	}); err != nil {
+		t.Fatalf("CreateSeason: %v", err)
+	}
+
+	id := newEntityID(t)
+	if _, err := q.CreateEntity(ctx, sqlc.CreateEntityParams{
+		ID: id, SeasonID: 77, EntityType: "item", CreatedAtTick: 1,
+	}); err != nil {
+		t.Fatalf("CreateEntity: %v", err)
+	}
+	destroyed := int64(2)
+	if _, err := q.SoftDeleteEntity(ctx, sqlc.SoftDeleteEntityParams{ID: id, DestroyedAtTick: &destroyed}); err != nil {
+		t.Fatalf("SoftDeleteEntity: %v", err)
+	}
+	cutoff := int64(3)
+	n, err := q.SweepDestroyedEntitiesBatch(ctx, sqlc.SweepDestroyedEntitiesBatchParams{
+		Cutoff: &cutoff, BatchSize: 10, ArchivedAtTick: 3,
+	})
+	if err != nil || n != 1 {
+		t.Fatalf("SweepDestroyedEntitiesBatch: got %d, %v; want 1, nil", n, err)
+	}
+
+	a, err := q.GetArchivedEntity(ctx, sqlc.GetArchivedEntityParams{SeasonID: 77, EntityID: id})
+	if err != nil {
+		t.Fatalf("GetArchivedEntity: %v", err)
+	}
+	if a.EntityType != "item" || a.DestroyedAtTick != 2 || a.ArchivedAtTick != 3 {
+		t.Errorf("archive row: got %+v", a)
+	}
+
+	if _, err := q.TakeArchivedEntity(ctx, sqlc.TakeArchivedEntityParams{SeasonID: 77, EntityID: id}); err != nil {
+		t.Fatalf("TakeArchivedEntity: %v", err)
+	}
+	if _, err := q.GetArchivedEntity(ctx, sqlc.GetArchivedEntityParams{SeasonID: 77, EntityID: id}); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("after take: got %v, want pgx.ErrNoRows", err)
+	}
+}
@@ -11,6 +11,14 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type CopyEntityPositionsParams struct {
+	EntityID      pgtype.UUID
+	RegionID      int32
+	X             int32
+	Y             int32
+	UpdatedAtTick int64
+}
+
 const deleteEntityPosition = `-- name: DeleteEntityPosition :exec
 DELETE FROM entity_positions
 WHERE entity_id = $1
@@ -24,6 +32,19 @@ func (q *Queries) DeleteEntityPosition(ctx context.Context, entityID pgtype.UUID
 	return err
 }
 
+const deleteEntityPositionsBatch = `-- name: DeleteEntityPositionsBatch :execrows
+DELETE FROM entity_positions
+WHERE entity_id = ANY($1::UUID[])
+`
+
+func (q *Queries) DeleteEntityPositionsBatch(ctx context.Context, entityIds []pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteEntityPositionsBatch, entityIds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const getEntitiesAtPosition = `-- name: GetEntitiesAtPosition :many
 SELECT entity_id, region_id, x, y, updated_at_tick FROM entity_positions
 WHERE region_id = $1
@@ -118,6 +139,151 @@ func (q *Queries) GetEntityPosition(ctx context.Context, entityID pgtype.UUID) (
 	return i, err
 }
 
+const listPositionsInSeason = `-- name: ListPositionsInSeason :many
+SELECT p.entity_id, p.region_id, p.x, p.y, p.updated_at_tick
+FROM entity_positions p
+JOIN entities e ON e.id = p.entity_id
+WHERE e.season_id = $1
+  AND e.destroyed_at_tick IS NULL
+ORDER BY p.entity_id
+`
+
+// Positions of every live entity in a season; the World loader's
+// counterpart to ListLiveEntitiesInSeason.
+func (q *Queries) ListPositionsInSeason(ctx context.Context, seasonID int32) ([]EntityPosition, error) {
+	rows, err := q.db.Query(ctx, listPositionsInSeason, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []EntityPosition{}
+	for rows.Next() {
+		var i EntityPosition
+		if err := rows.Scan(
+			&i.EntityID,
+			&i.RegionID,
+			&i.X,
+			&i.Y,
+			&i.UpdatedAtTick,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVisibleEntitiesAtPosition = `-- name: ListVisibleEntitiesAtPosition :many
+SELECT p.entity_id, p.region_id, p.x, p.y, p.updated_at_tick
+FROM entity_positions p
+WHERE p.region_id = $1
+  AND p.x = $2
+  AND p.y = $3
+  AND (
+    $4::BOOLEAN
+    OR p.entity_id = $5
+    OR NOT EXISTS (
+      SELECT 1 FROM components c
+      WHERE c.entity_id = p.entity_id AND c.component_type = 'hidden'
+    )
+  )
+`
+
+type ListVisibleEntitiesAtPositionParams struct {
+	RegionID   int32
+	X          int32
+	Y          int32
+	SeesHidden bool
+	ViewerID   pgtype.UUID
+}
+
+// GetEntitiesAtPosition for a client's eyes: entities carrying the
+// hidden component are left out unless the viewer can see hidden
+// things. A viewer always sees its own entity. Wrapped by
+// game.VisibleAtPosition.
+func (q *Queries) ListVisibleEntitiesAtPosition(ctx context.Context, arg ListVisibleEntitiesAtPositionParams) ([]EntityPosition, error) {
+	rows, err := q.db.Query(ctx, listVisibleEntitiesAtPosition,
+		arg.RegionID,
+		arg.X,
+		arg.Y,
+		arg.SeesHidden,
+		arg.ViewerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []EntityPosition{}
+	for rows.Next() {
+		var i EntityPosition
+		if err := rows.Scan(
+			&i.EntityID,
+			&i.RegionID,
+			&i.X,
+			&i.Y,
+			&i.UpdatedAtTick,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVisibleEntitiesInRegion = `-- name: ListVisibleEntitiesInRegion :many
+SELECT p.entity_id, p.region_id, p.x, p.y, p.updated_at_tick
+FROM entity_positions p
+WHERE p.region_id = $1
+  AND (
+    $2::BOOLEAN
+    OR p.entity_id = $3
+    OR NOT EXISTS (
+      SELECT 1 FROM components c
+      WHERE c.entity_id = p.entity_id AND c.component_type = 'hidden'
+    )
+  )
+`
+
+type ListVisibleEntitiesInRegionParams struct {
+	RegionID   int32
+	SeesHidden bool
+	ViewerID   pgtype.UUID
+}
+
+// GetEntitiesInRegion with the same hidden filter as
+// ListVisibleEntitiesAtPosition.
+func (q *Queries) ListVisibleEntitiesInRegion(ctx context.Context, arg ListVisibleEntitiesInRegionParams) ([]EntityPosition, error) {
+	rows, err := q.db.Query(ctx, listVisibleEntitiesInRegion, arg.RegionID, arg.SeesHidden, arg.ViewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []EntityPosition{}
+	for rows.Next() {
+		var i EntityPosition
+		if err := rows.Scan(
+			&i.EntityID,
+			&i.RegionID,
+			&i.X,
+			&i.Y,
+			&i.UpdatedAtTick,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const setEntityPosition = `-- name: SetEntityPosition :one
 INSERT INTO entity_positions (
   entity_id, region_id, x, y, updated_at_tick
@@ -161,3 +327,47 @@ func (q *Queries) SetEntityPosition(ctx context.Context, arg SetEntityPositionPa
 	)
 	return i, err
 }
+
+const upsertEntityPositionsBatch = `-- name: UpsertEntityPositionsBatch :execrows
+INSERT INTO entity_positions (entity_id, region_id, x, y, updated_at_tick)
+SELECT u.entity_id, u.region_id, u.x, u.y, u.updated_at_tick
+FROM (
+  SELECT
+    unnest($1::UUID[]) AS entity_id,
+    unnest($2::INT[]) AS region_id,
+    unnest($3::INT[]) AS x,
+    unnest($4::INT[]) AS y,
+    unnest($5::BIGINT[]) AS updated_at_tick
+) u
+JOIN entities e ON e.id = u.entity_id AND e.destroyed_at_tick IS NULL
+ON CONFLICT (entity_id) DO UPDATE
+SET region_id = EXCLUDED.region_id,
+    x = EXCLUDED.x,
+    y = EXCLUDED.y,
+    updated_at_tick = EXCLUDED.updated_at_tick
+`
+
+type UpsertEntityPositionsBatchParams struct {
+	EntityIds      []pgtype.UUID
+	RegionIds      []int32
+	Xs             []int32
+	Ys             []int32
+	UpdatedAtTicks []int64
+}
+
+// Write-behind counterpart to SetEntityPosition. Only live entities are
+// written: an entity destroyed (write-through) since the change was
+// queued must not get its position row back.
+func (q *Queries) UpsertEntityPositionsBatch(ctx context.Context, arg UpsertEntityPositionsBatchParams) (int64, error) {
+	result, err := q.db.Exec(ctx, upsertEntityPositionsBatch,
+		arg.EntityIds,
+		arg.RegionIds,
+		arg.Xs,
+		arg.Ys,
+		arg.UpdatedAtTicks,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
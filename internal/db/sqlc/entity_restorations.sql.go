@@ -0,0 +1,108 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: entity_restorations.sql
+
+package sqlc
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const appendEntityRestoration = `-- name: AppendEntityRestoration :one
+INSERT INTO entity_restorations (
+  id, entity_id, season_id, restored_by, reason,
+  destroyed_at_tick, destroy_reason, restored_at_tick, region_id, x, y
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+)
+RETURNING id, entity_id, season_id, restored_by, reason, destroyed_at_tick, destroy_reason, restored_at_tick, region_id, x, y, restored_at
+`
+
+type AppendEntityRestorationParams struct {
+	ID              pgtype.UUID
+	EntityID        pgtype.UUID
+	SeasonID        int32
+	RestoredBy      pgtype.UUID
+	Reason          string
+	DestroyedAtTick int64
+	DestroyReason   *string
+	RestoredAtTick  int64
+	RegionID        *int32
+	X               *int32
+	Y               *int32
+}
+
+// Append-only, like moderation_actions.
+func (q *Queries) AppendEntityRestoration(ctx context.Context, arg AppendEntityRestorationParams) (EntityRestoration, error) {
+	row := q.db.QueryRow(ctx, appendEntityRestoration,
+		arg.ID,
+		arg.EntityID,
+		arg.SeasonID,
+		arg.RestoredBy,
+		arg.Reason,
+		arg.DestroyedAtTick,
+		arg.DestroyReason,
+		arg.RestoredAtTick,
+		arg.RegionID,
+		arg.X,
+		arg.Y,
+	)
+	var i EntityRestoration
+	err := row.Scan(
+		&i.ID,
+		&i.EntityID,
+		&i.SeasonID,
+		&i.RestoredBy,
+		&i.Reason,
+		&i.DestroyedAtTick,
+		&i.DestroyReason,
+		&i.RestoredAtTick,
+		&i.RegionID,
+		&i.X,
+		&i.Y,
+		&i.RestoredAt,
+	)
+	return i, err
+}
+
+const listEntityRestorations = `-- name: ListEntityRestorations :many
+SELECT id, entity_id, season_id, restored_by, reason, destroyed_at_tick, destroy_reason, restored_at_tick, region_id, x, y, restored_at FROM entity_restorations
+WHERE entity_id = $1
+ORDER BY restored_at DESC
+`
+
+func (q *Queries) ListEntityRestorations(ctx context.Context, entityID pgtype.UUID) ([]EntityRestoration, error) {
+	rows, err := q.db.Query(ctx, listEntityRestorations, entityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []EntityRestoration{}
+	for rows.Next() {
+		var i EntityRestoration
+		if err := rows.Scan(
+			&i.ID,
+			&i.EntityID,
+			&i.SeasonID,
+			&i.RestoredBy,
+			&i.Reason,
+			&i.DestroyedAtTick,
+			&i.DestroyReason,
+			&i.RestoredAtTick,
+			&i.RegionID,
+			&i.X,
+			&i.Y,
+			&i.RestoredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,45 @@
+package sqlc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+	"github.com/dukerupert/walking-drum/internal/testdb"
+)
+
+func TestLeaderboardSnapshotIsIdempotent(t *testing.T) {
+	q, _ := testdb.WithTx(t)
+	ctx := context.Background()
+	acc := makeAccount(t, ctx, q, "lb-test@example.com", "LBTest")
+
+	if _, err := q.UpdateSeasonStatus(ctx, sqlc.UpdateSeasonStatusParams{ID: 1, Status: "active"}); err != nil {
+		t.Fatalf("UpdateSeasonStatus: %v", err)
+	}
+	if _, err := q.RecordDeepestRegion(ctx, sqlc.RecordDeepestRegionParams{AccountID: acc.ID, SeasonID: 1, Region: 4}); err != nil {
+		t.Fatalf("RecordDeepestRegion: %v", err)
+	}
+	if _, err := q.RecordLifeLength(ctx, sqlc.RecordLifeLengthParams{AccountID: acc.ID, SeasonID: 1, Ticks: 1200}); err != nil {
+		t.Fatalf("RecordLifeLength: %v", err)
+	}
+
+	// Two boards have a score; most_loot doesn't (loot_recovered is 0).
+	n, err := q.SnapshotLeaderboards(ctx, 1)
+	if err != nil {
+		t.Fatalf("SnapshotLeaderboards: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("first snapshot: got %d rows, want 2", n)
+	}
+	if n, err = q.SnapshotLeaderboards(ctx, 1); err != nil || n != 0 {
+		t.Errorf("second snapshot: got %d rows, err %v; want 0, nil", n, err)
+	}
+
+	rows, err := q.ListLeaderboardSnapshot(ctx, sqlc.ListLeaderboardSnapshotParams{SeasonID: 1, Board: "longest_life", Limit: 10})
+	if err != nil {
+		t.Fatalf("ListLeaderboardSnapshot: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Rank != 1 || rows[0].Score != 1200 || rows[0].DisplayName != "LBTest" {
+		t.Errorf("longest_life snapshot: got %+v", rows)
+	}
+}
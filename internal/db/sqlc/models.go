@@ -31,6 +31,32 @@ type AccountFlag struct {
 	CreatedAt pgtype.Timestamptz
 }
 
+type ActionCost struct {
+	Action    string
+	Cost      int32
+	UpdatedAt pgtype.Timestamptz
+}
+
+type Actor struct {
+	EntityID      pgtype.UUID
+	Energy        int32
+	EnergyRegen   int32
+	EnergyCap     int32
+	NextReadyTick *int64
+	UpdatedAtTick int64
+}
+
+type Character struct {
+	EntityID      pgtype.UUID
+	AccountID     pgtype.UUID
+	SeasonID      int32
+	CharacterName string
+	Hp            int32
+	HpMax         int32
+	CreatedAtTick int64
+	DiedAtTick    *int64
+}
+
 type Component struct {
 	EntityID      pgtype.UUID
 	ComponentType string
@@ -47,6 +73,16 @@ type Entity struct {
 	DestroyedAtTick *int64
 }
 
+type EntityArchive struct {
+	SeasonID        int32
+	EntityID        pgtype.UUID
+	EntityType      string
+	DestroyedAtTick int64
+	ArchivedAtTick  int64
+	ArchivedAt      pgtype.Timestamptz
+	Snapshot        []byte
+}
+
 type EntityPosition struct {
 	EntityID      pgtype.UUID
 	RegionID      int32
@@ -55,6 +91,38 @@ type EntityPosition struct {
 	UpdatedAtTick int64
 }
 
+type EntityRestoration struct {
+	ID              pgtype.UUID
+	EntityID        pgtype.UUID
+	SeasonID        int32
+	RestoredBy      pgtype.UUID
+	Reason          string
+	DestroyedAtTick int64
+	DestroyReason   *string
+	RestoredAtTick  int64
+	RegionID        *int32
+	X               *int32
+	Y               *int32
+	RestoredAt      pgtype.Timestamptz
+}
+
+type LeaderboardScore struct {
+	SeasonID  int32
+	Board     string
+	AccountID pgtype.UUID
+	Score     int64
+}
+
+type LeaderboardSnapshot struct {
+	SeasonID    int32
+	Board       string
+	AccountID   pgtype.UUID
+	Rank        int32
+	Score       int64
+	DisplayName string
+	TakenAt     pgtype.Timestamptz
+}
+
 type ModerationAction struct {
 	ID         pgtype.UUID
 	AccountID  pgtype.UUID
@@ -79,12 +147,24 @@ type Season struct {
 }
 
 type SeasonParticipation struct {
-	AccountID      pgtype.UUID
-	SeasonID       int32
-	CharactersMade int32
-	Deaths         int32
-	DeepestRegion  *int32
-	FinalSummary   []byte
+	AccountID        pgtype.UUID
+	SeasonID         int32
+	CharactersMade   int32
+	Deaths           int32
+	DeepestRegion    *int32
+	FinalSummary     []byte
+	LongestLifeTicks *int64
+	LootRecovered    int64
+}
+
+type SeasonWipe struct {
+	SeasonID    int32
+	Step        string
+	NextSeason  []byte
+	RowsDeleted int64
+	StartedAt   pgtype.Timestamptz
+	UpdatedAt   pgtype.Timestamptz
+	CompletedAt pgtype.Timestamptz
 }
 
 type Session struct {
@@ -0,0 +1,72 @@
+package sqlc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+	"github.com/dukerupert/walking-drum/internal/testdb"
+)
+
+func TestSeasonParticipationCounters(t *testing.T) {
+	q, _ := testdb.WithTx(t)
+	ctx := context.Background()
+	acc := makeAccount(t, ctx, q, "part-test@example.com", "PartTest")
+	key := sqlc.EnsureSeasonParticipationParams{AccountID: acc.ID, SeasonID: 1}
+
+	// Season 1 starts 'upcoming'; the guarded upserts refuse it.
+	if _, err := q.EnsureSeasonParticipation(ctx, key); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("EnsureSeasonParticipation on upcoming season: got %v, want pgx.ErrNoRows", err)
+	}
+	if _, err := q.UpdateSeasonStatus(ctx, sqlc.UpdateSeasonStatusParams{ID: 1, Status: "active"}); err != nil {
+		t.Fatalf("UpdateSeasonStatus: %v", err)
+	}
+
+	for range 2 {
+		if _, err := q.EnsureSeasonParticipation(ctx, key); err != nil {
+			t.Fatalf("EnsureSeasonParticipation: %v", err)
+		}
+	}
+	for range 3 {
+		if _, err := q.IncrementCharactersMade(ctx, sqlc.IncrementCharactersMadeParams(key)); err != nil {
+			t.Fatalf("IncrementCharactersMade: %v", err)
+		}
+	}
+	if _, err := q.IncrementDeaths(ctx, sqlc.IncrementDeathsParams(key)); err != nil {
+		t.Fatalf("IncrementDeaths: %v", err)
+	}
+	for _, region := range []int32{4, 9, 2} {
+		if _, err := q.RecordDeepestRegion(ctx, sqlc.RecordDeepestRegionParams{AccountID: acc.ID, SeasonID: 1, Region: region}); err != nil {
+			t.Fatalf("RecordDeepestRegion(%d): %v", region, err)
+		}
+	}
+
+	p, err := q.GetSeasonParticipation(ctx, sqlc.GetSeasonParticipationParams(key))
+	if err != nil {
+		t.Fatalf("GetSeasonParticipation: %v", err)
+	}
+	if p.CharactersMade != 3 || p.Deaths != 1 {
+		t.Errorf("counters: got %d characters / %d deaths, want 3 / 1", p.CharactersMade, p.Deaths)
+	}
+	if p.DeepestRegion == nil || *p.DeepestRegion != 9 {
+		t.Errorf("deepest_region: got %v, want 9", p.DeepestRegion)
+	}
+
+	if _, err := q.UpdateSeasonStatus(ctx, sqlc.UpdateSeasonStatusParams{ID: 1, Status: "ended"}); err != nil {
+		t.Fatalf("UpdateSeasonStatus: %v", err)
+	}
+	if _, err := q.IncrementDeaths(ctx, sqlc.IncrementDeathsParams(key)); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("IncrementDeaths on ended season: got %v, want pgx.ErrNoRows", err)
+	}
+
+	hist, err := q.ListParticipationForAccount(ctx, acc.ID)
+	if err != nil {
+		t.Fatalf("ListParticipationForAccount: %v", err)
+	}
+	if len(hist) != 1 || hist[0].SeasonID != 1 || hist[0].SeasonStatus != "ended" || hist[0].Deaths != 1 {
+		t.Errorf("history: got %+v", hist)
+	}
+}
@@ -0,0 +1,52 @@
+package sqlc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+	"github.com/dukerupert/walking-drum/internal/testdb"
+)
+
+func TestSeasonWipeProgress(t *testing.T) {
+	q, _ := testdb.WithTx(t)
+	ctx := context.Background()
+
+	w, err := q.CreateSeasonWipe(ctx, sqlc.CreateSeasonWipeParams{
+		SeasonID:   1,
+		NextSeason: []byte(`{"id":2}`),
+	})
+	if err != nil {
+		t.Fatalf("CreateSeasonWipe: %v", err)
+	}
+	if w.Step != "started" || w.CompletedAt.Valid {
+		t.Errorf("fresh wipe: step %q completed_at %v, want started and NULL", w.Step, w.CompletedAt)
+	}
+
+	if err := q.AdvanceSeasonWipe(ctx, sqlc.AdvanceSeasonWipeParams{SeasonID: 1, Step: "bogus"}); err == nil {
+		t.Error("expected CHECK violation for unknown step, got nil")
+	}
+}
+
+func TestSeasonWipeBatchDelete(t *testing.T) {
+	q, _ := testdb.WithTx(t)
+	ctx := context.Background()
+
+	for tick := int64(1); tick <= 3; tick++ {
+		makeLiveEntity(t, ctx, q, tick)
+	}
+	n, err := q.DeleteSeasonEntitiesBatch(ctx, sqlc.DeleteSeasonEntitiesBatchParams{SeasonID: 1, Limit: 2})
+	if err != nil {
+		t.Fatalf("DeleteSeasonEntitiesBatch: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("first batch: got %d, want 2", n)
+	}
+	left, err := q.CountSeasonEntities(ctx, 1)
+	if err != nil {
+		t.Fatalf("CountSeasonEntities: %v", err)
+	}
+	if left != 1 {
+		t.Errorf("entities left: got %d, want 1", left)
+	}
+}
@@ -7,7 +7,53 @@ package sqlc
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createSeason = `-- name: CreateSeason :one
+INSERT INTO seasons (
+  id, name, status, world_seed, modifiers, starts_at, ends_at
+) VALUES (
+  $1, $2, 'upcoming', $3, $4, $5, $6
 )
+RETURNING id, name, status, world_seed, modifiers, starts_at, ends_at, wiped_at, created_at
+`
+
+type CreateSeasonParams struct {
+	ID        int32
+	Name      *string
+	WorldSeed int64
+	Modifiers []byte
+	StartsAt  pgtype.Timestamptz
+	EndsAt    pgtype.Timestamptz
+}
+
+// New seasons always start out upcoming; the lifecycle functions take
+// it from there.
+func (q *Queries) CreateSeason(ctx context.Context, arg CreateSeasonParams) (Season, error) {
+	row := q.db.QueryRow(ctx, createSeason,
+		arg.ID,
+		arg.Name,
+		arg.WorldSeed,
+		arg.Modifiers,
+		arg.StartsAt,
+		arg.EndsAt,
+	)
+	var i Season
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Status,
+		&i.WorldSeed,
+		&i.Modifiers,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.WipedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
 
 const getActiveSeason = `-- name: GetActiveSeason :one
 SELECT id, name, status, world_seed, modifiers, starts_at, ends_at, wiped_at, created_at FROM seasons
@@ -31,6 +77,58 @@ func (q *Queries) GetActiveSeason(ctx context.Context) (Season, error) {
 	return i, err
 }
 
+const getNextUpcomingSeason = `-- name: GetNextUpcomingSeason :one
+SELECT id, name, status, world_seed, modifiers, starts_at, ends_at, wiped_at, created_at FROM seasons
+WHERE status = 'upcoming'
+ORDER BY starts_at, id
+LIMIT 1
+`
+
+// The upcoming season that should open next: earliest start, season
+// number as the tie-break. The scheduler polls this.
+func (q *Queries) GetNextUpcomingSeason(ctx context.Context) (Season, error) {
+	row := q.db.QueryRow(ctx, getNextUpcomingSeason)
+	var i Season
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Status,
+		&i.WorldSeed,
+		&i.Modifiers,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.WipedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPreviousSeason = `-- name: GetPreviousSeason :one
+SELECT id, name, status, world_seed, modifiers, starts_at, ends_at, wiped_at, created_at FROM seasons
+WHERE id < $1
+ORDER BY id DESC
+LIMIT 1
+`
+
+// The season immediately before $1 by number. Activation requires it
+// to be closed; season 1 has no predecessor (pgx.ErrNoRows).
+func (q *Queries) GetPreviousSeason(ctx context.Context, id int32) (Season, error) {
+	row := q.db.QueryRow(ctx, getPreviousSeason, id)
+	var i Season
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Status,
+		&i.WorldSeed,
+		&i.Modifiers,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.WipedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getSeasonByID = `-- name: GetSeasonByID :one
 SELECT id, name, status, world_seed, modifiers, starts_at, ends_at, wiped_at, created_at FROM seasons
 WHERE id = $1
@@ -53,6 +151,126 @@ func (q *Queries) GetSeasonByID(ctx context.Context, id int32) (Season, error) {
 	return i, err
 }
 
+const getSeasonForUpdate = `-- name: GetSeasonForUpdate :one
+SELECT id, name, status, world_seed, modifiers, starts_at, ends_at, wiped_at, created_at FROM seasons
+WHERE id = $1
+FOR UPDATE
+`
+
+// Row-locks the season for the rest of the transaction. Every lifecycle
+// transition goes through this so two operators racing on the same
+// season serialize instead of both passing the precondition checks.
+func (q *Queries) GetSeasonForUpdate(ctx context.Context, id int32) (Season, error) {
+	row := q.db.QueryRow(ctx, getSeasonForUpdate, id)
+	var i Season
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Status,
+		&i.WorldSeed,
+		&i.Modifiers,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.WipedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const markSeasonWiped = `-- name: MarkSeasonWiped :one
+UPDATE seasons
+SET status = 'wiped', wiped_at = $2
+WHERE id = $1
+RETURNING id, name, status, world_seed, modifiers, starts_at, ends_at, wiped_at, created_at
+`
+
+type MarkSeasonWipedParams struct {
+	ID      int32
+	WipedAt pgtype.Timestamptz
+}
+
+// Terminal transition. Sets status and wiped_at together.
+func (q *Queries) MarkSeasonWiped(ctx context.Context, arg MarkSeasonWipedParams) (Season, error) {
+	row := q.db.QueryRow(ctx, markSeasonWiped, arg.ID, arg.WipedAt)
+	var i Season
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Status,
+		&i.WorldSeed,
+		&i.Modifiers,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.WipedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updateSeasonModifiers = `-- name: UpdateSeasonModifiers :one
+UPDATE seasons
+SET modifiers = $2
+WHERE id = $1 AND status IN ('upcoming', 'active')
+RETURNING id, name, status, world_seed, modifiers, starts_at, ends_at, wiped_at, created_at
+`
+
+type UpdateSeasonModifiersParams struct {
+	ID        int32
+	Modifiers []byte
+}
+
+// Modifiers are editable until the season ends; after that they're
+// part of the historical record. Validation happens in Go before this
+// runs — the column is plain JSONB.
+func (q *Queries) UpdateSeasonModifiers(ctx context.Context, arg UpdateSeasonModifiersParams) (Season, error) {
+	row := q.db.QueryRow(ctx, updateSeasonModifiers, arg.ID, arg.Modifiers)
+	var i Season
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Status,
+		&i.WorldSeed,
+		&i.Modifiers,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.WipedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updateSeasonSeed = `-- name: UpdateSeasonSeed :one
+UPDATE seasons
+SET world_seed = $2
+WHERE id = $1 AND status = 'upcoming'
+RETURNING id, name, status, world_seed, modifiers, starts_at, ends_at, wiped_at, created_at
+`
+
+type UpdateSeasonSeedParams struct {
+	ID        int32
+	WorldSeed int64
+}
+
+// Seeds can only change while the season is still upcoming; once the
+// world has been generated from a seed, changing it would orphan the
+// generated content.
+func (q *Queries) UpdateSeasonSeed(ctx context.Context, arg UpdateSeasonSeedParams) (Season, error) {
+	row := q.db.QueryRow(ctx, updateSeasonSeed, arg.ID, arg.WorldSeed)
+	var i Season
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Status,
+		&i.WorldSeed,
+		&i.Modifiers,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.WipedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const updateSeasonStatus = `-- name: UpdateSeasonStatus :one
 UPDATE seasons
 SET status = $2
@@ -65,8 +283,11 @@ type UpdateSeasonStatusParams struct {
 	Status string
 }
 
-// Caller is responsible for transition validity. The unique partial index
-// on (status) WHERE status='active' ensures at most one active season.
+// Raw status write. Application code should go through the season
+// package's transition functions, which validate the move and hold a
+// row lock; this query is the primitive they sit on. The unique partial
+// index on (status) WHERE status='active' ensures at most one active
+// season.
 func (q *Queries) UpdateSeasonStatus(ctx context.Context, arg UpdateSeasonStatusParams) (Season, error) {
 	row := q.db.QueryRow(ctx, updateSeasonStatus, arg.ID, arg.Status)
 	var i Season
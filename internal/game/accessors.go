@@ -0,0 +1,158 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+)
+
+// ErrComponentNotFound is returned by GetComponent when the entity has
+// no component of the requested type.
+var ErrComponentNotFound = errors.New("game: component not found")
+
+// ComponentQuerier is the subset of *sqlc.Queries the typed component
+// accessors need.
+type ComponentQuerier interface {
+	GetComponent(ctx context.Context, arg sqlc.GetComponentParams) (sqlc.Component, error)
+	GetComponentForUpdate(ctx context.Context, arg sqlc.GetComponentForUpdateParams) (sqlc.Component, error)
+	SetComponent(ctx context.Context, arg sqlc.SetComponentParams) (sqlc.Component, error)
+	DeleteComponent(ctx context.Context, arg sqlc.DeleteComponentParams) error
+	HasComponent(ctx context.Context, arg sqlc.HasComponentParams) (bool, error)
+}
+
+var _ ComponentQuerier = (*sqlc.Queries)(nil)
+
+// componentType is T's type string, read off its zero value.
+func componentType[T Component]() string {
+	var zero T
+	return zero.ComponentType()
+}
+
+// GetComponent loads entity id's component of type T. The type
+// parameter is the schema: GetComponent[Hidden](ctx, q, id).
+func GetComponent[T Component](ctx context.Context, q ComponentQuerier, id uuid.UUID) (T, error) {
+	var v T
+	typ := componentType[T]()
+	row, err := q.GetComponent(ctx, sqlc.GetComponentParams{
+		EntityID:      pgtype.UUID{Bytes: id, Valid: true},
+		ComponentType: typ,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return v, fmt.Errorf("entity %s: %s: %w", id, typ, ErrComponentNotFound)
+		}
+		return v, fmt.Errorf("get component %s: %w", typ, err)
+	}
+	if err := decodeState(typ, row.State, &v); err != nil {
+		return v, err
+	}
+	return v, nil
+}
+
+// SetComponent writes c onto entity id, inserting or replacing. tick
+// becomes updated_at_tick, and created_at_tick on first write. c's type
+// must be registered. Inside Bus.InTx the write is recorded as an added
+// or changed event.
+func SetComponent(ctx context.Context, q ComponentQuerier, id uuid.UUID, c Component, tick int64) error {
+	if c == nil {
+		return errors.New("set component: nil component")
+	}
+	if err := checkRegistered(c); err != nil {
+		return err
+	}
+	raw, err := EncodeComponent(c)
+	if err != nil {
+		return err
+	}
+	var old Component
+	if recorderFrom(ctx) != nil {
+		if old, err = previousState(ctx, q, id, c.ComponentType()); err != nil {
+			return fmt.Errorf("set component %s: %w", c.ComponentType(), err)
+		}
+	}
+	if _, err := q.SetComponent(ctx, sqlc.SetComponentParams{
+		EntityID:      pgtype.UUID{Bytes: id, Valid: true},
+		ComponentType: c.ComponentType(),
+		State:         raw,
+		CreatedAtTick: tick,
+		UpdatedAtTick: tick,
+	}); err != nil {
+		return fmt.Errorf("set component %s: %w", c.ComponentType(), err)
+	}
+	kind := ComponentChanged
+	if old == nil {
+		kind = ComponentAdded
+	}
+	record(ctx, ComponentEvent{Kind: kind, EntityID: id, Type: c.ComponentType(), Old: old, New: c, Tick: tick})
+	return nil
+}
+
+// RemoveComponent deletes entity id's component of type T. Removing a
+// component the entity doesn't have is not an error. Inside Bus.InTx
+// removing one it does have is recorded as a removed event, stamped with
+// the transaction's tick.
+func RemoveComponent[T Component](ctx context.Context, q ComponentQuerier, id uuid.UUID) error {
+	typ := componentType[T]()
+	rec := recorderFrom(ctx)
+	var old Component
+	if rec != nil {
+		var err error
+		if old, err = previousState(ctx, q, id, typ); err != nil {
+			return fmt.Errorf("remove component %s: %w", typ, err)
+		}
+	}
+	if err := q.DeleteComponent(ctx, sqlc.DeleteComponentParams{
+		EntityID:      pgtype.UUID{Bytes: id, Valid: true},
+		ComponentType: typ,
+	}); err != nil {
+		return fmt.Errorf("remove component %s: %w", typ, err)
+	}
+	if old != nil {
+		record(ctx, ComponentEvent{Kind: ComponentRemoved, EntityID: id, Type: typ, Old: old, Tick: rec.tick})
+	}
+	return nil
+}
+
+// HasComponent reports whether entity id has a component of type T,
+// without reading or decoding its state. Marker components like Hidden
+// are usually asked about this way.
+func HasComponent[T Component](ctx context.Context, q ComponentQuerier, id uuid.UUID) (bool, error) {
+	typ := componentType[T]()
+	ok, err := q.HasComponent(ctx, sqlc.HasComponentParams{
+		EntityID:      pgtype.UUID{Bytes: id, Valid: true},
+		ComponentType: typ,
+	})
+	if err != nil {
+		return false, fmt.Errorf("has component %s: %w", typ, err)
+	}
+	return ok, nil
+}
+
+// RegistryQuerier is what VerifyComponentRegistry needs.
+type RegistryQuerier interface {
+	ListDistinctComponentTypes(ctx context.Context) ([]string, error)
+}
+
+var _ RegistryQuerier = (*sqlc.Queries)(nil)
+
+// VerifyComponentRegistry checks that every component_type in the
+// database has a registered Go type. Run it at startup: a row this
+// binary can't decode means a deploy went out without a type it needs,
+// and it's better to refuse to start than to fail on first read. The
+// error is an *UnregisteredComponentsError listing the strays.
+func VerifyComponentRegistry(ctx context.Context, q RegistryQuerier) error {
+	types, err := q.ListDistinctComponentTypes(ctx)
+	if err != nil {
+		return fmt.Errorf("list component types: %w", err)
+	}
+	if missing := unregistered(types); len(missing) > 0 {
+		return &UnregisteredComponentsError{Types: missing}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package game_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+	"github.com/dukerupert/walking-drum/internal/game"
+	"github.com/dukerupert/walking-drum/internal/testdb"
+)
+
+type unregisteredComponent struct{}
+
+func (unregisteredComponent) ComponentType() string { return "test_unregistered" }
+
+func TestComponentAccessors(t *testing.T) {
+	_, tx := testdb.WithTx(t)
+	ctx := context.Background()
+	q := sqlc.New(tx)
+
+	id, err := game.CreateEntity(ctx, tx, game.CreateEntityInput{SeasonID: 1, Type: game.EntityNPC, Tick: 1})
+	if err != nil {
+		t.Fatalf("CreateEntity: %v", err)
+	}
+
+	if has, err := game.HasComponent[game.Hidden](ctx, q, id); err != nil || has {
+		t.Fatalf("HasComponent before set: got %v, %v; want false, nil", has, err)
+	}
+	if _, err := game.GetComponent[game.Hidden](ctx, q, id); !errors.Is(err, game.ErrComponentNotFound) {
+		t.Fatalf("GetComponent before set: got %v, want ErrComponentNotFound", err)
+	}
+
+	if err := game.SetComponent(ctx, q, id, game.Hidden{}, 5); err != nil {
+		t.Fatalf("SetComponent: %v", err)
+	}
+	if has, err := game.HasComponent[game.Hidden](ctx, q, id); err != nil || !has {
+		t.Fatalf("HasComponent after set: got %v, %v; want true, nil", has, err)
+	}
+	if _, err := game.GetComponent[game.Hidden](ctx, q, id); err != nil {
+		t.Fatalf("GetComponent: %v", err)
+	}
+	if err := game.VerifyComponentRegistry(ctx, q); err != nil {
+		t.Errorf("VerifyComponentRegistry with only registered types: %v", err)
+	}
+
+	if err := game.RemoveComponent[game.Hidden](ctx, q, id); err != nil {
+		t.Fatalf("RemoveComponent: %v", err)
+	}
+	if has, _ := game.HasComponent[game.Hidden](ctx, q, id); has {
+		t.Error("HasComponent after remove: got true")
+	}
+
+	if err := game.SetComponent(ctx, q, id, unregisteredComponent{}, 6); !errors.Is(err, game.ErrUnknownComponent) {
+		t.Errorf("SetComponent(unregistered): got %v, want ErrUnknownComponent", err)
+	}
+}
+
+func TestVerifyComponentRegistryFindsStrays(t *testing.T) {
+	_, tx := testdb.WithTx(t)
+	ctx := context.Background()
+	q := sqlc.New(tx)
+
+	id, err := game.CreateEntity(ctx, tx, game.CreateEntityInput{SeasonID: 1, Type: game.EntityItem, Tick: 1})
+	if err != nil {
+		t.Fatalf("CreateEntity: %v", err)
+	}
+	// Written behind the registry's back, as an older or newer build
+	// might have.
+	if _, err := q.SetComponent(ctx, sqlc.SetComponentParams{
+		EntityID:      pgtype.UUID{Bytes: id, Valid: true},
+		ComponentType: "ghost",
+		State:         []byte("{}"),
+	}); err != nil {
+		t.Fatalf("SetComponent(raw): %v", err)
+	}
+
+	err = game.VerifyComponentRegistry(ctx, q)
+	var unreg *game.UnregisteredComponentsError
+	if !errors.As(err, &unreg) || len(unreg.Types) != 1 || unreg.Types[0] != "ghost" {
+		t.Errorf("VerifyComponentRegistry: got %v, want ghost reported", err)
+	}
+}
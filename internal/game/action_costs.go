@@ -0,0 +1,186 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync/atomic"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+	"github.com/dukerupert/walking-drum/internal/season"
+)
+
+// Actions seeded into action_costs by migration. The table is the source
+// of truth for which actions exist; these are for callers' convenience.
+const (
+	ActionMove   = "move"
+	ActionAttack = "attack"
+)
+
+// ActionCostsChannel is the Postgres NOTIFY channel the action_costs and
+// seasons triggers signal on.
+const ActionCostsChannel = "action_costs"
+
+var (
+	ErrUnknownAction     = errors.New("game: unknown action")
+	ErrInvalidActionCost = errors.New("game: action cost must be positive")
+)
+
+// ActionCosts answers "what does this action cost right now": the base
+// cost from the action_costs table, overridden by the active season's
+// modifiers (DESIGN.md §2.5). It is safe for concurrent reads; Load
+// swaps in fresh base costs atomically, and the season half is a
+// season.Resolver.
+type ActionCosts struct {
+	season *season.Resolver
+	base   atomic.Pointer[map[string]int32]
+}
+
+// NewActionCosts returns an ActionCosts with no actions, taking season
+// overrides from r. Call Load, or Listen, before use.
+func NewActionCosts(r *season.Resolver) *ActionCosts {
+	c := &ActionCosts{season: r}
+	c.base.Store(&map[string]int32{})
+	return c
+}
+
+// Cost returns action's effective cost: the season's override if it has
+// one, the base cost otherwise. Only actions in the base table exist; a
+// season can retune an action but not invent one, so anything else is
+// ErrUnknownAction.
+func (c *ActionCosts) Cost(action string) (int32, error) {
+	base, ok := (*c.base.Load())[action]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownAction, action)
+	}
+	return c.season.ActionCost(action, base), nil
+}
+
+// Base returns a copy of the base costs, without season overrides.
+func (c *ActionCosts) Base() map[string]int32 {
+	return maps.Clone(*c.base.Load())
+}
+
+// SetBase makes costs the base costs without touching the DB. Every cost
+// must be positive; on error nothing changes.
+func (c *ActionCosts) SetBase(costs map[string]int32) error {
+	if err := validateActionCosts(costs); err != nil {
+		return err
+	}
+	costs = maps.Clone(costs)
+	c.base.Store(&costs)
+	return nil
+}
+
+func validateActionCosts(costs map[string]int32) error {
+	var errs []error
+	for _, action := range slices.Sorted(maps.Keys(costs)) {
+		if action == "" {
+			errs = append(errs, errors.New("empty action name"))
+		}
+		if cost := costs[action]; cost <= 0 {
+			errs = append(errs, fmt.Errorf("%q: cost %d", action, cost))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidActionCost, errors.Join(errs...))
+	}
+	return nil
+}
+
+// ActionCostQuerier is the subset of *sqlc.Queries action cost loads
+// and edits need.
+type ActionCostQuerier interface {
+	GetActiveSeason(ctx context.Context) (sqlc.Season, error)
+	ListActionCosts(ctx context.Context) ([]sqlc.ActionCost, error)
+	SetActionCost(ctx context.Context, arg sqlc.SetActionCostParams) (sqlc.ActionCost, error)
+}
+
+var _ ActionCostQuerier = (*sqlc.Queries)(nil)
+
+// Load reads the base costs and the active season's modifiers. If
+// either read fails, or a stored season override is invalid, the
+// previous costs stay in place.
+func (c *ActionCosts) Load(ctx context.Context, q ActionCostQuerier) error {
+	rows, err := q.ListActionCosts(ctx)
+	if err != nil {
+		return fmt.Errorf("list action costs: %w", err)
+	}
+	costs := make(map[string]int32, len(rows))
+	for _, r := range rows {
+		costs[r.Action] = r.Cost
+	}
+	if err := validateActionCosts(costs); err != nil {
+		return err
+	}
+
+	// Resolver.Load's read, done through q so nothing is stored until
+	// both halves are good.
+	var (
+		seasonID int32
+		mods     season.SeasonModifiers
+	)
+	active, err := q.GetActiveSeason(ctx)
+	switch {
+	case err == nil:
+		if mods, err = season.ParseModifiers(active.Modifiers); err != nil {
+			return fmt.Errorf("season %d: %w", active.ID, err)
+		}
+		seasonID = active.ID
+	case !errors.Is(err, pgx.ErrNoRows):
+		return fmt.Errorf("load active season: %w", err)
+	}
+	c.season.Set(seasonID, mods)
+	c.base.Store(&costs)
+	return nil
+}
+
+// Listen loads c, then reloads it every time ActionCostsChannel fires —
+// an admin retuning a base cost, a season's modifiers changing, a season
+// going active — until ctx is cancelled. It returns nil on
+// cancellation. conn is given over to the LISTEN for as long as Listen
+// runs; take it from the pool with Acquire and hand the *pgx.Conn over.
+//
+// A failed reload goes to onError (nil discards it) and leaves the last
+// good costs in place; Listen carries on. Only an error from the
+// connection itself stops it.
+func (c *ActionCosts) Listen(ctx context.Context, conn *pgx.Conn, onError func(error)) error {
+	if _, err := conn.Exec(ctx, "LISTEN "+ActionCostsChannel); err != nil {
+		return fmt.Errorf("listen %s: %w", ActionCostsChannel, err)
+	}
+	defer func() { _, _ = conn.Exec(context.WithoutCancel(ctx), "UNLISTEN "+ActionCostsChannel) }()
+
+	// Loading after LISTEN means a change committed in between isn't
+	// missed.
+	q := sqlc.New(conn)
+	if err := c.Load(ctx, q); err != nil {
+		return err
+	}
+	for {
+		if _, err := conn.WaitForNotification(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("wait for %s: %w", ActionCostsChannel, err)
+		}
+		if err := c.Load(ctx, q); err != nil && onError != nil {
+			onError(fmt.Errorf("reload action costs: %w", err))
+		}
+	}
+}
+
+// SetActionCost sets action's base cost, creating the action if it's
+// new. Running servers pick it up through Listen.
+func SetActionCost(ctx context.Context, q ActionCostQuerier, action string, cost int32) error {
+	if err := validateActionCosts(map[string]int32{action: cost}); err != nil {
+		return err
+	}
+	if _, err := q.SetActionCost(ctx, sqlc.SetActionCostParams{Action: action, Cost: cost}); err != nil {
+		return fmt.Errorf("set action cost: %w", err)
+	}
+	return nil
+}
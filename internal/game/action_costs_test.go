@@ -0,0 +1,85 @@
+package game_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dukerupert/walking-drum/internal/game"
+	"github.com/dukerupert/walking-drum/internal/season"
+	"github.com/dukerupert/walking-drum/internal/testdb"
+)
+
+func TestActionCosts(t *testing.T) {
+	r := season.NewResolver(season.DefaultModifiers())
+	c := game.NewActionCosts(r)
+	if _, err := c.Cost(game.ActionMove); !errors.Is(err, game.ErrUnknownAction) {
+		t.Fatalf("before any base costs: got %v, want ErrUnknownAction", err)
+	}
+	must(t, c.SetBase(map[string]int32{game.ActionMove: 40, game.ActionAttack: 200}))
+
+	r.Set(2, season.SeasonModifiers{ActionCosts: map[string]int32{game.ActionMove: 80, "teleport": 1}})
+	for action, want := range map[string]int32{game.ActionMove: 80, game.ActionAttack: 200} {
+		if got, err := c.Cost(action); err != nil || got != want {
+			t.Errorf("Cost(%s): got %d, %v, want %d", action, got, err, want)
+		}
+	}
+	if _, err := c.Cost("teleport"); !errors.Is(err, game.ErrUnknownAction) {
+		t.Errorf("season-only action: got %v, want ErrUnknownAction", err)
+	}
+
+	for _, bad := range []map[string]int32{{game.ActionMove: 0}, {game.ActionMove: -40}, {"": 10}} {
+		if err := c.SetBase(bad); !errors.Is(err, game.ErrInvalidActionCost) {
+			t.Errorf("SetBase(%v): got %v, want ErrInvalidActionCost", bad, err)
+		}
+	}
+	if got, _ := c.Cost(game.ActionAttack); got != 200 {
+		t.Errorf("rejected SetBase changed costs: attack %d", got)
+	}
+
+	base := c.Base()
+	base[game.ActionAttack] = 1
+	if got, _ := c.Cost(game.ActionAttack); got != 200 {
+		t.Error("mutating Base() leaked into the resolver")
+	}
+}
+
+func TestActionCosts_Load(t *testing.T) {
+	q, tx := testdb.WithTx(t)
+	ctx := context.Background()
+	c := game.NewActionCosts(season.NewResolver(season.DefaultModifiers()))
+
+	must(t, c.Load(ctx, q))
+	if got, err := c.Cost(game.ActionMove); err != nil || got != 40 {
+		t.Errorf("seeded move: got %d, %v, want 40", got, err)
+	}
+	if got, err := c.Cost(game.ActionAttack); err != nil || got != 200 {
+		t.Errorf("seeded attack: got %d, %v, want 200", got, err)
+	}
+
+	// An admin retunes a base cost and the active season overrides
+	// another; a reload sees both.
+	if err := game.SetActionCost(ctx, q, game.ActionAttack, 0); !errors.Is(err, game.ErrInvalidActionCost) {
+		t.Errorf("zero cost: got %v, want ErrInvalidActionCost", err)
+	}
+	must(t, game.SetActionCost(ctx, q, game.ActionAttack, 150))
+	must(t, game.SetActionCost(ctx, q, "shove", 60))
+	activeSeason1(t, ctx, tx)
+	if _, err := season.SetModifiers(ctx, q, 1, season.SeasonModifiers{
+		ActionCosts: map[string]int32{game.ActionMove: 20},
+	}); err != nil {
+		t.Fatalf("SetModifiers: %v", err)
+	}
+
+	must(t, c.Load(ctx, q))
+	for action, want := range map[string]int32{game.ActionMove: 20, game.ActionAttack: 150, "shove": 60} {
+		if got, err := c.Cost(action); err != nil || got != want {
+			t.Errorf("Cost(%s) after reload: got %d, %v, want %d", action, got, err, want)
+		}
+	}
+
+	// The table itself refuses a non-positive cost.
+	if _, err := tx.Exec(ctx, `UPDATE action_costs SET cost = 0 WHERE action = 'move'`); err == nil {
+		t.Error("action_costs accepted a zero cost")
+	}
+}
@@ -0,0 +1,214 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+)
+
+// Energy stats CreateCharacter gives a character unless told otherwise:
+// a move (40) every four ticks, and a full bar banks two and a half.
+const (
+	DefaultCharacterEnergyCap   = 100
+	DefaultCharacterEnergyRegen = 10
+)
+
+var (
+	ErrActorNotFound     = errors.New("game: actor not found")
+	ErrActorNotReady     = errors.New("game: actor not ready")
+	ErrInvalidEnergyCost = errors.New("game: energy cost must not be negative")
+)
+
+// Actor is the actors row for an entity that acts over time. See
+// DESIGN.md §2.5 and §7.3.
+//
+// Energy is as of UpdatedAtTick; EnergyAt gives the current value.
+// Energy is negative while the actor is paying off an action that cost
+// more than it had, and the actor can act again once it's back to zero.
+// NextReadyTick is nil when the actor isn't scheduled.
+type Actor struct {
+	EntityID      uuid.UUID
+	Energy        int32
+	EnergyRegen   int32
+	EnergyCap     int32
+	NextReadyTick *int64
+	UpdatedAtTick int64
+}
+
+func actorFromRow(r sqlc.Actor) Actor {
+	return Actor{
+		EntityID:      r.EntityID.Bytes,
+		Energy:        r.Energy,
+		EnergyRegen:   r.EnergyRegen,
+		EnergyCap:     r.EnergyCap,
+		NextReadyTick: r.NextReadyTick,
+		UpdatedAtTick: r.UpdatedAtTick,
+	}
+}
+
+// EnergyAt is the actor's energy at tick: the stored energy plus regen
+// for every tick since UpdatedAtTick, never more than EnergyCap. Ticks
+// before UpdatedAtTick regenerate nothing.
+func (a Actor) EnergyAt(tick int64) int32 {
+	limit := int64(a.EnergyCap)
+	e := int64(a.Energy)
+	if elapsed := tick - a.UpdatedAtTick; elapsed > 0 && a.EnergyRegen > 0 && e < limit {
+		// Past the tick the bar fills, only the cap matters; stopping
+		// there keeps the product from overflowing.
+		e += int64(a.EnergyRegen) * min(elapsed, ceilDiv(limit-e, int64(a.EnergyRegen)))
+	}
+	return int32(min(e, limit))
+}
+
+// Ready reports whether the actor has the energy to act at tick.
+func (a Actor) Ready(tick int64) bool { return a.EnergyAt(tick) >= 0 }
+
+// ReadyTick is the first tick at or after from at which the actor can
+// act, or nil if it's in debt and doesn't regenerate. The
+// MarkActorReady query computes the same thing in SQL.
+func (a Actor) ReadyTick(from int64) *int64 {
+	if a.Energy >= 0 {
+		return &from
+	}
+	if a.EnergyRegen <= 0 {
+		return nil
+	}
+	t := max(from, a.UpdatedAtTick+ceilDiv(-int64(a.Energy), int64(a.EnergyRegen)))
+	return &t
+}
+
+// Spend deducts cost from the actor's energy at tick, which may leave it
+// negative, and reschedules the actor for the first later tick it can
+// act again: an actor acts at most once per tick. It fails with
+// ErrActorNotReady if the actor can't act at tick.
+func (a Actor) Spend(cost int32, tick int64) (Actor, error) {
+	if cost < 0 {
+		return a, fmt.Errorf("%w: %d", ErrInvalidEnergyCost, cost)
+	}
+	if tick < a.UpdatedAtTick {
+		return a, fmt.Errorf("spend energy at tick %d: actor updated at tick %d", tick, a.UpdatedAtTick)
+	}
+	e := a.EnergyAt(tick)
+	if e < 0 {
+		return a, fmt.Errorf("actor %s at tick %d (energy %d): %w", a.EntityID, tick, e, ErrActorNotReady)
+	}
+	a.Energy = e - cost
+	a.UpdatedAtTick = tick
+	a.NextReadyTick = a.ReadyTick(tick + 1)
+	return a, nil
+}
+
+func ceilDiv(a, b int64) int64 { return (a + b - 1) / b }
+
+// ActorQuerier is the subset of *sqlc.Queries actor reads and scheduling
+// need.
+type ActorQuerier interface {
+	GetActor(ctx context.Context, entityID pgtype.UUID) (sqlc.Actor, error)
+	MarkActorReady(ctx context.Context, arg sqlc.MarkActorReadyParams) (sqlc.Actor, error)
+	UnscheduleActor(ctx context.Context, entityID pgtype.UUID) (int64, error)
+	ListReadyActors(ctx context.Context, arg sqlc.ListReadyActorsParams) ([]sqlc.Actor, error)
+}
+
+var _ ActorQuerier = (*sqlc.Queries)(nil)
+
+// GetActor loads the actor for entity id.
+func GetActor(ctx context.Context, q ActorQuerier, id uuid.UUID) (Actor, error) {
+	row, err := q.GetActor(ctx, pgtype.UUID{Bytes: id, Valid: true})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Actor{}, fmt.Errorf("actor %s: %w", id, ErrActorNotFound)
+	}
+	if err != nil {
+		return Actor{}, fmt.Errorf("get actor: %w", err)
+	}
+	return actorFromRow(row), nil
+}
+
+// MarkActorReady schedules actor id to act at tick, or as soon after as
+// its energy allows. It is the single way to re-enable scheduling
+// (DESIGN.md §7.3): every path that gives an actor something to do —
+// player input, an NPC noticing something, a projectile spawning — goes
+// through here. An actor already scheduled earlier keeps its slot. A
+// destroyed entity's actor is ErrActorNotFound; it stays off the
+// schedule for good.
+func MarkActorReady(ctx context.Context, q ActorQuerier, id uuid.UUID, tick int64) (Actor, error) {
+	row, err := q.MarkActorReady(ctx, sqlc.MarkActorReadyParams{
+		EntityID: pgtype.UUID{Bytes: id, Valid: true},
+		Tick:     tick,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Actor{}, fmt.Errorf("actor %s: %w", id, ErrActorNotFound)
+	}
+	if err != nil {
+		return Actor{}, fmt.Errorf("mark actor ready: %w", err)
+	}
+	return actorFromRow(row), nil
+}
+
+// UnscheduleActor takes actor id out of the scheduler, for an actor the
+// scheduler picked that has nothing to do. A missing or already idle
+// actor is not an error.
+func UnscheduleActor(ctx context.Context, q ActorQuerier, id uuid.UUID) error {
+	if _, err := q.UnscheduleActor(ctx, pgtype.UUID{Bytes: id, Valid: true}); err != nil {
+		return fmt.Errorf("unschedule actor: %w", err)
+	}
+	return nil
+}
+
+// ReadyActors is the scheduler's question for a tick: up to limit actors
+// due at or before tick, soonest first and then by entity ID, so the
+// same state always yields the same order.
+func ReadyActors(ctx context.Context, q ActorQuerier, tick int64, limit int32) ([]Actor, error) {
+	rows, err := q.ListReadyActors(ctx, sqlc.ListReadyActorsParams{Tick: tick, MaxRows: limit})
+	if err != nil {
+		return nil, fmt.Errorf("list ready actors: %w", err)
+	}
+	out := make([]Actor, len(rows))
+	for i, r := range rows {
+		out[i] = actorFromRow(r)
+	}
+	return out, nil
+}
+
+// SpendEnergy charges actor id cost energy for an action at tick and
+// reschedules it, in one transaction (see Actor.Spend).
+func SpendEnergy(ctx context.Context, tb TxBeginner, id uuid.UUID, cost int32, tick int64) (Actor, error) {
+	pgID := pgtype.UUID{Bytes: id, Valid: true}
+
+	tx, err := tb.Begin(ctx)
+	if err != nil {
+		return Actor{}, fmt.Errorf("begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	q := sqlc.New(tx)
+	row, err := q.GetActorForUpdate(ctx, pgID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Actor{}, fmt.Errorf("actor %s: %w", id, ErrActorNotFound)
+	}
+	if err != nil {
+		return Actor{}, fmt.Errorf("get actor: %w", err)
+	}
+	a, err := actorFromRow(row).Spend(cost, tick)
+	if err != nil {
+		return Actor{}, err
+	}
+	if _, err := q.SaveActorEnergy(ctx, sqlc.SaveActorEnergyParams{
+		EntityID:      pgID,
+		Energy:        a.Energy,
+		NextReadyTick: a.NextReadyTick,
+		UpdatedAtTick: a.UpdatedAtTick,
+	}); err != nil {
+		return Actor{}, fmt.Errorf("save actor energy: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Actor{}, fmt.Errorf("commit: %w", err)
+	}
+	return a, nil
+}
@@ -0,0 +1,206 @@
+package game_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/quick"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+	"github.com/dukerupert/walking-drum/internal/game"
+	"github.com/dukerupert/walking-drum/internal/testdb"
+)
+
+// arbitraryActor builds a valid actor (regen >= 0, cap > 0) from
+// unconstrained quick inputs.
+func arbitraryActor(energy, regen, energyCap int32, updated int64) game.Actor {
+	return game.Actor{
+		Energy:        energy,
+		EnergyRegen:   max(regen, -regen, 0),
+		EnergyCap:     max(energyCap, -energyCap, 1),
+		UpdatedAtTick: updated,
+	}
+}
+
+func TestActor_EnergyNeverExceedsCap(t *testing.T) {
+	f := func(energy, regen, energyCap int32, updated, tick int64) bool {
+		a := arbitraryActor(energy, regen, energyCap, updated)
+		return a.EnergyAt(tick) <= a.EnergyCap
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestActor_EnergyMonotonic(t *testing.T) {
+	f := func(energy, regen, energyCap int32, updated int64, d1, d2 uint16) bool {
+		a := arbitraryActor(min(energy, 1<<20), regen, energyCap, updated)
+		t1 := a.UpdatedAtTick + int64(d1)
+		t2 := t1 + int64(d2)
+		e1, e2 := a.EnergyAt(t1), a.EnergyAt(t2)
+		// Regen never drains, and below the cap it's exact.
+		want := min(int64(a.Energy)+int64(a.EnergyRegen)*int64(d1), int64(a.EnergyCap))
+		return e2 >= e1 && int64(e1) == want
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// Any sequence of actions, each taken as soon as the actor is ready,
+// keeps energy within [-cost, cap] and schedules the actor for exactly
+// the first tick it's ready again.
+func TestActor_SpendSequence(t *testing.T) {
+	f := func(regen, energyCap uint8, costs []uint8) bool {
+		a := game.Actor{EnergyRegen: int32(regen), EnergyCap: int32(energyCap) + 1}
+		a.Energy = a.EnergyCap
+		tick := int64(0)
+		for _, c := range costs {
+			var err error
+			a, err = a.Spend(int32(c), tick)
+			if err != nil {
+				return false
+			}
+			if a.Energy > a.EnergyCap || a.Energy < -int32(c) {
+				return false
+			}
+			if a.NextReadyTick == nil {
+				return a.EnergyRegen == 0 && a.Energy < 0
+			}
+			next := *a.NextReadyTick
+			if next <= tick || !a.Ready(next) || (next > tick+1 && a.Ready(next-1)) {
+				return false
+			}
+			tick = next
+		}
+		return true
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestActor_Spend(t *testing.T) {
+	a := game.Actor{Energy: 100, EnergyRegen: 10, EnergyCap: 100}
+
+	a, err := a.Spend(200, 5)
+	must(t, err)
+	if a.Energy != -100 || a.UpdatedAtTick != 5 || a.NextReadyTick == nil || *a.NextReadyTick != 15 {
+		t.Errorf("after attack: got %+v, want energy -100, ready at 15", a)
+	}
+	if _, err := a.Spend(40, 14); !errors.Is(err, game.ErrActorNotReady) {
+		t.Errorf("spend in debt: got %v, want ErrActorNotReady", err)
+	}
+	if _, err := a.Spend(-1, 15); !errors.Is(err, game.ErrInvalidEnergyCost) {
+		t.Errorf("negative cost: got %v, want ErrInvalidEnergyCost", err)
+	}
+	if _, err := a.Spend(40, 4); err == nil {
+		t.Error("spend before updated_at_tick: got nil error")
+	}
+
+	a, err = a.Spend(40, 1000)
+	must(t, err)
+	if a.Energy != 60 || *a.NextReadyTick != 1001 {
+		t.Errorf("after rest and move: got %+v, want energy 60, ready at 1001", a)
+	}
+
+	stuck := game.Actor{Energy: 10, EnergyCap: 100}
+	stuck, err = stuck.Spend(40, 0)
+	must(t, err)
+	if stuck.NextReadyTick != nil {
+		t.Errorf("no regen, in debt: scheduled at %d", *stuck.NextReadyTick)
+	}
+}
+
+func TestActorScheduling(t *testing.T) {
+	_, tx := testdb.WithTx(t)
+	ctx := context.Background()
+	q := sqlc.New(tx)
+
+	spawn := func() uuid.UUID {
+		id, err := game.CreateEntity(ctx, tx, game.CreateEntityInput{SeasonID: 1, Type: game.EntityNPC, Tick: 1})
+		must(t, err)
+		if _, err := q.CreateActor(ctx, sqlc.CreateActorParams{
+			EntityID: pgtype.UUID{Bytes: id, Valid: true}, EnergyRegen: 10, EnergyCap: 100, UpdatedAtTick: 1,
+		}); err != nil {
+			t.Fatalf("CreateActor: %v", err)
+		}
+		return id
+	}
+	a, b, c := spawn(), spawn(), spawn()
+
+	if ready, err := game.ReadyActors(ctx, q, 100, 10); err != nil || len(ready) != 0 {
+		t.Fatalf("idle actors scheduled: got %+v, %v", ready, err)
+	}
+
+	// b is due first; a and c tie and come out in entity_id order
+	// (UUIDv7, so creation order).
+	for _, m := range []struct {
+		id   uuid.UUID
+		tick int64
+	}{{a, 5}, {c, 5}, {b, 3}} {
+		_, err := game.MarkActorReady(ctx, q, m.id, m.tick)
+		must(t, err)
+	}
+	// Marking again later doesn't lose b its place.
+	_, err := game.MarkActorReady(ctx, q, b, 4)
+	must(t, err)
+
+	ready, err := game.ReadyActors(ctx, q, 5, 10)
+	must(t, err)
+	if len(ready) != 3 || ready[0].EntityID != b || ready[1].EntityID != a || ready[2].EntityID != c {
+		t.Fatalf("ready order: got %+v, want b, a, c", ready)
+	}
+	if ready, err := game.ReadyActors(ctx, q, 4, 10); err != nil || len(ready) != 1 {
+		t.Errorf("ready at 4: got %+v, %v", ready, err)
+	}
+
+	// Spending reschedules for when energy is back to zero.
+	got, err := game.SpendEnergy(ctx, tx, a, 200, 5)
+	must(t, err)
+	if got.Energy != -100 || got.NextReadyTick == nil || *got.NextReadyTick != 15 {
+		t.Errorf("after SpendEnergy: got %+v", got)
+	}
+	if _, err := game.SpendEnergy(ctx, tx, a, 40, 6); !errors.Is(err, game.ErrActorNotReady) {
+		t.Errorf("spend in debt: got %v, want ErrActorNotReady", err)
+	}
+	// MarkActorReady can't pull an actor in debt forward.
+	got, err = game.MarkActorReady(ctx, q, a, 6)
+	must(t, err)
+	if *got.NextReadyTick != 15 {
+		t.Errorf("mark ready in debt: next_ready_tick %d, want 15", *got.NextReadyTick)
+	}
+
+	must(t, game.UnscheduleActor(ctx, q, c))
+	// A destroyed actor leaves the schedule.
+	must(t, game.DestroyEntity(ctx, tx, game.DestroyEntityInput{ID: b, Tick: 7, Reason: "killed"}))
+	ready, err = game.ReadyActors(ctx, q, 20, 10)
+	must(t, err)
+	if len(ready) != 1 || ready[0].EntityID != a {
+		t.Errorf("ready at 20: got %+v, want just a", ready)
+	}
+	if _, err := game.MarkActorReady(ctx, q, b, 20); !errors.Is(err, game.ErrActorNotFound) {
+		t.Errorf("mark destroyed actor ready: got %v, want ErrActorNotFound", err)
+	}
+
+	// Restoring it puts it back on the schedule.
+	admin, _ := uuid.NewV7()
+	if _, err := q.CreateAccount(ctx, sqlc.CreateAccountParams{
+		ID: pgtype.UUID{Bytes: admin, Valid: true}, Email: "gm@example.com", DisplayName: "gm", PasswordHash: "x",
+	}); err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	must(t, game.RestoreEntity(ctx, tx, game.RestoreEntityInput{ID: b, Tick: 21, AdminID: admin, Reason: "misfire"}))
+	ready, err = game.ReadyActors(ctx, q, 21, 10)
+	must(t, err)
+	if len(ready) != 2 || ready[0].EntityID != a || ready[1].EntityID != b {
+		t.Errorf("ready after restore: got %+v, want a, b", ready)
+	}
+
+	if _, err := game.MarkActorReady(ctx, q, uuid.UUID{15: 1}, 1); !errors.Is(err, game.ErrActorNotFound) {
+		t.Errorf("missing actor: got %v, want ErrActorNotFound", err)
+	}
+}
@@ -0,0 +1,289 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+)
+
+// ErrNotArchived is returned for an entity with no entity_archive row:
+// it was never swept, or has already been restored.
+var ErrNotArchived = errors.New("game: entity not archived")
+
+// ArchivedEntity is a swept entity as it was at the moment the sweep
+// deleted it.
+type ArchivedEntity struct {
+	ID              uuid.UUID
+	SeasonID        int32
+	Type            EntityType
+	CreatedAtTick   int64
+	DestroyedAtTick int64
+	ArchivedAtTick  int64
+	ArchivedAt      time.Time
+
+	// Position is nil for an entity that had left the world, which is
+	// every entity DestroyEntity destroyed.
+	Position *PositionSpec
+
+	// Character is the characters row, for a character entity.
+	Character *Character
+
+	// Actor is the actors row, for an entity that acted.
+	Actor *Actor
+
+	Components []Component // sorted by component type
+}
+
+// archiveSnapshot mirrors entity_archive.snapshot: to_jsonb of the
+// entities row, its entity_positions, characters, and actors rows, and
+// its components rows.
+type archiveSnapshot struct {
+	Entity struct {
+		ID              uuid.UUID `json:"id"`
+		SeasonID        int32     `json:"season_id"`
+		EntityType      string    `json:"entity_type"`
+		CreatedAtTick   int64     `json:"created_at_tick"`
+		DestroyedAtTick *int64    `json:"destroyed_at_tick"`
+	} `json:"entity"`
+	Position *struct {
+		RegionID      int32 `json:"region_id"`
+		X             int32 `json:"x"`
+		Y             int32 `json:"y"`
+		UpdatedAtTick int64 `json:"updated_at_tick"`
+	} `json:"position"`
+	Character *struct {
+		AccountID     uuid.UUID `json:"account_id"`
+		CharacterName string    `json:"character_name"`
+		HP            int32     `json:"hp"`
+		HPMax         int32     `json:"hp_max"`
+		CreatedAtTick int64     `json:"created_at_tick"`
+		DiedAtTick    *int64    `json:"died_at_tick"`
+	} `json:"character"`
+	Actor *struct {
+		Energy        int32  `json:"energy"`
+		EnergyRegen   int32  `json:"energy_regen"`
+		EnergyCap     int32  `json:"energy_cap"`
+		NextReadyTick *int64 `json:"next_ready_tick"`
+		UpdatedAtTick int64  `json:"updated_at_tick"`
+	} `json:"actor"`
+	Components []struct {
+		ComponentType string          `json:"component_type"`
+		State         json.RawMessage `json:"state"`
+		CreatedAtTick int64           `json:"created_at_tick"`
+		UpdatedAtTick int64           `json:"updated_at_tick"`
+	} `json:"components"`
+}
+
+func parseSnapshot(row sqlc.EntityArchive) (archiveSnapshot, error) {
+	var snap archiveSnapshot
+	if err := json.Unmarshal(row.Snapshot, &snap); err != nil {
+		return snap, fmt.Errorf("archived entity %s: snapshot: %w", uuid.UUID(row.EntityID.Bytes), err)
+	}
+	return snap, nil
+}
+
+// decodeArchived turns an entity_archive row into an ArchivedEntity.
+func decodeArchived(row sqlc.EntityArchive) (ArchivedEntity, error) {
+	snap, err := parseSnapshot(row)
+	if err != nil {
+		return ArchivedEntity{}, err
+	}
+	a := ArchivedEntity{
+		ID:              row.EntityID.Bytes,
+		SeasonID:        row.SeasonID,
+		Type:            EntityType(row.EntityType),
+		CreatedAtTick:   snap.Entity.CreatedAtTick,
+		DestroyedAtTick: row.DestroyedAtTick,
+		ArchivedAtTick:  row.ArchivedAtTick,
+		ArchivedAt:      row.ArchivedAt.Time,
+	}
+	if p := snap.Position; p != nil {
+		a.Position = &PositionSpec{RegionID: p.RegionID, X: p.X, Y: p.Y}
+	}
+	if c := snap.Character; c != nil {
+		a.Character = &Character{
+			EntityID:      a.ID,
+			AccountID:     c.AccountID,
+			SeasonID:      a.SeasonID,
+			Name:          c.CharacterName,
+			HP:            c.HP,
+			HPMax:         c.HPMax,
+			CreatedAtTick: c.CreatedAtTick,
+			DiedAtTick:    c.DiedAtTick,
+		}
+	}
+	if ac := snap.Actor; ac != nil {
+		a.Actor = &Actor{
+			EntityID:      a.ID,
+			Energy:        ac.Energy,
+			EnergyRegen:   ac.EnergyRegen,
+			EnergyCap:     ac.EnergyCap,
+			NextReadyTick: ac.NextReadyTick,
+			UpdatedAtTick: ac.UpdatedAtTick,
+		}
+	}
+	for _, c := range snap.Components {
+		comp, err := DecodeAny(c.ComponentType, c.State)
+		if err != nil {
+			return ArchivedEntity{}, fmt.Errorf("archived entity %s: %w", a.ID, err)
+		}
+		a.Components = append(a.Components, comp)
+	}
+	return a, nil
+}
+
+// ArchiveQuerier is the subset of *sqlc.Queries archive inspection needs.
+type ArchiveQuerier interface {
+	GetArchivedEntity(ctx context.Context, arg sqlc.GetArchivedEntityParams) (sqlc.EntityArchive, error)
+	ListArchivedEntities(ctx context.Context, arg sqlc.ListArchivedEntitiesParams) ([]sqlc.EntityArchive, error)
+}
+
+var _ ArchiveQuerier = (*sqlc.Queries)(nil)
+
+// GetArchivedEntity returns swept entity id's last state, with its
+// components decoded at their current schema version.
+func GetArchivedEntity(ctx context.Context, q ArchiveQuerier, seasonID int32, id uuid.UUID) (ArchivedEntity, error) {
+	row, err := q.GetArchivedEntity(ctx, sqlc.GetArchivedEntityParams{
+		SeasonID: seasonID,
+		EntityID: pgtype.UUID{Bytes: id, Valid: true},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ArchivedEntity{}, fmt.Errorf("entity %s: %w", id, ErrNotArchived)
+	}
+	if err != nil {
+		return ArchivedEntity{}, fmt.Errorf("get archived entity: %w", err)
+	}
+	return decodeArchived(row)
+}
+
+// ListArchivedEntities returns up to limit of the season's archived
+// entities destroyed between fromTick and toTick inclusive, oldest
+// destruction first.
+func ListArchivedEntities(ctx context.Context, q ArchiveQuerier, seasonID int32, fromTick, toTick int64, limit int32) ([]ArchivedEntity, error) {
+	rows, err := q.ListArchivedEntities(ctx, sqlc.ListArchivedEntitiesParams{
+		SeasonID: seasonID,
+		FromTick: fromTick,
+		ToTick:   toTick,
+		MaxRows:  limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list archived entities: %w", err)
+	}
+	out := make([]ArchivedEntity, 0, len(rows))
+	for _, row := range rows {
+		a, err := decodeArchived(row)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+// RestoreArchivedEntity undoes the sweep of entity id: its entities,
+// position, character, actor, and component rows go back exactly as
+// archived, ticks included, and the archive row is removed, all in one
+// transaction.
+//
+// The entity comes back soft-deleted, as it was when swept. Bringing it
+// back into the world is RestoreEntity's job, and should follow
+// promptly: once its retention window has passed, the next sweep
+// archives it again.
+func RestoreArchivedEntity(ctx context.Context, tb TxBeginner, seasonID int32, id uuid.UUID) error {
+	pgID := pgtype.UUID{Bytes: id, Valid: true}
+
+	tx, err := tb.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	q := sqlc.New(tx)
+	row, err := q.TakeArchivedEntity(ctx, sqlc.TakeArchivedEntityParams{SeasonID: seasonID, EntityID: pgID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("entity %s: %w", id, ErrNotArchived)
+	}
+	if err != nil {
+		return fmt.Errorf("take archived entity: %w", err)
+	}
+	snap, err := parseSnapshot(row)
+	if err != nil {
+		return err
+	}
+
+	if _, err := q.CreateEntity(ctx, sqlc.CreateEntityParams{
+		ID:            pgID,
+		SeasonID:      row.SeasonID,
+		EntityType:    row.EntityType,
+		CreatedAtTick: snap.Entity.CreatedAtTick,
+	}); err != nil {
+		return fmt.Errorf("restore entity: %w", err)
+	}
+	if _, err := q.SoftDeleteEntity(ctx, sqlc.SoftDeleteEntityParams{
+		ID:              pgID,
+		DestroyedAtTick: &row.DestroyedAtTick,
+	}); err != nil {
+		return fmt.Errorf("restore destroyed_at_tick: %w", err)
+	}
+	if p := snap.Position; p != nil {
+		if _, err := q.SetEntityPosition(ctx, sqlc.SetEntityPositionParams{
+			EntityID:      pgID,
+			RegionID:      p.RegionID,
+			X:             p.X,
+			Y:             p.Y,
+			UpdatedAtTick: p.UpdatedAtTick,
+		}); err != nil {
+			return fmt.Errorf("restore position: %w", err)
+		}
+	}
+	if c := snap.Character; c != nil {
+		if err := q.RestoreCharacter(ctx, sqlc.RestoreCharacterParams{
+			EntityID:      pgID,
+			AccountID:     pgtype.UUID{Bytes: c.AccountID, Valid: true},
+			SeasonID:      row.SeasonID,
+			CharacterName: c.CharacterName,
+			Hp:            c.HP,
+			HpMax:         c.HPMax,
+			CreatedAtTick: c.CreatedAtTick,
+			DiedAtTick:    c.DiedAtTick,
+		}); err != nil {
+			return fmt.Errorf("restore character: %w", err)
+		}
+	}
+	if ac := snap.Actor; ac != nil {
+		if err := q.RestoreActor(ctx, sqlc.RestoreActorParams{
+			EntityID:      pgID,
+			Energy:        ac.Energy,
+			EnergyRegen:   ac.EnergyRegen,
+			EnergyCap:     ac.EnergyCap,
+			NextReadyTick: ac.NextReadyTick,
+			UpdatedAtTick: ac.UpdatedAtTick,
+		}); err != nil {
+			return fmt.Errorf("restore actor: %w", err)
+		}
+	}
+	for _, c := range snap.Components {
+		if _, err := q.SetComponent(ctx, sqlc.SetComponentParams{
+			EntityID:      pgID,
+			ComponentType: c.ComponentType,
+			State:         c.State,
+			CreatedAtTick: c.CreatedAtTick,
+			UpdatedAtTick: c.UpdatedAtTick,
+		}); err != nil {
+			return fmt.Errorf("restore component %s: %w", c.ComponentType, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
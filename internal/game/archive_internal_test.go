@@ -0,0 +1,55 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+)
+
+// The snapshot is built by to_jsonb in SQL; this pins the shape the Go
+// side expects, so a renamed column shows up here and not in a restore.
+func TestDecodeArchived(t *testing.T) {
+	id := uuid.UUID{15: 1}
+	row := sqlc.EntityArchive{
+		SeasonID:        1,
+		EntityID:        pgtype.UUID{Bytes: id, Valid: true},
+		EntityType:      "item",
+		DestroyedAtTick: 10,
+		ArchivedAtTick:  100,
+		Snapshot: []byte(`{
+			"entity": {"id": "` + id.String() + `", "season_id": 1, "entity_type": "item",
+			           "created_at_tick": 3, "destroyed_at_tick": 10},
+			"position": {"entity_id": "` + id.String() + `", "region_id": 2, "x": 4, "y": 5, "updated_at_tick": 3},
+			"actor": {"entity_id": "` + id.String() + `", "energy": -20, "energy_regen": 10, "energy_cap": 100,
+			          "next_ready_tick": null, "updated_at_tick": 9},
+			"components": [
+				{"entity_id": "` + id.String() + `", "component_type": "hidden", "state": {},
+				 "created_at_tick": 3, "updated_at_tick": 3}
+			]
+		}`),
+	}
+	a, err := decodeArchived(row)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ID != id || a.CreatedAtTick != 3 || a.DestroyedAtTick != 10 || a.ArchivedAtTick != 100 {
+		t.Errorf("got %+v", a)
+	}
+	if a.Position == nil || *a.Position != (PositionSpec{RegionID: 2, X: 4, Y: 5}) {
+		t.Errorf("position: got %+v", a.Position)
+	}
+	if a.Actor == nil || a.Actor.Energy != -20 || a.Actor.EnergyCap != 100 || a.Actor.NextReadyTick != nil || a.Actor.UpdatedAtTick != 9 {
+		t.Errorf("actor: got %+v", a.Actor)
+	}
+	if len(a.Components) != 1 || a.Components[0] != (Hidden{}) {
+		t.Errorf("components: got %+v", a.Components)
+	}
+
+	row.Snapshot = []byte(`{"entity": {}, "position": null, "components": []}`)
+	if a, err := decodeArchived(row); err != nil || a.Position != nil || a.Actor != nil || len(a.Components) != 0 {
+		t.Errorf("empty snapshot: got %+v, %v", a, err)
+	}
+}
@@ -0,0 +1,79 @@
+package game_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+	"github.com/dukerupert/walking-drum/internal/game"
+	"github.com/dukerupert/walking-drum/internal/testdb"
+)
+
+func TestArchive_SweepArchivesAndRestores(t *testing.T) {
+	_, tx := testdb.WithTx(t)
+	ctx := context.Background()
+	q := sqlc.New(tx)
+
+	id, err := game.CreateEntity(ctx, tx, game.CreateEntityInput{
+		SeasonID:          1,
+		Type:              game.EntityItem,
+		Tick:              3,
+		Position:          &game.PositionSpec{RegionID: 2, X: 4, Y: 5},
+		InitialComponents: []game.Component{game.Hidden{}, game.OnFire{Damage: 2}},
+	})
+	must(t, err)
+	pgID := pgtype.UUID{Bytes: id, Valid: true}
+	destroyed := int64(10)
+	_, err = q.SoftDeleteEntity(ctx, sqlc.SoftDeleteEntityParams{ID: pgID, DestroyedAtTick: &destroyed})
+	must(t, err)
+
+	r := game.NewSweepRunner(tx, game.SweepRunnerConfig{SweepConfig: game.SweepConfig{Enabled: true}})
+	rep, err := r.Run(ctx, 100)
+	must(t, err)
+	if rep.Deleted != 1 {
+		t.Fatalf("swept %d, want 1", rep.Deleted)
+	}
+	if _, err := q.GetEntityByID(ctx, pgID); err == nil {
+		t.Fatal("entity should be hard-deleted")
+	}
+
+	a, err := game.GetArchivedEntity(ctx, q, 1, id)
+	must(t, err)
+	if a.Type != game.EntityItem || a.CreatedAtTick != 3 || a.DestroyedAtTick != 10 || a.ArchivedAtTick != 100 {
+		t.Errorf("archived entity: got %+v", a)
+	}
+	if a.Position == nil || *a.Position != (game.PositionSpec{RegionID: 2, X: 4, Y: 5}) {
+		t.Errorf("archived position: got %+v", a.Position)
+	}
+	if len(a.Components) != 2 || a.Components[0] != (game.Hidden{}) || a.Components[1] != (game.OnFire{Damage: 2}) {
+		t.Errorf("archived components: got %+v", a.Components)
+	}
+
+	list, err := game.ListArchivedEntities(ctx, q, 1, 0, 10, 100)
+	must(t, err)
+	if len(list) != 1 || list[0].ID != id {
+		t.Errorf("ListArchivedEntities: got %+v", list)
+	}
+
+	must(t, game.RestoreArchivedEntity(ctx, tx, 1, id))
+	e, err := q.GetEntityByID(ctx, pgID)
+	must(t, err)
+	if e.DestroyedAtTick == nil || *e.DestroyedAtTick != 10 {
+		t.Errorf("restored destroyed_at_tick: got %v, want 10", e.DestroyedAtTick)
+	}
+	if p, err := q.GetEntityPosition(ctx, pgID); err != nil || p.X != 4 {
+		t.Errorf("restored position: got %+v, %v", p, err)
+	}
+	if fire, err := game.GetComponent[game.OnFire](ctx, q, id); err != nil || fire.Damage != 2 {
+		t.Errorf("restored component: got %+v, %v", fire, err)
+	}
+	if _, err := game.GetArchivedEntity(ctx, q, 1, id); !errors.Is(err, game.ErrNotArchived) {
+		t.Errorf("archive row after restore: got %v, want ErrNotArchived", err)
+	}
+	if err := game.RestoreArchivedEntity(ctx, tx, 1, id); !errors.Is(err, game.ErrNotArchived) {
+		t.Errorf("second restore: got %v, want ErrNotArchived", err)
+	}
+}
@@ -0,0 +1,195 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+	"github.com/dukerupert/walking-drum/internal/season"
+)
+
+// MaxCharacterNameLength bounds character names, in runes.
+const MaxCharacterNameLength = 24
+
+var (
+	ErrCharacterNotFound     = errors.New("game: character not found")
+	ErrCharacterNameTaken    = errors.New("game: character name taken this season")
+	ErrLivingCharacterExists = errors.New("game: account already has a living character this season")
+	ErrInvalidCharacterName  = errors.New("game: invalid character name")
+)
+
+// Character is the characters row for a player-controlled entity. See
+// DESIGN.md §7.2.
+type Character struct {
+	EntityID      uuid.UUID
+	AccountID     uuid.UUID
+	SeasonID      int32
+	Name          string
+	HP, HPMax     int32
+	CreatedAtTick int64
+	DiedAtTick    *int64
+}
+
+// Alive reports whether the character hasn't been marked dead.
+func (c Character) Alive() bool { return c.DiedAtTick == nil }
+
+func characterFromRow(r sqlc.Character) Character {
+	return Character{
+		EntityID:      r.EntityID.Bytes,
+		AccountID:     r.AccountID.Bytes,
+		SeasonID:      r.SeasonID,
+		Name:          r.CharacterName,
+		HP:            r.Hp,
+		HPMax:         r.HpMax,
+		CreatedAtTick: r.CreatedAtTick,
+		DiedAtTick:    r.DiedAtTick,
+	}
+}
+
+// CreateCharacterInput is the payload for CreateCharacter. Components
+// are optional, and zero energy stats take the DefaultCharacter values;
+// everything else is required.
+type CreateCharacterInput struct {
+	AccountID   uuid.UUID
+	SeasonID    int32
+	Name        string
+	HPMax       int32
+	EnergyRegen int32
+	EnergyCap   int32
+	Tick        int64
+	Position    PositionSpec
+	Components  []Component
+}
+
+// CreateCharacter creates a character in one transaction: the entity and
+// its position and components via CreateEntity, the characters row, an
+// idle actors row at full energy, and a bump to the account's
+// season_participation.characters_made. The season must be active
+// (season.ErrSeasonNotActive otherwise).
+//
+// Names are trimmed, and unique per season ignoring case
+// (ErrCharacterNameTaken). An account has at most one living character
+// per season (ErrLivingCharacterExists). Both are enforced by unique
+// indexes, so concurrent creations can't slip past.
+func CreateCharacter(ctx context.Context, tb TxBeginner, in CreateCharacterInput) (Character, error) {
+	name := strings.TrimSpace(in.Name)
+	if name == "" || utf8.RuneCountInString(name) > MaxCharacterNameLength {
+		return Character{}, fmt.Errorf("%w: %q must be 1 to %d characters", ErrInvalidCharacterName, in.Name, MaxCharacterNameLength)
+	}
+	if in.HPMax <= 0 {
+		return Character{}, fmt.Errorf("create character: hp_max %d must be positive", in.HPMax)
+	}
+	regen, energyCap := in.EnergyRegen, in.EnergyCap
+	if regen == 0 {
+		regen = DefaultCharacterEnergyRegen
+	}
+	if energyCap == 0 {
+		energyCap = DefaultCharacterEnergyCap
+	}
+	if regen < 0 || energyCap < 0 {
+		return Character{}, fmt.Errorf("create character: negative energy regen %d or cap %d", regen, energyCap)
+	}
+
+	tx, err := tb.Begin(ctx)
+	if err != nil {
+		return Character{}, fmt.Errorf("begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	id, err := CreateEntity(ctx, tx, CreateEntityInput{
+		SeasonID:          in.SeasonID,
+		Type:              EntityCharacter,
+		Tick:              in.Tick,
+		Position:          &in.Position,
+		InitialComponents: in.Components,
+	})
+	if err != nil {
+		return Character{}, fmt.Errorf("create character: %w", err)
+	}
+
+	q := sqlc.New(tx)
+	accountID := pgtype.UUID{Bytes: in.AccountID, Valid: true}
+	row, err := q.CreateCharacter(ctx, sqlc.CreateCharacterParams{
+		EntityID:      pgtype.UUID{Bytes: id, Valid: true},
+		AccountID:     accountID,
+		SeasonID:      in.SeasonID,
+		CharacterName: name,
+		HpMax:         in.HPMax,
+		CreatedAtTick: in.Tick,
+	})
+	switch {
+	case isUniqueViolation(err, "characters_season_name_idx"):
+		return Character{}, fmt.Errorf("create character %q: %w", name, ErrCharacterNameTaken)
+	case isUniqueViolation(err, "characters_account_active_idx"):
+		return Character{}, fmt.Errorf("create character: %w", ErrLivingCharacterExists)
+	case err != nil:
+		return Character{}, fmt.Errorf("insert character: %w", err)
+	}
+	if _, err := q.CreateActor(ctx, sqlc.CreateActorParams{
+		EntityID:      pgtype.UUID{Bytes: id, Valid: true},
+		EnergyRegen:   regen,
+		EnergyCap:     energyCap,
+		UpdatedAtTick: in.Tick,
+	}); err != nil {
+		return Character{}, fmt.Errorf("insert actor: %w", err)
+	}
+	if _, err := season.RecordCharacterCreated(ctx, q, accountID, in.SeasonID); err != nil {
+		return Character{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Character{}, fmt.Errorf("commit: %w", err)
+	}
+	return characterFromRow(row), nil
+}
+
+// isUniqueViolation reports whether err is the unique index constraint
+// firing.
+func isUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == constraint
+}
+
+// CharacterQuerier is the subset of *sqlc.Queries character reads need.
+type CharacterQuerier interface {
+	GetCharacter(ctx context.Context, entityID pgtype.UUID) (sqlc.Character, error)
+	GetLivingCharacter(ctx context.Context, arg sqlc.GetLivingCharacterParams) (sqlc.Character, error)
+}
+
+var _ CharacterQuerier = (*sqlc.Queries)(nil)
+
+// GetCharacter loads character id, living or dead.
+func GetCharacter(ctx context.Context, q CharacterQuerier, id uuid.UUID) (Character, error) {
+	row, err := q.GetCharacter(ctx, pgtype.UUID{Bytes: id, Valid: true})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Character{}, fmt.Errorf("character %s: %w", id, ErrCharacterNotFound)
+	}
+	if err != nil {
+		return Character{}, fmt.Errorf("get character: %w", err)
+	}
+	return characterFromRow(row), nil
+}
+
+// GetLivingCharacter returns accountID's living character in seasonID,
+// or ErrCharacterNotFound if it has none.
+func GetLivingCharacter(ctx context.Context, q CharacterQuerier, accountID uuid.UUID, seasonID int32) (Character, error) {
+	row, err := q.GetLivingCharacter(ctx, sqlc.GetLivingCharacterParams{
+		AccountID: pgtype.UUID{Bytes: accountID, Valid: true},
+		SeasonID:  seasonID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Character{}, fmt.Errorf("account %s season %d: %w", accountID, seasonID, ErrCharacterNotFound)
+	}
+	if err != nil {
+		return Character{}, fmt.Errorf("get living character: %w", err)
+	}
+	return characterFromRow(row), nil
+}
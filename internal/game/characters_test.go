@@ -0,0 +1,93 @@
+package game_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+	"github.com/dukerupert/walking-drum/internal/game"
+	"github.com/dukerupert/walking-drum/internal/season"
+	"github.com/dukerupert/walking-drum/internal/testdb"
+)
+
+// activeSeason1 activates season 1 and returns a fresh account to play
+// it with.
+func activeSeason1(t *testing.T, ctx context.Context, tx pgx.Tx) uuid.UUID {
+	t.Helper()
+	q := sqlc.New(tx)
+	if _, err := season.SetSeed(ctx, q, 1, 1); err != nil {
+		t.Fatalf("SetSeed: %v", err)
+	}
+	if _, err := season.Activate(ctx, tx, 1, time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("Activate: %v", err)
+	}
+	acc, _ := uuid.NewV7()
+	if _, err := q.CreateAccount(ctx, sqlc.CreateAccountParams{
+		ID: pgtype.UUID{Bytes: acc, Valid: true}, Email: "player@example.com", DisplayName: "player", PasswordHash: "x",
+	}); err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	return acc
+}
+
+func TestCreateCharacter(t *testing.T) {
+	_, tx := testdb.WithTx(t)
+	ctx := context.Background()
+	q := sqlc.New(tx)
+
+	in := game.CreateCharacterInput{SeasonID: 1, Name: "  Wren ", HPMax: 30, Tick: 5, Position: game.PositionSpec{RegionID: 1}}
+	if _, err := game.CreateCharacter(ctx, tx, in); !errors.Is(err, season.ErrSeasonNotActive) {
+		t.Fatalf("upcoming season: got %v, want ErrSeasonNotActive", err)
+	}
+
+	in.AccountID = activeSeason1(t, ctx, tx)
+	c, err := game.CreateCharacter(ctx, tx, in)
+	must(t, err)
+	if c.Name != "Wren" || c.HP != 30 || c.HPMax != 30 || !c.Alive() {
+		t.Errorf("created: got %+v", c)
+	}
+	if got, err := game.GetLivingCharacter(ctx, q, in.AccountID, 1); err != nil || got.EntityID != c.EntityID {
+		t.Errorf("GetLivingCharacter: got %+v, %v", got, err)
+	}
+	if e, err := q.GetEntityByID(ctx, pgtype.UUID{Bytes: c.EntityID, Valid: true}); err != nil || e.EntityType != string(game.EntityCharacter) {
+		t.Errorf("entity: got %+v, %v", e, err)
+	}
+	if a, err := game.GetActor(ctx, q, c.EntityID); err != nil || a.Energy != game.DefaultCharacterEnergyCap ||
+		a.EnergyRegen != game.DefaultCharacterEnergyRegen || a.NextReadyTick != nil || a.UpdatedAtTick != 5 {
+		t.Errorf("actor: got %+v, %v", a, err)
+	}
+	p, err := q.GetSeasonParticipation(ctx, sqlc.GetSeasonParticipationParams{
+		AccountID: pgtype.UUID{Bytes: in.AccountID, Valid: true}, SeasonID: 1,
+	})
+	must(t, err)
+	if p.CharactersMade != 1 {
+		t.Errorf("characters_made: got %d, want 1", p.CharactersMade)
+	}
+
+	if _, err := game.CreateCharacter(ctx, tx, in); !errors.Is(err, game.ErrLivingCharacterExists) {
+		t.Errorf("second living character: got %v, want ErrLivingCharacterExists", err)
+	}
+	other, _ := uuid.NewV7()
+	if _, err := q.CreateAccount(ctx, sqlc.CreateAccountParams{
+		ID: pgtype.UUID{Bytes: other, Valid: true}, Email: "other@example.com", DisplayName: "other", PasswordHash: "x",
+	}); err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	dup := in
+	dup.AccountID, dup.Name = other, "WREN"
+	if _, err := game.CreateCharacter(ctx, tx, dup); !errors.Is(err, game.ErrCharacterNameTaken) {
+		t.Errorf("duplicate name: got %v, want ErrCharacterNameTaken", err)
+	}
+	for _, name := range []string{"", "   ", "abcdefghijklmnopqrstuvwxy"} {
+		dup.Name = name
+		if _, err := game.CreateCharacter(ctx, tx, dup); !errors.Is(err, game.ErrInvalidCharacterName) {
+			t.Errorf("name %q: got %v, want ErrInvalidCharacterName", name, err)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Codec is one way of turning a component struct into components.state
+// and back. A type picks its codec at registration with WithCodec; JSON
+// is the default. Every codec's output is valid JSON — the column is
+// JSONB — and each marks its format in the bytes themselves, so rows
+// written under an old codec still decode after a type switches.
+type Codec interface {
+	// Name identifies the codec in reports and errors.
+	Name() string
+
+	// Match reports whether state is in this codec's format.
+	Match(state []byte) bool
+
+	// Encode serializes v, a component struct value, stamped with
+	// version.
+	Encode(v any, version int) ([]byte, error)
+
+	// Version reads the schema version state was written at.
+	Version(state []byte) (int, error)
+
+	// Decode deserializes state into into, a pointer to a component
+	// struct. The caller has already checked the version is current.
+	Decode(state []byte, into any) error
+
+	// ToJSON renders older state as the unversioned JSON object
+	// Upgraders take. fields names the struct's fields as they were at
+	// the stored version, for codecs whose bytes don't carry names.
+	ToJSON(state []byte, fields []string) ([]byte, error)
+}
+
+// JSONCodec stores state as a JSON object, with the schema version in
+// versionKey. Readable in psql and queryable with JSONB operators, so
+// it's the right choice for anything that isn't measurably hot.
+var JSONCodec Codec = jsonCodec{}
+
+// codecs are tried in order by detectCodec. JSONCodec is the fallback
+// and so isn't listed.
+var codecs = []Codec{BinaryCodec}
+
+// detectCodec returns the codec state was written with.
+func detectCodec(state []byte) Codec {
+	for _, c := range codecs {
+		if c.Match(state) {
+			return c
+		}
+	}
+	return JSONCodec
+}
+
+// WithCodec stores the type's state with c instead of JSON. Existing
+// rows keep decoding with whatever codec wrote them; MigrateComponents
+// converts them. Codecs that can't represent the type panic here.
+func WithCodec(c Codec) ComponentOption {
+	return func(info *ComponentInfo) {
+		if v, ok := c.(interface{ validate(*ComponentInfo) error }); ok {
+			if err := v.validate(info); err != nil {
+				panic(fmt.Sprintf("game: component %q: %s codec: %v", info.Type, c.Name(), err))
+			}
+		}
+		info.Codec = c
+	}
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string { return "json" }
+
+func (jsonCodec) Match(state []byte) bool {
+	s := bytes.TrimSpace(state)
+	return len(s) > 0 && s[0] == '{'
+}
+
+func (jsonCodec) Encode(v any, version int) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return stampVersion(b, version)
+}
+
+// Version reads versionKey; absent means 1. Payloads that can't contain
+// the key skip the parse, which keeps v1 types' reads at one Unmarshal.
+func (jsonCodec) Version(state []byte) (int, error) {
+	if !bytes.Contains(state, []byte(`"`+versionKey+`"`)) {
+		return 1, nil
+	}
+	var hdr struct {
+		V *int `json:"_v"`
+	}
+	if err := json.Unmarshal(state, &hdr); err != nil {
+		return 0, err
+	}
+	if hdr.V == nil {
+		return 1, nil
+	}
+	if *hdr.V < 1 {
+		return 0, fmt.Errorf("invalid version %d", *hdr.V)
+	}
+	return *hdr.V, nil
+}
+
+// Decode ignores versionKey along with any other unknown field.
+func (jsonCodec) Decode(state []byte, into any) error {
+	return json.Unmarshal(state, into)
+}
+
+func (jsonCodec) ToJSON(state []byte, _ []string) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(state, &fields); err != nil {
+		return nil, err
+	}
+	delete(fields, versionKey)
+	return json.Marshal(fields)
+}
+
+// stampVersion adds versionKey to an encoded object when version > 1.
+func stampVersion(b []byte, version int) ([]byte, error) {
+	if version <= 1 {
+		return b, nil
+	}
+	if len(b) < 2 || b[0] != '{' {
+		return nil, errors.New("versioned component must encode as a JSON object")
+	}
+	out := make([]byte, 0, len(b)+16)
+	out = append(out, `{"`+versionKey+`":`...)
+	out = strconv.AppendInt(out, int64(version), 10)
+	if rest := bytes.TrimSpace(b[1:]); len(rest) > 0 && rest[0] != '}' {
+		out = append(out, ',')
+	}
+	return append(out, b[1:]...), nil
+}
@@ -0,0 +1,524 @@
+package game
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// BinaryCodec stores state positionally: field values in struct order,
+// each behind a one-byte kind tag, integers as varints, and no field
+// names. The bytes are base64'd into a JSON string so the column stays
+// JSONB. It is meant for hot, flat components where row size and decode
+// time matter more than being able to read the row in psql.
+//
+// Supported field types: bool, signed and unsigned integers, float32,
+// float64, string, []byte, byte arrays (UUIDs), and slices of those.
+// Nested structs, maps, pointers and interfaces are rejected at
+// registration. Fields are the exported ones encoding/json would write,
+// named by their json tags.
+//
+// Because names aren't stored, upgrading state written at an older
+// version needs that version's field list: register it with
+// WithBinaryFields before bumping the version.
+var BinaryCodec Codec = binaryCodec{}
+
+// binaryFormat is the first byte of every decoded payload. It's what
+// tells a binary row from a JSON string that happens to be base64.
+const binaryFormat byte = 0xB1
+
+// Kind tags. Bools carry their value in the tag.
+const (
+	kNull byte = iota
+	kFalse
+	kTrue
+	kInt   // zigzag varint
+	kUint  // varint
+	kF32   // 4 bytes little-endian
+	kF64   // 8 bytes little-endian
+	kStr   // uvarint length + bytes
+	kBytes // uvarint length + bytes ([]byte)
+	kFixed // uvarint length + bytes ([N]byte)
+	kList  // uvarint count + tagged values
+)
+
+var errShortBinary = errors.New("truncated binary state")
+
+// WithBinaryFields records the json field names, in struct order, that
+// a BinaryCodec type had at version. Add one when bumping the version of
+// a binary-coded type, listing the fields of the version being retired.
+func WithBinaryFields(version int, names ...string) ComponentOption {
+	return func(info *ComponentInfo) {
+		if info.fieldHistory == nil {
+			info.fieldHistory = map[int][]string{}
+		}
+		info.fieldHistory[version] = names
+	}
+}
+
+type binaryCodec struct{}
+
+func (binaryCodec) Name() string { return "binary" }
+
+func (binaryCodec) Match(state []byte) bool {
+	p, err := binaryPayload(state)
+	return err == nil && len(p) > 0 && p[0] == binaryFormat
+}
+
+// binaryPayload undoes the JSON-string-of-base64 wrapping.
+func binaryPayload(state []byte) ([]byte, error) {
+	s := bytes.TrimSpace(state)
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return nil, errors.New("not a JSON string")
+	}
+	return base64.RawStdEncoding.DecodeString(string(s[1 : len(s)-1]))
+}
+
+func (binaryCodec) Encode(v any, version int) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	fields, err := binaryFieldsOf(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 0, 64)
+	buf = append(buf, binaryFormat)
+	buf = binary.AppendUvarint(buf, uint64(version))
+	buf = binary.AppendUvarint(buf, uint64(len(fields)))
+	for _, f := range fields {
+		buf = appendBinaryValue(buf, rv.Field(f.index))
+	}
+
+	out := make([]byte, 0, base64.RawStdEncoding.EncodedLen(len(buf))+2)
+	out = append(out, '"')
+	out = base64.RawStdEncoding.AppendEncode(out, buf)
+	return append(out, '"'), nil
+}
+
+func (binaryCodec) Version(state []byte) (int, error) {
+	p, err := binaryPayload(state)
+	if err != nil {
+		return 0, err
+	}
+	r := binaryReader{b: p[1:]}
+	v, err := r.uvarint()
+	if err != nil {
+		return 0, err
+	}
+	if v < 1 {
+		return 0, fmt.Errorf("invalid version %d", v)
+	}
+	return int(v), nil
+}
+
+func (binaryCodec) Decode(state []byte, into any) error {
+	p, err := binaryPayload(state)
+	if err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(into)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("decode into non-pointer %T", into)
+	}
+	rv = rv.Elem()
+	fields, err := binaryFieldsOf(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	r := binaryReader{b: p[1:]}
+	if _, err := r.uvarint(); err != nil { // version; checked by the caller
+		return err
+	}
+	n, err := r.uvarint()
+	if err != nil {
+		return err
+	}
+	if int(n) != len(fields) {
+		return fmt.Errorf("state has %d fields, %v has %d", n, rv.Type(), len(fields))
+	}
+	for _, f := range fields {
+		if err := r.decodeInto(rv.Field(f.index)); err != nil {
+			return fmt.Errorf("field %s: %w", f.name, err)
+		}
+	}
+	if len(r.b) != 0 {
+		return fmt.Errorf("%d trailing bytes", len(r.b))
+	}
+	return nil
+}
+
+func (binaryCodec) ToJSON(state []byte, fields []string) ([]byte, error) {
+	if fields == nil {
+		return nil, errors.New("no field names recorded for this version; see WithBinaryFields")
+	}
+	p, err := binaryPayload(state)
+	if err != nil {
+		return nil, err
+	}
+	r := binaryReader{b: p[1:]}
+	if _, err := r.uvarint(); err != nil {
+		return nil, err
+	}
+	n, err := r.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	if int(n) != len(fields) {
+		return nil, fmt.Errorf("state has %d fields, %d names recorded", n, len(fields))
+	}
+	obj := make(map[string]any, n)
+	for _, name := range fields {
+		if obj[name], err = r.decodeAny(); err != nil {
+			return nil, fmt.Errorf("field %s: %w", name, err)
+		}
+	}
+	return json.Marshal(obj)
+}
+
+// validate rejects types the codec can't represent, so a bad
+// WithCodec(BinaryCodec) fails at startup rather than on first write.
+func (binaryCodec) validate(info *ComponentInfo) error {
+	_, err := binaryFieldsOf(info.GoType)
+	return err
+}
+
+type binaryField struct {
+	index int
+	name  string
+}
+
+// binaryLayouts caches binaryFieldsOf per struct type.
+var binaryLayouts sync.Map // reflect.Type → []binaryField
+
+// binaryFieldsOf lists the fields of t the codec writes, in order.
+func binaryFieldsOf(t reflect.Type) ([]binaryField, error) {
+	if cached, ok := binaryLayouts.Load(t); ok {
+		return cached.([]binaryField), nil
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%v is not a struct", t)
+	}
+	var fields []binaryField
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		if err := checkBinaryType(sf.Type); err != nil {
+			return nil, fmt.Errorf("field %s: %w", sf.Name, err)
+		}
+		fields = append(fields, binaryField{index: i, name: name})
+	}
+	binaryLayouts.Store(t, fields)
+	return fields, nil
+}
+
+func checkBinaryType(t reflect.Type) error {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return nil
+	case reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return nil
+		}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return nil
+		}
+		if t.Elem().Kind() != reflect.Slice {
+			return checkBinaryType(t.Elem())
+		}
+	}
+	return fmt.Errorf("unsupported type %v", t)
+}
+
+func appendBinaryValue(buf []byte, v reflect.Value) []byte {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(buf, kTrue)
+		}
+		return append(buf, kFalse)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binary.AppendVarint(append(buf, kInt), v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return binary.AppendUvarint(append(buf, kUint), v.Uint())
+	case reflect.Float32:
+		return binary.LittleEndian.AppendUint32(append(buf, kF32), math.Float32bits(float32(v.Float())))
+	case reflect.Float64:
+		return binary.LittleEndian.AppendUint64(append(buf, kF64), math.Float64bits(v.Float()))
+	case reflect.String:
+		buf = binary.AppendUvarint(append(buf, kStr), uint64(v.Len()))
+		return append(buf, v.String()...)
+	case reflect.Array: // byte arrays only; see checkBinaryType
+		buf = binary.AppendUvarint(append(buf, kFixed), uint64(v.Len()))
+		for i := range v.Len() {
+			buf = append(buf, byte(v.Index(i).Uint()))
+		}
+		return buf
+	case reflect.Slice:
+		if v.IsNil() {
+			return append(buf, kNull)
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			buf = binary.AppendUvarint(append(buf, kBytes), uint64(v.Len()))
+			return append(buf, v.Bytes()...)
+		}
+		buf = binary.AppendUvarint(append(buf, kList), uint64(v.Len()))
+		for i := range v.Len() {
+			buf = appendBinaryValue(buf, v.Index(i))
+		}
+		return buf
+	}
+	panic(fmt.Sprintf("game: binary codec: unsupported kind %v", v.Kind())) // checkBinaryType rules this out
+}
+
+type binaryReader struct {
+	b []byte
+}
+
+func (r *binaryReader) byte() (byte, error) {
+	if len(r.b) == 0 {
+		return 0, errShortBinary
+	}
+	c := r.b[0]
+	r.b = r.b[1:]
+	return c, nil
+}
+
+func (r *binaryReader) uvarint() (uint64, error) {
+	v, n := binary.Uvarint(r.b)
+	if n <= 0 {
+		return 0, errShortBinary
+	}
+	r.b = r.b[n:]
+	return v, nil
+}
+
+func (r *binaryReader) varint() (int64, error) {
+	v, n := binary.Varint(r.b)
+	if n <= 0 {
+		return 0, errShortBinary
+	}
+	r.b = r.b[n:]
+	return v, nil
+}
+
+func (r *binaryReader) take(n uint64) ([]byte, error) {
+	if uint64(len(r.b)) < n {
+		return nil, errShortBinary
+	}
+	out := r.b[:n]
+	r.b = r.b[n:]
+	return out, nil
+}
+
+func (r *binaryReader) fixed(n int) ([]byte, error) { return r.take(uint64(n)) }
+
+// decodeInto reads one tagged value into v, checking the tag fits v's
+// type.
+func (r *binaryReader) decodeInto(v reflect.Value) error {
+	tag, err := r.byte()
+	if err != nil {
+		return err
+	}
+	mismatch := func() error { return fmt.Errorf("kind tag %d doesn't fit %v", tag, v.Type()) }
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if tag != kTrue && tag != kFalse {
+			return mismatch()
+		}
+		v.SetBool(tag == kTrue)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if tag != kInt {
+			return mismatch()
+		}
+		n, err := r.varint()
+		if err != nil {
+			return err
+		}
+		if v.OverflowInt(n) {
+			return fmt.Errorf("%d overflows %v", n, v.Type())
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if tag != kUint {
+			return mismatch()
+		}
+		n, err := r.uvarint()
+		if err != nil {
+			return err
+		}
+		if v.OverflowUint(n) {
+			return fmt.Errorf("%d overflows %v", n, v.Type())
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		switch tag {
+		case kF32:
+			b, err := r.fixed(4)
+			if err != nil {
+				return err
+			}
+			v.SetFloat(float64(math.Float32frombits(binary.LittleEndian.Uint32(b))))
+		case kF64:
+			b, err := r.fixed(8)
+			if err != nil {
+				return err
+			}
+			v.SetFloat(math.Float64frombits(binary.LittleEndian.Uint64(b)))
+		default:
+			return mismatch()
+		}
+	case reflect.String:
+		if tag != kStr {
+			return mismatch()
+		}
+		n, err := r.uvarint()
+		if err != nil {
+			return err
+		}
+		b, err := r.take(n)
+		if err != nil {
+			return err
+		}
+		v.SetString(string(b))
+	case reflect.Array:
+		if tag != kFixed {
+			return mismatch()
+		}
+		n, err := r.uvarint()
+		if err != nil {
+			return err
+		}
+		if int(n) != v.Len() {
+			return fmt.Errorf("%d bytes for %v", n, v.Type())
+		}
+		b, err := r.take(n)
+		if err != nil {
+			return err
+		}
+		reflect.Copy(v, reflect.ValueOf(b))
+	case reflect.Slice:
+		switch {
+		case tag == kNull:
+			v.SetZero()
+		case tag == kBytes && v.Type().Elem().Kind() == reflect.Uint8:
+			n, err := r.uvarint()
+			if err != nil {
+				return err
+			}
+			b, err := r.take(n)
+			if err != nil {
+				return err
+			}
+			v.SetBytes(bytes.Clone(b))
+		case tag == kList:
+			n, err := r.uvarint()
+			if err != nil {
+				return err
+			}
+			if n > uint64(len(r.b)) { // every element is at least one byte
+				return errShortBinary
+			}
+			s := reflect.MakeSlice(v.Type(), int(n), int(n))
+			for i := range int(n) {
+				if err := r.decodeInto(s.Index(i)); err != nil {
+					return fmt.Errorf("[%d]: %w", i, err)
+				}
+			}
+			v.Set(s)
+		default:
+			return mismatch()
+		}
+	default:
+		return mismatch()
+	}
+	return nil
+}
+
+// decodeAny reads one tagged value into the type encoding/json would
+// produce for it, so ToJSON's output matches what the JSON codec would
+// have stored.
+func (r *binaryReader) decodeAny() (any, error) {
+	tag, err := r.byte()
+	if err != nil {
+		return nil, err
+	}
+	switch tag {
+	case kNull:
+		return nil, nil
+	case kFalse, kTrue:
+		return tag == kTrue, nil
+	case kInt:
+		return r.varint()
+	case kUint:
+		return r.uvarint()
+	case kF32:
+		b, err := r.fixed(4)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float32frombits(binary.LittleEndian.Uint32(b)), nil
+	case kF64:
+		b, err := r.fixed(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+	case kStr, kBytes, kFixed:
+		n, err := r.uvarint()
+		if err != nil {
+			return nil, err
+		}
+		b, err := r.take(n)
+		if err != nil {
+			return nil, err
+		}
+		switch tag {
+		case kStr:
+			return string(b), nil
+		case kBytes:
+			return bytes.Clone(b), nil // base64 in JSON, like encoding/json
+		}
+		arr := make([]int, len(b)) // a JSON array of numbers, like encoding/json
+		for i, c := range b {
+			arr[i] = int(c)
+		}
+		return arr, nil
+	case kList:
+		n, err := r.uvarint()
+		if err != nil {
+			return nil, err
+		}
+		if n > uint64(len(r.b)) {
+			return nil, errShortBinary
+		}
+		list := make([]any, n)
+		for i := range list {
+			if list[i], err = r.decodeAny(); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	}
+	return nil, fmt.Errorf("unknown kind tag %d", tag)
+}
@@ -0,0 +1,260 @@
+package game
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// codecProbe is shaped like a hot status-effect component: a few small
+// integers, a source entity, a flag, and a short list.
+type codecProbe struct {
+	Stacks        int32     `json:"stacks"`
+	ExpiresAtTick int64     `json:"expires_at_tick"`
+	Potency       float64   `json:"potency"`
+	Source        uuid.UUID `json:"source"`
+	Refreshable   bool      `json:"refreshable"`
+	Label         string    `json:"label"`
+	Ticks         []int64   `json:"ticks"`
+	Salt          []byte    `json:"salt,omitempty"`
+	internal      int
+}
+
+func (codecProbe) ComponentType() string { return "test_codec_probe" }
+
+// binaryProbe is binary-coded at v2. v1 had (stacks, source_tick); v2
+// renamed source_tick to expires_at_tick.
+type binaryProbe struct {
+	Stacks        int32 `json:"stacks"`
+	ExpiresAtTick int64 `json:"expires_at_tick"`
+}
+
+func (binaryProbe) ComponentType() string { return "test_binary_probe" }
+
+func init() {
+	RegisterComponent[codecProbe](WithCodec(BinaryCodec))
+	RegisterComponent[binaryProbe](
+		WithCodec(BinaryCodec),
+		WithBinaryFields(1, "stacks", "source_tick"),
+		WithVersion(2, func(state []byte) ([]byte, error) {
+			var v1 map[string]any
+			if err := json.Unmarshal(state, &v1); err != nil {
+				return nil, err
+			}
+			v1["expires_at_tick"] = v1["source_tick"]
+			delete(v1, "source_tick")
+			return json.Marshal(v1)
+		}),
+	)
+}
+
+var sampleProbe = codecProbe{
+	Stacks:        3,
+	ExpiresAtTick: 1_204_311,
+	Potency:       1.25,
+	Source:        uuid.MustParse("0190f5a2-7c1e-7cc3-9b8e-3f0a6c2d9e41"),
+	Refreshable:   true,
+	Label:         "poisoned",
+	Ticks:         []int64{-1, 0, 300},
+	Salt:          []byte{0, 1, 2},
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	raw, err := EncodeComponent(sampleProbe)
+	if err != nil {
+		t.Fatalf("EncodeComponent: %v", err)
+	}
+	if !json.Valid(raw) || raw[0] != '"' {
+		t.Fatalf("binary state must be a JSON string: %s", raw)
+	}
+	if detectCodec(raw) != BinaryCodec {
+		t.Fatalf("detectCodec: got %s, want binary", detectCodec(raw).Name())
+	}
+
+	var got codecProbe
+	if err := DecodeComponent(raw, &got); err != nil {
+		t.Fatalf("DecodeComponent: %v", err)
+	}
+	if got.Label != sampleProbe.Label || got.Source != sampleProbe.Source ||
+		got.ExpiresAtTick != sampleProbe.ExpiresAtTick || got.Potency != sampleProbe.Potency ||
+		!got.Refreshable || len(got.Ticks) != 3 || got.Ticks[0] != -1 || string(got.Salt) != "\x00\x01\x02" {
+		t.Errorf("round trip: got %+v, want %+v", got, sampleProbe)
+	}
+
+	var nilSlices codecProbe
+	raw, _ = EncodeComponent(codecProbe{})
+	if err := DecodeComponent(raw, &nilSlices); err != nil || nilSlices.Ticks != nil {
+		t.Errorf("zero value round trip: got %+v, %v", nilSlices, err)
+	}
+}
+
+func TestMixedCodecRowsDecode(t *testing.T) {
+	// A row written while codecProbe was still JSON-coded.
+	legacy, err := JSONCodec.Encode(sampleProbe, 1)
+	if err != nil {
+		t.Fatalf("JSONCodec.Encode: %v", err)
+	}
+	var got codecProbe
+	if err := DecodeComponent(legacy, &got); err != nil {
+		t.Fatalf("DecodeComponent(json row): %v", err)
+	}
+	if got.Source != sampleProbe.Source || got.Label != "poisoned" {
+		t.Errorf("json row: got %+v", got)
+	}
+
+	stale, err := needsRewrite(mustLookup(t, "test_codec_probe"), legacy)
+	if err != nil || !stale {
+		t.Errorf("needsRewrite(json row of binary type): got %v, %v; want true", stale, err)
+	}
+
+	// A JSON string that isn't ours stays with the JSON codec.
+	if detectCodec([]byte(`"hello"`)) != JSONCodec {
+		t.Error(`detectCodec("hello"): want json`)
+	}
+}
+
+func TestBinaryUpgradeUsesFieldHistory(t *testing.T) {
+	type v1 struct {
+		Stacks     int32 `json:"stacks"`
+		SourceTick int64 `json:"source_tick"`
+	}
+	old, err := BinaryCodec.Encode(v1{Stacks: 2, SourceTick: 99}, 1)
+	if err != nil {
+		t.Fatalf("Encode v1: %v", err)
+	}
+	var got binaryProbe
+	if err := DecodeComponent(old, &got); err != nil {
+		t.Fatalf("DecodeComponent(v1): %v", err)
+	}
+	if got != (binaryProbe{Stacks: 2, ExpiresAtTick: 99}) {
+		t.Errorf("upgraded: got %+v", got)
+	}
+
+	cur, _ := EncodeComponent(got)
+	if up, _ := NeedsUpgrade("test_binary_probe", cur); up {
+		t.Error("freshly encoded state reports NeedsUpgrade")
+	}
+	if up, _ := NeedsUpgrade("test_binary_probe", old); !up {
+		t.Error("v1 state doesn't report NeedsUpgrade")
+	}
+}
+
+func TestBinaryRejectsBadInput(t *testing.T) {
+	raw, _ := EncodeComponent(sampleProbe)
+	p, _ := binaryPayload(raw)
+
+	for name, payload := range map[string][]byte{
+		"truncated":      p[:len(p)-2],
+		"trailing bytes": append(append([]byte{}, p...), 0),
+	} {
+		t.Run(name, func(t *testing.T) {
+			var got codecProbe
+			if err := BinaryCodec.Decode(wrapBinary(payload), &got); err == nil {
+				t.Error("Decode: got nil error")
+			}
+		})
+	}
+
+	var wrong binaryProbe
+	if err := BinaryCodec.Decode(raw, &wrong); err == nil {
+		t.Error("Decode into a struct with a different layout: got nil error")
+	}
+}
+
+func TestWithCodecValidatesType(t *testing.T) {
+	type mapped struct {
+		M map[string]int
+	}
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "unsupported") {
+			t.Errorf("recover: got %v, want unsupported-type panic", r)
+		}
+	}()
+	WithCodec(BinaryCodec)(&ComponentInfo{Type: "x", GoType: reflect.TypeOf(mapped{})})
+}
+
+func TestToJSONWithoutFieldHistory(t *testing.T) {
+	raw, _ := EncodeComponent(binaryProbe{})
+	if _, err := BinaryCodec.ToJSON(raw, nil); err == nil {
+		t.Errorf("ToJSON without names: got %v, want error", err)
+	}
+}
+
+func wrapBinary(payload []byte) []byte {
+	return []byte(`"` + base64.RawStdEncoding.EncodeToString(payload) + `"`)
+}
+
+func mustLookup(t *testing.T, typ string) ComponentInfo {
+	t.Helper()
+	info, ok := LookupComponent(typ)
+	if !ok {
+		t.Fatalf("%s not registered", typ)
+	}
+	return info
+}
+
+// benchActor is shaped like the per-actor state the scheduler touches
+// every tick.
+type benchActor struct {
+	Energy         int32   `json:"energy"`
+	MaxEnergy      int32   `json:"max_energy"`
+	EnergyAsOfTick int64   `json:"energy_as_of_tick"`
+	RegenPerTick   float64 `json:"regen_per_tick"`
+	Speed          int32   `json:"speed"`
+	RegionID       int64   `json:"region_id"`
+	X              int32   `json:"x"`
+	Y              int32   `json:"y"`
+	HP             int32   `json:"hp"`
+	MaxHP          int32   `json:"max_hp"`
+	Asleep         bool    `json:"asleep"`
+}
+
+var benchStates = []struct {
+	name string
+	v    any
+	into func() any
+}{
+	{"actor", benchActor{
+		Energy: 840, MaxEnergy: 1000, EnergyAsOfTick: 1_204_311, RegenPerTick: 2.5,
+		Speed: 100, RegionID: 88_412, X: -37, Y: 211, HP: 47, MaxHP: 60,
+	}, func() any { return new(benchActor) }},
+	{"status_effect", sampleProbe, func() any { return new(codecProbe) }},
+}
+
+func BenchmarkCodecEncode(b *testing.B) {
+	for _, c := range []Codec{JSONCodec, BinaryCodec} {
+		for _, s := range benchStates {
+			b.Run(c.Name()+"/"+s.name, func(b *testing.B) {
+				var state []byte
+				for b.Loop() {
+					state, _ = c.Encode(s.v, 3)
+				}
+				b.ReportMetric(float64(len(state)), "B/state")
+			})
+		}
+	}
+}
+
+func BenchmarkCodecDecode(b *testing.B) {
+	for _, c := range []Codec{JSONCodec, BinaryCodec} {
+		for _, s := range benchStates {
+			b.Run(c.Name()+"/"+s.name, func(b *testing.B) {
+				state, err := c.Encode(s.v, 3)
+				if err != nil {
+					b.Fatal(err)
+				}
+				into := s.into()
+				for b.Loop() {
+					if err := c.Decode(state, into); err != nil {
+						b.Fatal(err)
+					}
+				}
+				b.ReportMetric(float64(len(state)), "B/state")
+			})
+		}
+	}
+}
@@ -1,43 +1,86 @@
 package game
 
 import (
-	"encoding/json"
 	"fmt"
 )
 
 // Component-type strings live as constants so they're greppable and
 // renames are mechanical. The DB column is free-form TEXT (DESIGN.md
-// §6.4); validation is purely Go-side.
+// §6.4); validation is purely Go-side, via the registry in
+// registry.go — every type here needs a RegisterComponent call.
 const (
-	ComponentHidden = "hidden"
+	ComponentHidden        = "hidden"
+	ComponentDetectsHidden = "detects_hidden"
+	ComponentDestroyed     = "destroyed"
+
+	// Status effects; see effects.go.
+	ComponentOnFire       = "on_fire"
+	ComponentPoisoned     = "poisoned"
+	ComponentStunned      = "stunned"
+	ComponentRegenerating = "regenerating"
 )
 
 // Hidden is a marker component: its presence on an entity means the
 // entity isn't broadcast to clients (think stealth, fog-of-war, GM
 // invisibility). The struct is empty by design — the *presence of the
-// row* is the signal.
+// row* is the signal. Enforced by the visibility layer in
+// visibility.go.
 type Hidden struct{}
 
 // ComponentType lets Hidden satisfy Component.
 func (Hidden) ComponentType() string { return ComponentHidden }
 
-// EncodeComponent serializes c to the JSONB blob that lands in
-// components.state. Centralized so swapping encodings later is a
-// one-place change.
+// DetectsHidden is Hidden's counter: an entity carrying it sees hidden
+// entities as if they weren't.
+type DetectsHidden struct{}
+
+// ComponentType lets DetectsHidden satisfy Component.
+func (DetectsHidden) ComponentType() string { return ComponentDetectsHidden }
+
+// Destroyed is written by DestroyEntity alongside destroyed_at_tick so
+// the row says why the entity is gone, not just when. It stays until
+// the sweep hard-deletes the entity, or RestoreEntity undoes the
+// destruction.
+type Destroyed struct {
+	Reason string `json:"reason"`
+	Tick   int64  `json:"tick"`
+
+	// Position is where the entity was when destroyed, nil if it wasn't
+	// in the world. The position row itself is deleted; this is what
+	// RestoreEntity puts it back from.
+	Position *PositionSpec `json:"position,omitempty"`
+}
+
+// ComponentType lets Destroyed satisfy Component.
+func (Destroyed) ComponentType() string { return ComponentDestroyed }
+
+func init() {
+	RegisterComponent[Hidden]()
+	RegisterComponent[DetectsHidden]()
+	RegisterComponent[Destroyed]()
+}
+
+// EncodeComponent serializes c to the blob that lands in
+// components.state, using the codec and schema version c's type is
+// registered with (JSON at version 1 if it isn't). Centralized so
+// swapping encodings is a one-place change — see codec.go.
 func EncodeComponent(c Component) ([]byte, error) {
-	b, err := json.Marshal(c)
+	codec, version := JSONCodec, 1
+	if info, ok := LookupComponent(c.ComponentType()); ok {
+		codec, version = info.Codec, info.Version
+	}
+	b, err := codec.Encode(c, version)
 	if err != nil {
 		return nil, fmt.Errorf("encode component %s: %w", c.ComponentType(), err)
 	}
 	return b, nil
 }
 
-// DecodeComponent unmarshals raw JSONB state into the supplied component
+// DecodeComponent unmarshals stored state into the supplied component
 // pointer. The pointer's type doubles as the schema — callers know what
-// they're reading because they pass it in.
+// they're reading because they pass it in. The codec is detected from
+// the state itself, and state written at an older schema version is run
+// through the type's upgraders first.
 func DecodeComponent(raw []byte, into Component) error {
-	if err := json.Unmarshal(raw, into); err != nil {
-		return fmt.Errorf("decode component %s: %w", into.ComponentType(), err)
-	}
-	return nil
+	return decodeState(into.ComponentType(), raw, into)
 }
@@ -0,0 +1,101 @@
+package game
+
+// The first status effects, from DESIGN.md §7.5. Each modifies HP (or,
+// for Stunned, whether the entity may act) without being HP: the
+// effects live in components, HP in the core tables.
+
+// OnFire burns for Damage HP a tick. Catching fire again restarts the
+// burn rather than doubling it.
+type OnFire struct {
+	EffectState
+	Damage int32 `json:"damage"`
+}
+
+// ComponentType lets OnFire satisfy Component.
+func (OnFire) ComponentType() string { return ComponentOnFire }
+
+// WithStatus lets OnFire satisfy StatusEffect.
+func (e OnFire) WithStatus(s EffectState) StatusEffect { e.EffectState = s; return e }
+
+// Stacking makes a new burn restart the old one.
+func (OnFire) Stacking() StackRule { return StackRefresh }
+
+// MaxStacks is 1: burns don't stack.
+func (OnFire) MaxStacks() int32 { return 1 }
+
+// OnTick burns Damage HP.
+func (e OnFire) OnTick(int64) int32 { return -e.Damage }
+
+// MaxPoisonStacks caps how many doses of poison accumulate.
+const MaxPoisonStacks = 5
+
+// Poisoned deals Damage HP a tick per stack. Each new dose adds a stack
+// and restarts the clock.
+type Poisoned struct {
+	EffectState
+	Damage int32 `json:"damage"`
+}
+
+// ComponentType lets Poisoned satisfy Component.
+func (Poisoned) ComponentType() string { return ComponentPoisoned }
+
+// WithStatus lets Poisoned satisfy StatusEffect.
+func (e Poisoned) WithStatus(s EffectState) StatusEffect { e.EffectState = s; return e }
+
+// Stacking makes each new dose add a stack.
+func (Poisoned) Stacking() StackRule { return StackIntensity }
+
+// MaxStacks caps the doses at MaxPoisonStacks.
+func (Poisoned) MaxStacks() int32 { return MaxPoisonStacks }
+
+// OnTick deals Damage HP per stack.
+func (e Poisoned) OnTick(int64) int32 { return -e.Damage * e.Stacks }
+
+// Stunned stops the entity acting; see HasEffect. A stun can't be
+// extended by stunning again, so stun-locking takes a gap.
+type Stunned struct {
+	EffectState
+}
+
+// ComponentType lets Stunned satisfy Component.
+func (Stunned) ComponentType() string { return ComponentStunned }
+
+// WithStatus lets Stunned satisfy StatusEffect.
+func (e Stunned) WithStatus(s EffectState) StatusEffect { e.EffectState = s; return e }
+
+// Stacking makes a stun ignore further stuns until it ends.
+func (Stunned) Stacking() StackRule { return StackIgnore }
+
+// MaxStacks is 1: stuns don't stack.
+func (Stunned) MaxStacks() int32 { return 1 }
+
+// OnTick leaves HP alone; a stun only stops the entity acting.
+func (Stunned) OnTick(int64) int32 { return 0 }
+
+// Regenerating heals Heal HP a tick. Reapplying restarts it.
+type Regenerating struct {
+	EffectState
+	Heal int32 `json:"heal"`
+}
+
+// ComponentType lets Regenerating satisfy Component.
+func (Regenerating) ComponentType() string { return ComponentRegenerating }
+
+// WithStatus lets Regenerating satisfy StatusEffect.
+func (e Regenerating) WithStatus(s EffectState) StatusEffect { e.EffectState = s; return e }
+
+// Stacking makes reapplying restart the regeneration.
+func (Regenerating) Stacking() StackRule { return StackRefresh }
+
+// MaxStacks is 1: regeneration doesn't stack.
+func (Regenerating) MaxStacks() int32 { return 1 }
+
+// OnTick heals Heal HP.
+func (e Regenerating) OnTick(int64) int32 { return e.Heal }
+
+func init() {
+	RegisterStatusEffect[OnFire]()
+	RegisterStatusEffect[Poisoned]()
+	RegisterStatusEffect[Stunned]()
+	RegisterStatusEffect[Regenerating]()
+}
@@ -26,8 +26,9 @@ type TxBeginner interface {
 // the entity's CreatedAtTick — there's no meaningful "this position
 // is older than the entity" case at creation time.
 type PositionSpec struct {
-	RegionID int32
-	X, Y     int32
+	RegionID int32 `json:"region_id"`
+	X        int32 `json:"x"`
+	Y        int32 `json:"y"`
 }
 
 // CreateEntityInput is the one-stop payload for the entity-creation
@@ -43,7 +44,8 @@ type CreateEntityInput struct {
 
 // CreateEntity inserts an `entities` row plus the optional position and
 // component rows in a single transaction. Returns the freshly generated
-// UUIDv7 so the caller has a handle for follow-up work.
+// UUIDv7 so the caller has a handle for follow-up work. Inside Bus.InTx
+// each initial component is recorded as an added event.
 //
 // This is the primary entity-creation API; Layer 3+ helpers
 // (character-creation, NPC spawning, etc.) compose on top of it rather
@@ -93,6 +95,9 @@ func CreateEntity(ctx context.Context, tb TxBeginner, in CreateEntityInput) (uui
 		if c == nil {
 			return uuid.Nil, errors.New("create entity: nil component in InitialComponents")
 		}
+		if err := checkRegistered(c); err != nil {
+			return uuid.Nil, fmt.Errorf("create entity: %w", err)
+		}
 		raw, err := EncodeComponent(c)
 		if err != nil {
 			return uuid.Nil, err
@@ -111,5 +116,362 @@ func CreateEntity(ctx context.Context, tb TxBeginner, in CreateEntityInput) (uui
 	if err := tx.Commit(ctx); err != nil {
 		return uuid.Nil, fmt.Errorf("commit: %w", err)
 	}
+	for _, c := range in.InitialComponents {
+		record(ctx, ComponentEvent{Kind: ComponentAdded, EntityID: id, Type: c.ComponentType(), New: c, Tick: in.Tick})
+	}
 	return id, nil
 }
+
+// CreateEntities is the bulk form of CreateEntity, for world generation
+// and anything else spawning hundreds of entities at once. Rows go in
+// through COPY — one round trip per table instead of one per row — in a
+// single transaction: either every entity is created or none is. IDs are
+// returned in input order. Inside Bus.InTx components are recorded as
+// added events, as CreateEntity does.
+func CreateEntities(ctx context.Context, tb TxBeginner, ins []CreateEntityInput) ([]uuid.UUID, error) {
+	if len(ins) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uuid.UUID, len(ins))
+	entities := make([]sqlc.CopyEntitiesParams, len(ins))
+	var (
+		positions  []sqlc.CopyEntityPositionsParams
+		components []sqlc.CopyComponentsParams
+	)
+	for i, in := range ins {
+		if !in.Type.Valid() {
+			return nil, fmt.Errorf("create entities: [%d]: invalid type %q", i, in.Type)
+		}
+		id, err := NewEntityID()
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+		pgID := pgtype.UUID{Bytes: id, Valid: true}
+
+		entities[i] = sqlc.CopyEntitiesParams{
+			ID:            pgID,
+			SeasonID:      in.SeasonID,
+			EntityType:    string(in.Type),
+			CreatedAtTick: in.Tick,
+		}
+		if in.Position != nil {
+			positions = append(positions, sqlc.CopyEntityPositionsParams{
+				EntityID:      pgID,
+				RegionID:      in.Position.RegionID,
+				X:             in.Position.X,
+				Y:             in.Position.Y,
+				UpdatedAtTick: in.Tick,
+			})
+		}
+		for _, c := range in.InitialComponents {
+			if c == nil {
+				return nil, fmt.Errorf("create entities: [%d]: nil component in InitialComponents", i)
+			}
+			if err := checkRegistered(c); err != nil {
+				return nil, fmt.Errorf("create entities: [%d]: %w", i, err)
+			}
+			raw, err := EncodeComponent(c)
+			if err != nil {
+				return nil, err
+			}
+			components = append(components, sqlc.CopyComponentsParams{
+				EntityID:      pgID,
+				ComponentType: c.ComponentType(),
+				State:         raw,
+				CreatedAtTick: in.Tick,
+				UpdatedAtTick: in.Tick,
+			})
+		}
+	}
+
+	tx, err := tb.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	q := sqlc.New(tx)
+	if _, err := q.CopyEntities(ctx, entities); err != nil {
+		return nil, fmt.Errorf("copy entities: %w", err)
+	}
+	if len(positions) > 0 {
+		if _, err := q.CopyEntityPositions(ctx, positions); err != nil {
+			return nil, fmt.Errorf("copy positions: %w", err)
+		}
+	}
+	if len(components) > 0 {
+		if _, err := q.CopyComponents(ctx, components); err != nil {
+			return nil, fmt.Errorf("copy components: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	for i, in := range ins {
+		for _, c := range in.InitialComponents {
+			record(ctx, ComponentEvent{Kind: ComponentAdded, EntityID: ids[i], Type: c.ComponentType(), New: c, Tick: in.Tick})
+		}
+	}
+	return ids, nil
+}
+
+// ErrEntityAlreadyDestroyed is returned by DestroyEntity for an entity
+// that is already soft-deleted.
+var ErrEntityAlreadyDestroyed = errors.New("game: entity already destroyed")
+
+// DestroyEntityInput is the payload for DestroyEntity. Reason is
+// required; it lands in a Destroyed component ("killed", "consumed",
+// "expired", ...).
+type DestroyEntityInput struct {
+	ID     uuid.UUID
+	Tick   int64
+	Reason string
+
+	// StripTransient deletes the entity's transient components (see
+	// Transient) so nothing reading the soft-deleted row sees it still
+	// burning.
+	StripTransient bool
+}
+
+// DestroyEntity soft-deletes an entity in one transaction: it sets
+// destroyed_at_tick, deletes the position row (DESIGN.md §6.3 — no
+// position means not in the world), takes an actor out of the
+// scheduler, optionally strips transient components, and records a
+// Destroyed component carrying the reason and the deleted position.
+// Inside Bus.InTx the component writes are recorded as events. A
+// missing entity reports ErrEntityNotFound; a destroyed one
+// ErrEntityAlreadyDestroyed.
+//
+// Destruction is write-through (DESIGN.md §3.7). Callers holding the
+// entity in a World evict it with RemoveEntity after this returns.
+func DestroyEntity(ctx context.Context, tb TxBeginner, in DestroyEntityInput) error {
+	if in.Reason == "" {
+		return errors.New("destroy entity: empty reason")
+	}
+	pgID := pgtype.UUID{Bytes: in.ID, Valid: true}
+
+	tx, err := tb.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	q := sqlc.New(tx)
+
+	if _, err := q.SoftDeleteEntity(ctx, sqlc.SoftDeleteEntityParams{
+		ID:              pgID,
+		DestroyedAtTick: &in.Tick,
+	}); err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("soft-delete entity: %w", err)
+		}
+		// No live row: either there's no row at all or it's already
+		// destroyed. The UPDATE can't say which, so look.
+		if _, err := q.GetEntityByID(ctx, pgID); errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("destroy entity %s: %w", in.ID, ErrEntityNotFound)
+		} else if err != nil {
+			return fmt.Errorf("get entity: %w", err)
+		}
+		return fmt.Errorf("destroy entity %s: %w", in.ID, ErrEntityAlreadyDestroyed)
+	}
+
+	destroyed := Destroyed{Reason: in.Reason, Tick: in.Tick}
+	if pos, err := q.GetEntityPosition(ctx, pgID); err == nil {
+		destroyed.Position = &PositionSpec{RegionID: pos.RegionID, X: pos.X, Y: pos.Y}
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("get position: %w", err)
+	}
+	if err := q.DeleteEntityPosition(ctx, pgID); err != nil {
+		return fmt.Errorf("delete position: %w", err)
+	}
+	if _, err := q.UnscheduleActor(ctx, pgID); err != nil {
+		return fmt.Errorf("unschedule actor: %w", err)
+	}
+
+	if in.StripTransient {
+		if err := stripTransient(ctx, q, in.ID, in.Tick); err != nil {
+			return err
+		}
+	}
+
+	if err := SetComponent(ctx, q, in.ID, destroyed, in.Tick); err != nil {
+		return fmt.Errorf("record destroy reason: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// stripTransient deletes entity id's transient components in one
+// statement. Inside Bus.InTx each one it had is recorded as a removed
+// event at tick.
+func stripTransient(ctx context.Context, q *sqlc.Queries, id uuid.UUID, tick int64) error {
+	types := TransientComponentTypes()
+	if len(types) == 0 {
+		return nil
+	}
+	var removed []ComponentEvent
+	if recorderFrom(ctx) != nil {
+		for _, typ := range types {
+			old, err := previousState(ctx, q, id, typ)
+			if err != nil {
+				return fmt.Errorf("strip transient components: %w", err)
+			}
+			if old != nil {
+				removed = append(removed, ComponentEvent{Kind: ComponentRemoved, EntityID: id, Type: typ, Old: old, Tick: tick})
+			}
+		}
+	}
+	if _, err := q.DeleteComponentsOfTypes(ctx, sqlc.DeleteComponentsOfTypesParams{
+		EntityID:       pgtype.UUID{Bytes: id, Valid: true},
+		ComponentTypes: types,
+	}); err != nil {
+		return fmt.Errorf("strip transient components: %w", err)
+	}
+	for _, e := range removed {
+		record(ctx, e)
+	}
+	return nil
+}
+
+// ErrEntityNotDestroyed is returned by RestoreEntity for a live entity.
+var ErrEntityNotDestroyed = errors.New("game: entity not destroyed")
+
+// ErrEntitySwept is returned by RestoreEntity for an entity the sweep
+// has already hard-deleted. RestoreArchivedEntity brings its rows back
+// from the archive, after which RestoreEntity can proceed.
+var ErrEntitySwept = errors.New("game: entity swept")
+
+// RestoreEntityInput is the payload for RestoreEntity. AdminID and
+// Reason are required; both go on the audit trail.
+type RestoreEntityInput struct {
+	ID      uuid.UUID
+	Tick    int64
+	AdminID uuid.UUID
+	Reason  string
+
+	// Position places the entity. Nil means where it was when
+	// destroyed, as DestroyEntity recorded it; an entity with neither
+	// comes back without a position, i.e. not in the world.
+	Position *PositionSpec
+}
+
+// RestoreEntity undoes DestroyEntity for an admin rollback (DESIGN.md
+// §3.6), in one transaction: it clears destroyed_at_tick, puts the
+// entity back at in.Position or its last-known position, drops the
+// Destroyed component, puts an actor back on the schedule at in.Tick,
+// and appends an entity_restorations row naming the admin. Transient
+// components stripped at destruction stay gone.
+//
+// A missing entity reports ErrEntityNotFound, or ErrEntitySwept if the
+// sweep took it; a live one ErrEntityNotDestroyed. Callers holding a
+// World add the entity back after this returns.
+func RestoreEntity(ctx context.Context, tb TxBeginner, in RestoreEntityInput) error {
+	if in.AdminID == uuid.Nil {
+		return errors.New("restore entity: no admin")
+	}
+	if in.Reason == "" {
+		return errors.New("restore entity: empty reason")
+	}
+	pgID := pgtype.UUID{Bytes: in.ID, Valid: true}
+	auditID, err := uuid.NewV7()
+	if err != nil {
+		return fmt.Errorf("restore entity: audit id: %w", err)
+	}
+
+	tx, err := tb.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	q := sqlc.New(tx)
+
+	prev, err := q.UndeleteEntity(ctx, pgID)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("undelete entity: %w", err)
+		}
+		if _, err := q.GetEntityByID(ctx, pgID); err == nil {
+			return fmt.Errorf("restore entity %s: %w", in.ID, ErrEntityNotDestroyed)
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("get entity: %w", err)
+		}
+		swept, err := q.IsEntityArchived(ctx, pgID)
+		if err != nil {
+			return fmt.Errorf("check archive: %w", err)
+		}
+		if swept {
+			return fmt.Errorf("restore entity %s: %w", in.ID, ErrEntitySwept)
+		}
+		return fmt.Errorf("restore entity %s: %w", in.ID, ErrEntityNotFound)
+	}
+
+	// The entity may have been soft-deleted some other way than
+	// DestroyEntity, with no Destroyed component and its position row
+	// still in place.
+	var (
+		destroyReason *string
+		pos           = in.Position
+	)
+	destroyed, err := GetComponent[Destroyed](ctx, q, in.ID)
+	switch {
+	case err == nil:
+		destroyReason = &destroyed.Reason
+		if pos == nil {
+			pos = destroyed.Position
+		}
+		if err := RemoveComponent[Destroyed](ctx, q, in.ID); err != nil {
+			return fmt.Errorf("remove destroyed marker: %w", err)
+		}
+	case !errors.Is(err, ErrComponentNotFound):
+		return err
+	}
+	if pos == nil {
+		if row, err := q.GetEntityPosition(ctx, pgID); err == nil {
+			pos = &PositionSpec{RegionID: row.RegionID, X: row.X, Y: row.Y}
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("get position: %w", err)
+		}
+	}
+
+	audit := sqlc.AppendEntityRestorationParams{
+		ID:              pgtype.UUID{Bytes: auditID, Valid: true},
+		EntityID:        pgID,
+		SeasonID:        prev.SeasonID,
+		RestoredBy:      pgtype.UUID{Bytes: in.AdminID, Valid: true},
+		Reason:          in.Reason,
+		DestroyedAtTick: *prev.DestroyedAtTick,
+		DestroyReason:   destroyReason,
+		RestoredAtTick:  in.Tick,
+	}
+	if pos != nil {
+		if _, err := q.SetEntityPosition(ctx, sqlc.SetEntityPositionParams{
+			EntityID:      pgID,
+			RegionID:      pos.RegionID,
+			X:             pos.X,
+			Y:             pos.Y,
+			UpdatedAtTick: in.Tick,
+		}); err != nil {
+			return fmt.Errorf("restore position: %w", err)
+		}
+		audit.RegionID, audit.X, audit.Y = &pos.RegionID, &pos.X, &pos.Y
+	}
+	if _, err := q.AppendEntityRestoration(ctx, audit); err != nil {
+		return fmt.Errorf("record restoration: %w", err)
+	}
+	// DestroyEntity took any actor off the schedule; put it back.
+	if _, err := MarkActorReady(ctx, q, in.ID, in.Tick); err != nil && !errors.Is(err, ErrActorNotFound) {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
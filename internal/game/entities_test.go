@@ -2,8 +2,11 @@ package game_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 
 	"github.com/dukerupert/walking-drum/internal/db/sqlc"
@@ -131,3 +134,233 @@ func TestCreateEntity_AtomicityOnComponentFailure(t *testing.T) {
 		t.Errorf("entity rows after rolled-back create: got %d, want 0", len(rows))
 	}
 }
+
+type transientProbe struct{}
+
+func (transientProbe) ComponentType() string { return "test_transient_probe" }
+
+func init() {
+	game.RegisterComponent[transientProbe](game.Transient())
+}
+
+func TestDestroyEntity(t *testing.T) {
+	_, tx := testdb.WithTx(t)
+	ctx := context.Background()
+	q := sqlc.New(tx)
+
+	id, err := game.CreateEntity(ctx, tx, game.CreateEntityInput{
+		SeasonID:          1,
+		Type:              game.EntityNPC,
+		Tick:              1,
+		Position:          &game.PositionSpec{RegionID: 1, X: 2, Y: 3},
+		InitialComponents: []game.Component{game.Hidden{}, transientProbe{}},
+	})
+	if err != nil {
+		t.Fatalf("CreateEntity: %v", err)
+	}
+	pgID := pgtype.UUID{Bytes: id, Valid: true}
+
+	in := game.DestroyEntityInput{ID: id, Tick: 50, Reason: "killed", StripTransient: true}
+	if err := game.DestroyEntity(ctx, tx, in); err != nil {
+		t.Fatalf("DestroyEntity: %v", err)
+	}
+
+	ent, err := q.GetEntityByID(ctx, pgID)
+	if err != nil || ent.DestroyedAtTick == nil || *ent.DestroyedAtTick != 50 {
+		t.Fatalf("entity after destroy: got %+v, %v", ent, err)
+	}
+	if _, err := q.GetEntityPosition(ctx, pgID); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("position after destroy: got %v, want pgx.ErrNoRows", err)
+	}
+	if has, _ := game.HasComponent[transientProbe](ctx, q, id); has {
+		t.Error("transient component survived StripTransient")
+	}
+	if has, _ := game.HasComponent[game.Hidden](ctx, q, id); !has {
+		t.Error("non-transient component was stripped")
+	}
+	if d, err := game.GetComponent[game.Destroyed](ctx, q, id); err != nil || d.Reason != "killed" || d.Tick != 50 ||
+		d.Position == nil || *d.Position != (game.PositionSpec{RegionID: 1, X: 2, Y: 3}) {
+		t.Errorf("Destroyed component: got %+v, %v", d, err)
+	}
+
+	if err := game.DestroyEntity(ctx, tx, in); !errors.Is(err, game.ErrEntityAlreadyDestroyed) {
+		t.Errorf("second destroy: got %v, want ErrEntityAlreadyDestroyed", err)
+	}
+	in.ID = uuid.New()
+	if err := game.DestroyEntity(ctx, tx, in); !errors.Is(err, game.ErrEntityNotFound) {
+		t.Errorf("destroy unknown: got %v, want ErrEntityNotFound", err)
+	}
+}
+
+func TestRestoreEntity(t *testing.T) {
+	_, tx := testdb.WithTx(t)
+	ctx := context.Background()
+	q := sqlc.New(tx)
+
+	admin, _ := uuid.NewV7()
+	if _, err := q.CreateAccount(ctx, sqlc.CreateAccountParams{
+		ID: pgtype.UUID{Bytes: admin, Valid: true}, Email: "gm@example.com", DisplayName: "gm", PasswordHash: "x",
+	}); err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	spawn := func() uuid.UUID {
+		id, err := game.CreateEntity(ctx, tx, game.CreateEntityInput{
+			SeasonID: 1, Type: game.EntityItem, Tick: 1,
+			Position: &game.PositionSpec{RegionID: 1, X: 2, Y: 3},
+		})
+		must(t, err)
+		must(t, game.DestroyEntity(ctx, tx, game.DestroyEntityInput{ID: id, Tick: 10, Reason: "consumed"}))
+		return id
+	}
+
+	// Last-known position.
+	id := spawn()
+	in := game.RestoreEntityInput{ID: id, Tick: 20, AdminID: admin, Reason: "dupe bug refund"}
+	must(t, game.RestoreEntity(ctx, tx, in))
+	pgID := pgtype.UUID{Bytes: id, Valid: true}
+	if e, err := q.GetEntityByID(ctx, pgID); err != nil || e.DestroyedAtTick != nil {
+		t.Errorf("entity after restore: got %+v, %v", e, err)
+	}
+	if p, err := q.GetEntityPosition(ctx, pgID); err != nil || p.RegionID != 1 || p.X != 2 || p.Y != 3 || p.UpdatedAtTick != 20 {
+		t.Errorf("position after restore: got %+v, %v", p, err)
+	}
+	if has, _ := game.HasComponent[game.Destroyed](ctx, q, id); has {
+		t.Error("Destroyed component survived restore")
+	}
+	trail, err := q.ListEntityRestorations(ctx, pgID)
+	must(t, err)
+	if len(trail) != 1 || trail[0].RestoredBy.Bytes != admin || trail[0].DestroyedAtTick != 10 ||
+		trail[0].DestroyReason == nil || *trail[0].DestroyReason != "consumed" || trail[0].X == nil || *trail[0].X != 2 {
+		t.Errorf("audit trail: got %+v", trail)
+	}
+	if err := game.RestoreEntity(ctx, tx, in); !errors.Is(err, game.ErrEntityNotDestroyed) {
+		t.Errorf("restore live entity: got %v, want ErrEntityNotDestroyed", err)
+	}
+
+	// Supplied position wins.
+	id = spawn()
+	in.ID, in.Position = id, &game.PositionSpec{RegionID: 4, X: 5, Y: 6}
+	must(t, game.RestoreEntity(ctx, tx, in))
+	if p, err := q.GetEntityPosition(ctx, pgtype.UUID{Bytes: id, Valid: true}); err != nil || p.RegionID != 4 {
+		t.Errorf("position after restore: got %+v, %v", p, err)
+	}
+
+	// Swept, then back from the archive.
+	id = spawn()
+	in.ID = id
+	r := game.NewSweepRunner(tx, game.SweepRunnerConfig{SweepConfig: game.SweepConfig{Enabled: true}})
+	_, err = r.Run(ctx, 100)
+	must(t, err)
+	if err := game.RestoreEntity(ctx, tx, in); !errors.Is(err, game.ErrEntitySwept) {
+		t.Errorf("restore swept: got %v, want ErrEntitySwept", err)
+	}
+	must(t, game.RestoreArchivedEntity(ctx, tx, 1, id))
+	must(t, game.RestoreEntity(ctx, tx, in))
+
+	in.ID = uuid.New()
+	if err := game.RestoreEntity(ctx, tx, in); !errors.Is(err, game.ErrEntityNotFound) {
+		t.Errorf("restore unknown: got %v, want ErrEntityNotFound", err)
+	}
+}
+
+func spawnBatch(n int) []game.CreateEntityInput {
+	ins := make([]game.CreateEntityInput, n)
+	for i := range ins {
+		ins[i] = game.CreateEntityInput{
+			SeasonID:          1,
+			Type:              game.EntityNPC,
+			Tick:              7,
+			Position:          &game.PositionSpec{RegionID: 9, X: int32(i % 64), Y: int32(i / 64)},
+			InitialComponents: []game.Component{game.Hidden{}},
+		}
+	}
+	return ins
+}
+
+func TestCreateEntities(t *testing.T) {
+	_, tx := testdb.WithTx(t)
+	ctx := context.Background()
+	q := sqlc.New(tx)
+
+	ins := spawnBatch(100)
+	ins[3].Position = nil
+	ins[4].InitialComponents = nil
+	ids, err := game.CreateEntities(ctx, tx, ins)
+	if err != nil {
+		t.Fatalf("CreateEntities: %v", err)
+	}
+	if len(ids) != len(ins) {
+		t.Fatalf("ids: got %d, want %d", len(ids), len(ins))
+	}
+
+	rows, err := q.GetEntitiesInRegion(ctx, 9)
+	if err != nil {
+		t.Fatalf("GetEntitiesInRegion: %v", err)
+	}
+	if len(rows) != 99 {
+		t.Errorf("positions: got %d, want 99", len(rows))
+	}
+	pos, err := q.GetEntityPosition(ctx, pgtype.UUID{Bytes: ids[70], Valid: true})
+	if err != nil || pos.X != 70%64 || pos.Y != 1 || pos.UpdatedAtTick != 7 {
+		t.Errorf("position of ids[70]: got %+v, %v", pos, err)
+	}
+	if has, err := game.HasComponent[game.Hidden](ctx, q, ids[0]); err != nil || !has {
+		t.Errorf("component of ids[0]: got %v, %v", has, err)
+	}
+	if has, _ := game.HasComponent[game.Hidden](ctx, q, ids[4]); has {
+		t.Error("ids[4] got a component it didn't ask for")
+	}
+}
+
+func TestCreateEntities_AllOrNothing(t *testing.T) {
+	_, tx := testdb.WithTx(t)
+	ctx := context.Background()
+
+	// The duplicate component violates the components PK partway through
+	// the COPY; every entity in the batch must roll back with it.
+	ins := spawnBatch(10)
+	ins[9].InitialComponents = []game.Component{game.Hidden{}, game.Hidden{}}
+	if _, err := game.CreateEntities(ctx, tx, ins); err == nil {
+		t.Fatal("CreateEntities with a duplicate component: got nil error")
+	}
+
+	rows, err := sqlc.New(tx).GetEntitiesInRegion(ctx, 9)
+	if err != nil {
+		t.Fatalf("GetEntitiesInRegion: %v", err)
+	}
+	if len(rows) != 0 {
+		t.Errorf("positions after failed batch: got %d, want 0", len(rows))
+	}
+}
+
+// BenchmarkSpawn compares the single-row helper with the COPY path for a
+// region's worth of spawns. Run against a real database:
+//
+//	go test ./internal/game -run '^$' -bench Spawn
+func BenchmarkSpawn(b *testing.B) {
+	const n = 1000
+	ctx := context.Background()
+
+	b.Run("CreateEntity", func(b *testing.B) {
+		_, tx := testdb.WithTx(b)
+		ins := spawnBatch(n)
+		for b.Loop() {
+			for _, in := range ins {
+				if _, err := game.CreateEntity(ctx, tx, in); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+		b.ReportMetric(float64(n*b.N)/b.Elapsed().Seconds(), "entities/s")
+	})
+	b.Run("CreateEntities", func(b *testing.B) {
+		_, tx := testdb.WithTx(b)
+		ins := spawnBatch(n)
+		for b.Loop() {
+			if _, err := game.CreateEntities(ctx, tx, ins); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(n*b.N)/b.Elapsed().Seconds(), "entities/s")
+	})
+}
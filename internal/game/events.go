@@ -0,0 +1,284 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/maphash"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+)
+
+// EventKind says what happened to a component.
+type EventKind string
+
+const (
+	ComponentAdded   EventKind = "added"
+	ComponentChanged EventKind = "changed"
+	ComponentRemoved EventKind = "removed"
+)
+
+// ComponentEvent reports one component write. Old is nil for Added and
+// New is nil for Removed.
+type ComponentEvent struct {
+	Kind     EventKind
+	EntityID uuid.UUID
+	Type     string
+	Old, New Component
+	Tick     int64
+}
+
+// eventRecorder collects a transaction's events until it commits.
+type eventRecorder struct {
+	tick   int64
+	events []ComponentEvent
+}
+
+type recorderKey struct{}
+
+func recorderFrom(ctx context.Context) *eventRecorder {
+	r, _ := ctx.Value(recorderKey{}).(*eventRecorder)
+	return r
+}
+
+// record appends e if ctx belongs to a Bus.InTx transaction. Outside one
+// there's nobody to tell, and the write stays silent.
+func record(ctx context.Context, e ComponentEvent) {
+	if r := recorderFrom(ctx); r != nil {
+		r.events = append(r.events, e)
+	}
+}
+
+// eventStripes is how many locks per-entity ordering is spread over.
+const eventStripes = 64
+
+// Bus delivers component events in-process to subscribers: broadcast,
+// audit, achievements. Events are only ever published after the
+// transaction that produced them commits, so a subscriber never hears
+// about a write that rolled back.
+//
+// Events for one entity reach every subscriber in commit order. Different
+// entities are delivered independently, under striped locks, so one busy
+// entity doesn't serialize the rest.
+type Bus struct {
+	mu     sync.RWMutex
+	subs   []*subscriber
+	nextID int
+	closed bool
+
+	seed    maphash.Seed
+	stripes [eventStripes]sync.Mutex
+	workers sync.WaitGroup
+}
+
+type subscriber struct {
+	id    int
+	types []string // nil = every type
+	fn    func(ComponentEvent)
+	queue chan ComponentEvent // nil for synchronous subscribers
+}
+
+func (s *subscriber) wants(typ string) bool {
+	return s.types == nil || slices.Contains(s.types, typ)
+}
+
+// NewBus returns a Bus with no subscribers.
+func NewBus() *Bus {
+	return &Bus{seed: maphash.MakeSeed()}
+}
+
+// Subscribe calls fn for every event of the given component types (all
+// types if none), synchronously on the publishing goroutine. fn must be
+// quick: the committing caller waits for it, and so does every other
+// publisher touching the same entity. It must not Publish or unsubscribe
+// from inside fn. The returned func unsubscribes.
+func (b *Bus) Subscribe(fn func(ComponentEvent), types ...string) (unsubscribe func()) {
+	return b.add(&subscriber{types: nilIfEmpty(types), fn: fn})
+}
+
+// SubscribeAsync is Subscribe with a buffer of size events between the
+// publisher and fn, which runs on its own goroutine. Publishers block
+// when the buffer is full rather than drop events.
+func (b *Bus) SubscribeAsync(size int, fn func(ComponentEvent), types ...string) (unsubscribe func()) {
+	s := &subscriber{types: nilIfEmpty(types), fn: fn, queue: make(chan ComponentEvent, max(size, 1))}
+	b.workers.Go(func() {
+		for e := range s.queue {
+			s.fn(e)
+		}
+	})
+	return b.add(s)
+}
+
+func nilIfEmpty(types []string) []string {
+	if len(types) == 0 {
+		return nil
+	}
+	return slices.Clone(types)
+}
+
+func (b *Bus) add(s *subscriber) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		panic("game: Subscribe on a closed Bus")
+	}
+	b.nextID++
+	s.id = b.nextID
+	b.subs = append(b.subs, s)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { b.remove(s.id) })
+	}
+}
+
+func (b *Bus) remove(id int) {
+	// Taking every stripe waits out in-flight publishes, so no one is
+	// mid-send when the queue closes.
+	for i := range b.stripes {
+		b.stripes[i].Lock()
+	}
+	defer func() {
+		for i := range b.stripes {
+			b.stripes[i].Unlock()
+		}
+	}()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, s := range b.subs {
+		if s.id == id {
+			b.subs = slices.Delete(b.subs, i, i+1)
+			if s.queue != nil {
+				close(s.queue)
+			}
+			return
+		}
+	}
+}
+
+// Close unsubscribes everyone and waits for async subscribers to drain
+// their buffers.
+func (b *Bus) Close() {
+	b.mu.RLock()
+	var ids []int
+	for _, s := range b.subs {
+		ids = append(ids, s.id)
+	}
+	b.mu.RUnlock()
+	for _, id := range ids {
+		b.remove(id)
+	}
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+	b.workers.Wait()
+}
+
+func (b *Bus) stripe(id uuid.UUID) int {
+	return int(maphash.Bytes(b.seed, id[:]) % eventStripes)
+}
+
+// lockEntities takes the stripes covering events, in ascending order so
+// two publishers can't deadlock, and returns the unlock.
+func (b *Bus) lockEntities(events []ComponentEvent) func() {
+	var held []int
+	for _, e := range events {
+		held = append(held, b.stripe(e.EntityID))
+	}
+	slices.Sort(held)
+	held = slices.Compact(held)
+	for _, i := range held {
+		b.stripes[i].Lock()
+	}
+	return func() {
+		for _, i := range held {
+			b.stripes[i].Unlock()
+		}
+	}
+}
+
+// Publish delivers events, in order, to every interested subscriber.
+// InTx calls it for you; call it directly only for events that didn't
+// come from a database write.
+func (b *Bus) Publish(events ...ComponentEvent) {
+	if len(events) == 0 {
+		return
+	}
+	unlock := b.lockEntities(events)
+	defer unlock()
+	b.deliver(events)
+}
+
+// deliver runs with the events' stripes held.
+func (b *Bus) deliver(events []ComponentEvent) {
+	b.mu.RLock()
+	subs := slices.Clone(b.subs)
+	b.mu.RUnlock()
+	for _, e := range events {
+		for _, s := range subs {
+			if !s.wants(e.Type) {
+				continue
+			}
+			if s.queue != nil {
+				s.queue <- e
+			} else {
+				s.fn(e)
+			}
+		}
+	}
+}
+
+// InTx runs fn in a transaction whose component writes — SetComponent,
+// RemoveComponent, CreateEntity's initial components, and those
+// DestroyEntity and RestoreEntity make — are recorded as events, and
+// publishes them once the transaction commits. If fn fails or the
+// commit does, nothing is published. tick stamps events for writes that
+// don't carry their own, such as removals.
+//
+// The entities' stripes are held from just before the commit until
+// delivery finishes. A second transaction writing the same component
+// waits on the row lock for the first to commit, then on the stripe for
+// its events to go out, so per-entity order always matches commit order.
+func (b *Bus) InTx(ctx context.Context, tb TxBeginner, tick int64, fn func(ctx context.Context, tx pgx.Tx) error) error {
+	tx, err := tb.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rec := &eventRecorder{tick: tick}
+	if err := fn(context.WithValue(ctx, recorderKey{}, rec), tx); err != nil {
+		return err
+	}
+
+	unlock := b.lockEntities(rec.events)
+	defer unlock()
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	b.deliver(rec.events)
+	return nil
+}
+
+// previousState returns the decoded component typ on id, or nil if there
+// is none, locking the row so it can't change before the caller's write.
+// It's only called when someone is listening.
+func previousState(ctx context.Context, q ComponentQuerier, id uuid.UUID, typ string) (Component, error) {
+	row, err := q.GetComponentForUpdate(ctx, sqlc.GetComponentForUpdateParams{
+		EntityID:      pgtype.UUID{Bytes: id, Valid: true},
+		ComponentType: typ,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read previous %s: %w", typ, err)
+	}
+	return DecodeAny(typ, row.State)
+}
@@ -0,0 +1,228 @@
+package game_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+	"github.com/dukerupert/walking-drum/internal/game"
+	"github.com/dukerupert/walking-drum/internal/testdb"
+)
+
+func TestBus_SyncAndAsync(t *testing.T) {
+	bus := game.NewBus()
+	id := uuid.UUID{15: 1}
+
+	var direct []game.ComponentEvent
+	bus.Subscribe(func(e game.ComponentEvent) { direct = append(direct, e) })
+	var (
+		mu    sync.Mutex
+		async []game.ComponentEvent
+	)
+	bus.SubscribeAsync(1, func(e game.ComponentEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		async = append(async, e)
+	})
+
+	events := []game.ComponentEvent{
+		{Kind: game.ComponentAdded, EntityID: id, Type: game.ComponentHidden, New: game.Hidden{}, Tick: 1},
+		{Kind: game.ComponentRemoved, EntityID: id, Type: game.ComponentHidden, Old: game.Hidden{}, Tick: 2},
+	}
+	bus.Publish(events...)
+	if !slices.Equal(direct, events) {
+		t.Errorf("sync: got %+v, want %+v", direct, events)
+	}
+	bus.Close() // drains the async buffer
+	if !slices.Equal(async, events) {
+		t.Errorf("async: got %+v, want %+v", async, events)
+	}
+}
+
+func TestBus_TypeFilterAndUnsubscribe(t *testing.T) {
+	bus := game.NewBus()
+	defer bus.Close()
+	id := uuid.UUID{15: 1}
+
+	var got []string
+	unsubscribe := bus.Subscribe(func(e game.ComponentEvent) { got = append(got, e.Type) }, game.ComponentOnFire)
+
+	bus.Publish(
+		game.ComponentEvent{Kind: game.ComponentAdded, EntityID: id, Type: game.ComponentHidden, New: game.Hidden{}},
+		game.ComponentEvent{Kind: game.ComponentAdded, EntityID: id, Type: game.ComponentOnFire, New: game.OnFire{}},
+	)
+	unsubscribe()
+	unsubscribe() // idempotent
+	bus.Publish(game.ComponentEvent{Kind: game.ComponentRemoved, EntityID: id, Type: game.ComponentOnFire, Old: game.OnFire{}})
+
+	if want := []string{game.ComponentOnFire}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+// Each publisher emits an increasing tick sequence for its own entity;
+// every subscriber must see each entity's ticks in order however the
+// publishers interleave.
+func TestBus_PerEntityOrder(t *testing.T) {
+	const (
+		entities = 8
+		perEnt   = 500
+	)
+	bus := game.NewBus()
+
+	var mu sync.Mutex
+	seen := map[uuid.UUID][]int64{}
+	bus.SubscribeAsync(16, func(e game.ComponentEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		seen[e.EntityID] = append(seen[e.EntityID], e.Tick)
+	})
+
+	var wg sync.WaitGroup
+	for i := range entities {
+		id := uuid.UUID{15: byte(i + 1)}
+		wg.Go(func() {
+			for tick := range int64(perEnt) {
+				bus.Publish(game.ComponentEvent{Kind: game.ComponentChanged, EntityID: id, Type: game.ComponentHidden, Tick: tick})
+			}
+		})
+	}
+	wg.Wait()
+	bus.Close()
+
+	for id, ticks := range seen {
+		if len(ticks) != perEnt || !slices.IsSorted(ticks) {
+			t.Errorf("entity %s: %d events, sorted=%v", id, len(ticks), slices.IsSorted(ticks))
+		}
+	}
+	if len(seen) != entities {
+		t.Errorf("saw %d entities, want %d", len(seen), entities)
+	}
+}
+
+func TestBus_InTx(t *testing.T) {
+	_, tx := testdb.WithTx(t)
+	ctx := context.Background()
+	bus := game.NewBus()
+	defer bus.Close()
+
+	var got []game.ComponentEvent
+	bus.Subscribe(func(e game.ComponentEvent) { got = append(got, e) })
+
+	var id uuid.UUID
+	err := bus.InTx(ctx, tx, 7, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+		id, err = game.CreateEntity(ctx, tx, game.CreateEntityInput{
+			SeasonID: 1, Type: game.EntityNPC, Tick: 7,
+			InitialComponents: []game.Component{game.Hidden{}},
+		})
+		if err != nil {
+			return err
+		}
+		q := sqlc.New(tx)
+		if err := game.SetComponent(ctx, q, id, game.OnFire{Damage: 1}, 7); err != nil {
+			return err
+		}
+		if len(got) != 0 {
+			t.Error("events delivered before commit")
+		}
+		return nil
+	})
+	must(t, err)
+
+	err = bus.InTx(ctx, tx, 8, func(ctx context.Context, tx pgx.Tx) error {
+		q := sqlc.New(tx)
+		must(t, game.SetComponent(ctx, q, id, game.OnFire{Damage: 3}, 8))
+		must(t, game.RemoveComponent[game.Hidden](ctx, q, id))
+		return game.RemoveComponent[game.Hidden](ctx, q, id) // already gone: no event
+	})
+	must(t, err)
+
+	want := []game.ComponentEvent{
+		{Kind: game.ComponentAdded, EntityID: id, Type: game.ComponentHidden, New: game.Hidden{}, Tick: 7},
+		{Kind: game.ComponentAdded, EntityID: id, Type: game.ComponentOnFire, New: game.OnFire{Damage: 1}, Tick: 7},
+		{Kind: game.ComponentChanged, EntityID: id, Type: game.ComponentOnFire, Old: game.OnFire{Damage: 1}, New: game.OnFire{Damage: 3}, Tick: 8},
+		{Kind: game.ComponentRemoved, EntityID: id, Type: game.ComponentHidden, Old: game.Hidden{}, Tick: 8},
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("got %+v\nwant %+v", got, want)
+	}
+
+	// A transaction that fails publishes nothing.
+	got = nil
+	boom := errors.New("boom")
+	err = bus.InTx(ctx, tx, 9, func(ctx context.Context, tx pgx.Tx) error {
+		must(t, game.SetComponent(ctx, sqlc.New(tx), id, game.Hidden{}, 9))
+		return boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("InTx: got %v, want boom", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("rolled-back transaction published %+v", got)
+	}
+	if has, _ := game.HasComponent[game.Hidden](ctx, sqlc.New(tx), id); has {
+		t.Error("rolled-back write is visible")
+	}
+}
+
+// Destroying and restoring an entity inside InTx reports every component
+// they touch.
+func TestBus_InTxDestroyAndRestore(t *testing.T) {
+	_, tx := testdb.WithTx(t)
+	ctx := context.Background()
+	bus := game.NewBus()
+	defer bus.Close()
+
+	admin, _ := uuid.NewV7()
+	if _, err := sqlc.New(tx).CreateAccount(ctx, sqlc.CreateAccountParams{
+		ID: pgtype.UUID{Bytes: admin, Valid: true}, Email: "gm@example.com", DisplayName: "gm", PasswordHash: "x",
+	}); err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	id, err := game.CreateEntity(ctx, tx, game.CreateEntityInput{
+		SeasonID: 1, Type: game.EntityNPC, Tick: 1,
+		InitialComponents: []game.Component{game.Hidden{}, game.OnFire{Damage: 2}},
+	})
+	must(t, err)
+
+	var got []game.ComponentEvent
+	bus.Subscribe(func(e game.ComponentEvent) { got = append(got, e) })
+	must(t, bus.InTx(ctx, tx, 5, func(ctx context.Context, tx pgx.Tx) error {
+		return game.DestroyEntity(ctx, tx, game.DestroyEntityInput{ID: id, Tick: 5, Reason: "killed", StripTransient: true})
+	}))
+	must(t, bus.InTx(ctx, tx, 9, func(ctx context.Context, tx pgx.Tx) error {
+		return game.RestoreEntity(ctx, tx, game.RestoreEntityInput{ID: id, Tick: 9, AdminID: admin, Reason: "misfire"})
+	}))
+
+	want := []struct {
+		kind game.EventKind
+		typ  string
+		tick int64
+	}{
+		{game.ComponentRemoved, game.ComponentOnFire, 5},
+		{game.ComponentAdded, game.ComponentDestroyed, 5},
+		{game.ComponentRemoved, game.ComponentDestroyed, 9},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("events: got %+v, want %+v", got, want)
+	}
+	for i, w := range want {
+		if e := got[i]; e.Kind != w.kind || e.Type != w.typ || e.Tick != w.tick || e.EntityID != id {
+			t.Errorf("event %d: got %+v, want %s %s at %d", i, e, w.kind, w.typ, w.tick)
+		}
+	}
+	if old, ok := got[0].Old.(game.OnFire); !ok || old.Damage != 2 {
+		t.Errorf("stripped on_fire: got Old %+v", got[0].Old)
+	}
+	if d, ok := got[1].New.(game.Destroyed); !ok || d.Reason != "killed" {
+		t.Errorf("destroyed added: got New %+v", got[1].New)
+	}
+}
@@ -0,0 +1,414 @@
+package game
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+)
+
+// componentKey identifies one components row.
+type componentKey struct {
+	entity uuid.UUID
+	typ    string
+}
+
+func compareComponentKeys(a, b componentKey) int {
+	if c := bytes.Compare(a.entity[:], b.entity[:]); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.typ, b.typ)
+}
+
+// dirtySet is what a World has changed since the last TakeChanges. It
+// records only which rows changed; their values are read from the world
+// when the changes are taken, so repeated writes to a row coalesce.
+type dirtySet struct {
+	entities   map[uuid.UUID]struct{}
+	positions  map[uuid.UUID]struct{}
+	components map[componentKey]int64 // tick of the latest change
+}
+
+func newDirtySet() dirtySet {
+	return dirtySet{
+		entities:   map[uuid.UUID]struct{}{},
+		positions:  map[uuid.UUID]struct{}{},
+		components: map[componentKey]int64{},
+	}
+}
+
+func (d dirtySet) forget(id uuid.UUID) {
+	delete(d.entities, id)
+	delete(d.positions, id)
+	for k := range d.components {
+		if k.entity == id {
+			delete(d.components, k)
+		}
+	}
+}
+
+func (d dirtySet) len() int {
+	return len(d.entities) + len(d.positions) + len(d.components)
+}
+
+// Changes is a batch of world writes awaiting persistence, as returned
+// by World.TakeChanges. Each slice is in ID order.
+type Changes struct {
+	// Entities were created in memory and aren't in the database yet.
+	Entities []Entity
+
+	// Positions are upserted; RemovedPositions are deleted.
+	Positions        []Position
+	RemovedPositions []uuid.UUID
+
+	// Components are upserted or, if Removed, deleted.
+	Components []ComponentChange
+}
+
+// ComponentChange is one pending components row write. State is already
+// encoded, so the flusher never touches live component values.
+type ComponentChange struct {
+	EntityID uuid.UUID
+	Type     string
+	State    []byte
+	Tick     int64
+	Removed  bool
+}
+
+// Len is the number of row writes in c.
+func (c Changes) Len() int {
+	return len(c.Entities) + len(c.Positions) + len(c.RemovedPositions) + len(c.Components)
+}
+
+// PendingChanges is the number of rows changed since the last
+// TakeChanges.
+func (w *World) PendingChanges() int { return w.dirty.len() }
+
+// TakeChanges returns everything changed since the last call, with
+// component state encoded, and starts a fresh change set. Call it from
+// the goroutine that owns the world and hand the result to a Flusher. If
+// a component fails to encode, the error is returned and nothing is
+// taken.
+func (w *World) TakeChanges() (Changes, error) {
+	var c Changes
+	for _, id := range sortedKeys(w.dirty.entities) {
+		c.Entities = append(c.Entities, w.entities[id])
+	}
+	for _, id := range sortedKeys(w.dirty.positions) {
+		if p, ok := w.positions[id]; ok {
+			c.Positions = append(c.Positions, p)
+		} else {
+			c.RemovedPositions = append(c.RemovedPositions, id)
+		}
+	}
+	keys := make([]componentKey, 0, len(w.dirty.components))
+	for k := range w.dirty.components {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, compareComponentKeys)
+	for _, k := range keys {
+		ch := ComponentChange{EntityID: k.entity, Type: k.typ, Tick: w.dirty.components[k]}
+		if comp, ok := w.components[k.typ][k.entity]; ok {
+			state, err := EncodeComponent(comp)
+			if err != nil {
+				return Changes{}, fmt.Errorf("take changes: entity %s: %w", k.entity, err)
+			}
+			ch.State = state
+		} else {
+			ch.Removed = true
+		}
+		c.Components = append(c.Components, ch)
+	}
+	w.dirty = newDirtySet()
+	return c, nil
+}
+
+func sortedKeys[V any](m map[uuid.UUID]V) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sortIDs(ids)
+	return ids
+}
+
+// DefaultFlushInterval is within the 10–30s snapshot cadence from
+// DESIGN.md §3.7.
+const DefaultFlushInterval = 15 * time.Second
+
+// FlusherConfig tunes a Flusher. Zero values pick the defaults.
+type FlusherConfig struct {
+	// Interval between flushes in Run. Defaults to DefaultFlushInterval.
+	Interval time.Duration
+
+	// OnFlush receives stats after every successful flush that wrote
+	// something. Nil discards them.
+	OnFlush func(FlushStats)
+
+	// OnError receives errors from flushes made by Run, which keeps the
+	// failed changes queued and tries again next interval. Nil discards
+	// them. Flush returns its error directly instead.
+	OnError func(error)
+}
+
+// FlushStats describes a Flusher's recent work.
+type FlushStats struct {
+	Flushes     int64         // successful flushes that wrote something
+	Failures    int64         // flushes that rolled back
+	LastLatency time.Duration // wall time of the latest flush attempt
+	LastWrites  int           // row writes in the latest successful flush
+	Queued      int           // row writes waiting for the next flush
+}
+
+// Flusher is the write-behind half of DESIGN.md §3.7's persistence
+// cadence: it coalesces World changes and writes them in one transaction
+// per interval, using one array-parameter statement per kind of write.
+// Writes that must be durable before the game acknowledges them — trades,
+// deaths — stay write-through and don't go near it.
+//
+// Enqueue is safe to call from any goroutine, concurrently with a flush.
+type Flusher struct {
+	tb  TxBeginner
+	cfg FlusherConfig
+
+	flushing sync.Mutex // serializes flushes, so batches land in order
+
+	mu      sync.Mutex
+	pending *pendingWrites
+	stats   FlushStats
+}
+
+// NewFlusher returns a Flusher writing through tb.
+func NewFlusher(tb TxBeginner, cfg FlusherConfig) *Flusher {
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultFlushInterval
+	}
+	return &Flusher{tb: tb, cfg: cfg, pending: newPendingWrites()}
+}
+
+// Enqueue adds c to the next flush. A row already queued is replaced by
+// its newer value.
+func (f *Flusher) Enqueue(c Changes) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pending.merge(c, true)
+	f.stats.Queued = f.pending.len()
+}
+
+// Stats returns a snapshot of the flusher's counters.
+func (f *Flusher) Stats() FlushStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.stats
+}
+
+// Run flushes every cfg.Interval until ctx is cancelled, then flushes
+// once more so a graceful shutdown loses nothing, and returns that final
+// flush's error.
+func (f *Flusher) Run(ctx context.Context) error {
+	t := time.NewTicker(f.cfg.Interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return f.Flush(context.WithoutCancel(ctx))
+		case <-t.C:
+		}
+		if err := f.Flush(ctx); err != nil && f.cfg.OnError != nil {
+			f.cfg.OnError(err)
+		}
+	}
+}
+
+// Flush writes everything queued in a single transaction. On failure the
+// batch is requeued behind anything enqueued since, so no change is lost
+// and none overwrites a newer one.
+func (f *Flusher) Flush(ctx context.Context) error {
+	f.flushing.Lock()
+	defer f.flushing.Unlock()
+
+	f.mu.Lock()
+	batch := f.pending
+	f.pending = newPendingWrites()
+	f.mu.Unlock()
+	if batch.len() == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	err := f.write(ctx, batch)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stats.LastLatency = time.Since(start)
+	if err != nil {
+		f.pending.merge(batch.changes(), false)
+		f.stats.Failures++
+		f.stats.Queued = f.pending.len()
+		return err
+	}
+	f.stats.Flushes++
+	f.stats.LastWrites = batch.len()
+	f.stats.Queued = f.pending.len()
+	if f.cfg.OnFlush != nil {
+		f.cfg.OnFlush(f.stats)
+	}
+	return nil
+}
+
+func (f *Flusher) write(ctx context.Context, batch *pendingWrites) error {
+	tx, err := f.tb.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("flush: begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	q := sqlc.New(tx)
+	c := batch.changes()
+
+	// Entities first, so positions and components for them have a row to
+	// reference.
+	if len(c.Entities) > 0 {
+		var p sqlc.InsertEntitiesBatchParams
+		for _, e := range c.Entities {
+			p.Ids = append(p.Ids, pgtype.UUID{Bytes: e.ID, Valid: true})
+			p.SeasonIds = append(p.SeasonIds, e.SeasonID)
+			p.EntityTypes = append(p.EntityTypes, string(e.Type))
+			p.CreatedAtTicks = append(p.CreatedAtTicks, e.CreatedAtTick)
+		}
+		if _, err := q.InsertEntitiesBatch(ctx, p); err != nil {
+			return fmt.Errorf("flush: insert entities: %w", err)
+		}
+	}
+
+	if len(c.RemovedPositions) > 0 {
+		ids := make([]pgtype.UUID, len(c.RemovedPositions))
+		for i, id := range c.RemovedPositions {
+			ids[i] = pgtype.UUID{Bytes: id, Valid: true}
+		}
+		if _, err := q.DeleteEntityPositionsBatch(ctx, ids); err != nil {
+			return fmt.Errorf("flush: delete positions: %w", err)
+		}
+	}
+	if len(c.Positions) > 0 {
+		var p sqlc.UpsertEntityPositionsBatchParams
+		for _, pos := range c.Positions {
+			p.EntityIds = append(p.EntityIds, pgtype.UUID{Bytes: pos.EntityID, Valid: true})
+			p.RegionIds = append(p.RegionIds, pos.RegionID)
+			p.Xs = append(p.Xs, pos.X)
+			p.Ys = append(p.Ys, pos.Y)
+			p.UpdatedAtTicks = append(p.UpdatedAtTicks, pos.UpdatedAtTick)
+		}
+		if _, err := q.UpsertEntityPositionsBatch(ctx, p); err != nil {
+			return fmt.Errorf("flush: upsert positions: %w", err)
+		}
+	}
+
+	var (
+		del sqlc.DeleteComponentsBatchParams
+		set sqlc.UpsertComponentsBatchParams
+	)
+	for _, ch := range c.Components {
+		id := pgtype.UUID{Bytes: ch.EntityID, Valid: true}
+		if ch.Removed {
+			del.EntityIds = append(del.EntityIds, id)
+			del.ComponentTypes = append(del.ComponentTypes, ch.Type)
+			continue
+		}
+		set.EntityIds = append(set.EntityIds, id)
+		set.ComponentTypes = append(set.ComponentTypes, ch.Type)
+		set.States = append(set.States, ch.State)
+		set.Ticks = append(set.Ticks, ch.Tick)
+	}
+	if len(del.EntityIds) > 0 {
+		if _, err := q.DeleteComponentsBatch(ctx, del); err != nil {
+			return fmt.Errorf("flush: delete components: %w", err)
+		}
+	}
+	if len(set.EntityIds) > 0 {
+		if _, err := q.UpsertComponentsBatch(ctx, set); err != nil {
+			return fmt.Errorf("flush: upsert components: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("flush: commit: %w", err)
+	}
+	return nil
+}
+
+// pendingWrites is the flusher's coalesced queue, keyed by row.
+type pendingWrites struct {
+	entities   map[uuid.UUID]Entity
+	positions  map[uuid.UUID]*Position // nil = delete
+	components map[componentKey]ComponentChange
+}
+
+func newPendingWrites() *pendingWrites {
+	return &pendingWrites{
+		entities:   map[uuid.UUID]Entity{},
+		positions:  map[uuid.UUID]*Position{},
+		components: map[componentKey]ComponentChange{},
+	}
+}
+
+func (p *pendingWrites) len() int {
+	return len(p.entities) + len(p.positions) + len(p.components)
+}
+
+// merge adds c's rows. With overwrite false, rows already queued win —
+// that's how a failed batch goes back in behind newer changes.
+func (p *pendingWrites) merge(c Changes, overwrite bool) {
+	for _, e := range c.Entities {
+		if _, ok := p.entities[e.ID]; overwrite || !ok {
+			p.entities[e.ID] = e
+		}
+	}
+	for _, pos := range c.Positions {
+		if _, ok := p.positions[pos.EntityID]; overwrite || !ok {
+			p.positions[pos.EntityID] = &pos
+		}
+	}
+	for _, id := range c.RemovedPositions {
+		if _, ok := p.positions[id]; overwrite || !ok {
+			p.positions[id] = nil
+		}
+	}
+	for _, ch := range c.Components {
+		k := componentKey{ch.EntityID, ch.Type}
+		if _, ok := p.components[k]; overwrite || !ok {
+			p.components[k] = ch
+		}
+	}
+}
+
+// changes flattens p back into ID-ordered Changes.
+func (p *pendingWrites) changes() Changes {
+	var c Changes
+	for _, id := range sortedKeys(p.entities) {
+		c.Entities = append(c.Entities, p.entities[id])
+	}
+	for _, id := range sortedKeys(p.positions) {
+		if pos := p.positions[id]; pos != nil {
+			c.Positions = append(c.Positions, *pos)
+		} else {
+			c.RemovedPositions = append(c.RemovedPositions, id)
+		}
+	}
+	keys := make([]componentKey, 0, len(p.components))
+	for k := range p.components {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, compareComponentKeys)
+	for _, k := range keys {
+		c.Components = append(c.Components, p.components[k])
+	}
+	return c
+}
@@ -0,0 +1,72 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// stalledBeginner signals when Begin is entered, then fails once
+// released, so a test can act while a flush is in flight.
+type stalledBeginner struct {
+	entered, release chan struct{}
+}
+
+var errBeginFailed = errors.New("begin failed")
+
+func (b stalledBeginner) Begin(context.Context) (pgx.Tx, error) {
+	b.entered <- struct{}{}
+	<-b.release
+	return nil, errBeginFailed
+}
+
+func TestFlushFailureRequeuesBehindNewerChanges(t *testing.T) {
+	id := uuid.UUID{15: 1}
+	tb := stalledBeginner{entered: make(chan struct{}), release: make(chan struct{})}
+	f := NewFlusher(tb, FlusherConfig{})
+	f.Enqueue(Changes{
+		Positions:  []Position{{EntityID: id, X: 1}},
+		Components: []ComponentChange{{EntityID: id, Type: ComponentHidden, State: []byte("{}")}},
+	})
+
+	done := make(chan error)
+	go func() { done <- f.Flush(context.Background()) }()
+	<-tb.entered
+	// Something newer for the position arrives while the flush is failing.
+	f.Enqueue(Changes{Positions: []Position{{EntityID: id, X: 2}}})
+	close(tb.release)
+	if err := <-done; !errors.Is(err, errBeginFailed) {
+		t.Fatalf("Flush: got %v, want errBeginFailed", err)
+	}
+
+	st := f.Stats()
+	if st.Failures != 1 || st.Flushes != 0 || st.Queued != 2 {
+		t.Errorf("stats: got %+v, want 1 failure, 2 queued", st)
+	}
+	if got := f.pending.positions[id]; got == nil || got.X != 2 {
+		t.Errorf("requeued position: got %+v, want the newer X=2", got)
+	}
+	if _, ok := f.pending.components[componentKey{id, ComponentHidden}]; !ok {
+		t.Error("failed component write was dropped")
+	}
+}
+
+func TestPendingWritesCoalesce(t *testing.T) {
+	id := uuid.UUID{15: 1}
+	p := newPendingWrites()
+	p.merge(Changes{Positions: []Position{{EntityID: id, X: 1}}}, true)
+	p.merge(Changes{RemovedPositions: []uuid.UUID{id}}, true)
+	p.merge(Changes{Components: []ComponentChange{{EntityID: id, Type: "a", Tick: 1}}}, true)
+	p.merge(Changes{Components: []ComponentChange{{EntityID: id, Type: "a", Tick: 2, Removed: true}}}, true)
+
+	c := p.changes()
+	if len(c.Positions) != 0 || len(c.RemovedPositions) != 1 {
+		t.Errorf("positions: got %+v / %v, want one removal", c.Positions, c.RemovedPositions)
+	}
+	if len(c.Components) != 1 || !c.Components[0].Removed || c.Components[0].Tick != 2 {
+		t.Errorf("components: got %+v, want the tick-2 removal", c.Components)
+	}
+}
@@ -0,0 +1,138 @@
+package game_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+	"github.com/dukerupert/walking-drum/internal/game"
+	"github.com/dukerupert/walking-drum/internal/testdb"
+)
+
+func TestTakeChanges(t *testing.T) {
+	w, ids := newTestWorld(t, 3)
+	a, b, c := ids[0], ids[1], ids[2]
+	if n := w.PendingChanges(); n != 3 {
+		t.Fatalf("PendingChanges after AddEntity: got %d, want 3", n)
+	}
+	if _, err := w.TakeChanges(); err != nil {
+		t.Fatal(err)
+	}
+
+	w.SetTick(10)
+	must(t, w.SetPosition(game.Position{EntityID: a, X: 1}))
+	must(t, w.SetPosition(game.Position{EntityID: a, X: 2})) // coalesces
+	must(t, w.SetPosition(game.Position{EntityID: b, X: 1}))
+	w.RemovePosition(b)
+	must(t, w.SetComponent(a, worldBurning{Intensity: 1}))
+	w.SetTick(11)
+	must(t, w.SetComponent(a, worldBurning{Intensity: 2}))
+	must(t, w.SetComponent(b, game.Hidden{}))
+	w.RemoveComponent(b, game.ComponentHidden)
+	must(t, w.SetComponent(c, game.Hidden{}))
+	w.RemoveEntity(c) // evicted; its change goes with it
+
+	ch, err := w.TakeChanges()
+	if err != nil {
+		t.Fatalf("TakeChanges: %v", err)
+	}
+	if len(ch.Entities) != 0 {
+		t.Errorf("Entities: got %v, want none after the first take", ch.Entities)
+	}
+	if len(ch.Positions) != 1 || ch.Positions[0].X != 2 {
+		t.Errorf("Positions: got %+v, want a at X=2", ch.Positions)
+	}
+	if len(ch.RemovedPositions) != 1 || ch.RemovedPositions[0] != b {
+		t.Errorf("RemovedPositions: got %v, want [b]", ch.RemovedPositions)
+	}
+	want := []game.ComponentChange{
+		{EntityID: a, Type: "test_world_burning", State: []byte(`{"intensity":2}`), Tick: 11},
+		{EntityID: b, Type: game.ComponentHidden, Tick: 11, Removed: true},
+	}
+	if len(ch.Components) != len(want) {
+		t.Fatalf("Components: got %+v, want %+v", ch.Components, want)
+	}
+	for i, got := range ch.Components {
+		exp := want[i]
+		if got.EntityID != exp.EntityID || got.Type != exp.Type || string(got.State) != string(exp.State) ||
+			got.Tick != exp.Tick || got.Removed != exp.Removed {
+			t.Errorf("Components[%d]: got %+v, want %+v", i, got, exp)
+		}
+	}
+
+	if n := w.PendingChanges(); n != 0 {
+		t.Errorf("PendingChanges after take: got %d, want 0", n)
+	}
+}
+
+func TestFlusherWritesWorldChanges(t *testing.T) {
+	_, tx := testdb.WithTx(t)
+	ctx := context.Background()
+	q := sqlc.New(tx)
+
+	existing, err := game.CreateEntity(ctx, tx, game.CreateEntityInput{
+		SeasonID:          1,
+		Type:              game.EntityNPC,
+		Tick:              1,
+		Position:          &game.PositionSpec{RegionID: 1, X: 0, Y: 0},
+		InitialComponents: []game.Component{game.Hidden{}},
+	})
+	if err != nil {
+		t.Fatalf("CreateEntity: %v", err)
+	}
+	w, err := game.LoadWorld(ctx, q, 1)
+	if err != nil {
+		t.Fatalf("LoadWorld: %v", err)
+	}
+	if n := w.PendingChanges(); n != 0 {
+		t.Fatalf("PendingChanges after load: got %d, want 0", n)
+	}
+
+	spawned, _ := uuid.NewV7()
+	w.SetTick(20)
+	must(t, w.AddEntity(game.Entity{ID: spawned, Type: game.EntityItem, CreatedAtTick: 20}))
+	must(t, w.SetPosition(game.Position{EntityID: spawned, RegionID: 1, X: 4, Y: 4, UpdatedAtTick: 20}))
+	must(t, w.SetComponent(spawned, worldBurning{Intensity: 9}))
+	must(t, w.SetPosition(game.Position{EntityID: existing, RegionID: 1, X: 5, Y: 6, UpdatedAtTick: 20}))
+	w.RemoveComponent(existing, game.ComponentHidden)
+
+	changes, err := w.TakeChanges()
+	if err != nil {
+		t.Fatalf("TakeChanges: %v", err)
+	}
+	f := game.NewFlusher(tx, game.FlusherConfig{})
+	f.Enqueue(changes)
+	if st := f.Stats(); st.Queued != changes.Len() {
+		t.Errorf("Queued: got %d, want %d", st.Queued, changes.Len())
+	}
+	if err := f.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if st := f.Stats(); st.Flushes != 1 || st.Queued != 0 || st.LastWrites != changes.Len() {
+		t.Errorf("stats: got %+v", st)
+	}
+
+	pg := func(id uuid.UUID) pgtype.UUID { return pgtype.UUID{Bytes: id, Valid: true} }
+	if _, err := q.GetEntityByID(ctx, pg(spawned)); err != nil {
+		t.Errorf("spawned entity not inserted: %v", err)
+	}
+	if p, err := q.GetEntityPosition(ctx, pg(existing)); err != nil || p.X != 5 || p.Y != 6 {
+		t.Errorf("moved position: got %+v, %v", p, err)
+	}
+	if got, err := game.GetComponent[worldBurning](ctx, q, spawned); err != nil || got.Intensity != 9 {
+		t.Errorf("spawned component: got %+v, %v", got, err)
+	}
+	if has, err := game.HasComponent[game.Hidden](ctx, q, existing); err != nil || has {
+		t.Errorf("removed component: got %v, %v; want gone", has, err)
+	}
+}
+
+func must(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
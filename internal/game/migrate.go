@@ -0,0 +1,113 @@
+package game
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+)
+
+// DefaultMigrateBatchSize is how many rows MigrateComponents rewrites
+// per transaction.
+const DefaultMigrateBatchSize = 500
+
+// MigrateReport summarizes a MigrateComponents run. Scanned counts the
+// candidate rows examined; Rewritten the ones that actually changed.
+type MigrateReport struct {
+	ComponentType string
+	Version       int
+	Codec         string
+	Batches       int
+	Scanned       int64
+	Rewritten     int64
+}
+
+// MigrateComponents rewrites every stored row of component type typ
+// that is below the registered version or stored with a different codec
+// than the registered one, so the upgrade cost is paid once instead of
+// on every read. Each batch of up to batchSize rows is its own
+// transaction, holding row locks only for that batch; the game can keep
+// running, and an interrupted run is resumed by running it again.
+// Decoding goes through the type's Go struct, so the rewritten state is
+// exactly what EncodeComponent would produce today.
+func MigrateComponents(ctx context.Context, tb TxBeginner, typ string, batchSize int32) (MigrateReport, error) {
+	info, ok := LookupComponent(typ)
+	if !ok {
+		return MigrateReport{}, fmt.Errorf("migrate components: %w: %q", ErrUnknownComponent, typ)
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultMigrateBatchSize
+	}
+	rep := MigrateReport{ComponentType: typ, Version: info.Version, Codec: info.Codec.Name()}
+
+	var after pgtype.UUID
+	after.Valid = true // the zero UUID sorts first
+	for {
+		scanned, rewritten, last, err := migrateBatch(ctx, tb, info, after, batchSize)
+		if err != nil {
+			return rep, err
+		}
+		if scanned == 0 {
+			return rep, nil
+		}
+		rep.Batches++
+		rep.Scanned += scanned
+		rep.Rewritten += rewritten
+		after = last
+	}
+}
+
+// migrateBatch rewrites one keyset page and returns how many rows it
+// examined and rewrote, and the last entity_id seen.
+func migrateBatch(ctx context.Context, tb TxBeginner, info ComponentInfo, after pgtype.UUID, batchSize int32) (scanned, rewritten int64, last pgtype.UUID, err error) {
+	last = after
+	tx, err := tb.Begin(ctx)
+	if err != nil {
+		return 0, 0, last, fmt.Errorf("begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	q := sqlc.New(tx)
+
+	rows, err := q.ListStaleComponents(ctx, sqlc.ListStaleComponentsParams{
+		ComponentType: info.Type,
+		After:         after,
+		JsonCodec:     info.Codec == JSONCodec,
+		Version:       int32(info.Version),
+		BatchSize:     batchSize,
+	})
+	if err != nil {
+		return 0, 0, last, fmt.Errorf("list stale %s components: %w", info.Type, err)
+	}
+	for _, r := range rows {
+		last = r.EntityID
+		stale, err := needsRewrite(info, r.State)
+		if err != nil {
+			return 0, 0, last, fmt.Errorf("entity %s: %w", r.EntityID, err)
+		}
+		if !stale {
+			continue
+		}
+		c, err := info.decode(r.State)
+		if err != nil {
+			return 0, 0, last, fmt.Errorf("entity %s: %w", r.EntityID, err)
+		}
+		state, err := EncodeComponent(c)
+		if err != nil {
+			return 0, 0, last, err
+		}
+		if err := q.RewriteComponentState(ctx, sqlc.RewriteComponentStateParams{
+			EntityID:      r.EntityID,
+			ComponentType: info.Type,
+			State:         state,
+		}); err != nil {
+			return 0, 0, last, fmt.Errorf("rewrite %s on %s: %w", info.Type, r.EntityID, err)
+		}
+		rewritten++
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, 0, last, fmt.Errorf("commit: %w", err)
+	}
+	return int64(len(rows)), rewritten, last, nil
+}
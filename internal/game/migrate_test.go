@@ -0,0 +1,86 @@
+package game_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+	"github.com/dukerupert/walking-drum/internal/game"
+	"github.com/dukerupert/walking-drum/internal/testdb"
+)
+
+// migrateProbe is at v2: v1 stored {"n": x}, v2 stores {"count": x}.
+type migrateProbe struct {
+	Count int `json:"count"`
+}
+
+func (migrateProbe) ComponentType() string { return "test_migrate_probe" }
+
+func init() {
+	game.RegisterComponent[migrateProbe](game.WithVersion(2, func(state []byte) ([]byte, error) {
+		var v1 struct {
+			N int `json:"n"`
+		}
+		if err := json.Unmarshal(state, &v1); err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string]int{"count": v1.N})
+	}))
+}
+
+func TestMigrateComponents(t *testing.T) {
+	_, tx := testdb.WithTx(t)
+	ctx := context.Background()
+	q := sqlc.New(tx)
+
+	// Five v1 rows and one already at v2.
+	for i := range 6 {
+		id, err := game.CreateEntity(ctx, tx, game.CreateEntityInput{SeasonID: 1, Type: game.EntityItem, Tick: 1})
+		if err != nil {
+			t.Fatalf("CreateEntity: %v", err)
+		}
+		state := fmt.Appendf(nil, `{"n":%d}`, i)
+		if i == 5 {
+			state = []byte(`{"_v":2,"count":5}`)
+		}
+		if _, err := q.SetComponent(ctx, sqlc.SetComponentParams{
+			EntityID:      pgtype.UUID{Bytes: id, Valid: true},
+			ComponentType: "test_migrate_probe",
+			State:         state,
+			CreatedAtTick: 1,
+			UpdatedAtTick: 1,
+		}); err != nil {
+			t.Fatalf("SetComponent: %v", err)
+		}
+	}
+
+	rep, err := game.MigrateComponents(ctx, tx, "test_migrate_probe", 2)
+	if err != nil {
+		t.Fatalf("MigrateComponents: %v", err)
+	}
+	if rep.Rewritten != 5 || rep.Batches != 3 {
+		t.Errorf("report: got %+v, want 5 rows in 3 batches", rep)
+	}
+
+	again, err := game.MigrateComponents(ctx, tx, "test_migrate_probe", 2)
+	if err != nil {
+		t.Fatalf("MigrateComponents (second run): %v", err)
+	}
+	if again.Rewritten != 0 {
+		t.Errorf("second run rewrote %d rows, want 0", again.Rewritten)
+	}
+
+	rows, err := q.ListEntitiesWithComponent(ctx, "test_migrate_probe")
+	if err != nil {
+		t.Fatalf("ListEntitiesWithComponent: %v", err)
+	}
+	for _, r := range rows {
+		if stale, _ := game.NeedsUpgrade(r.ComponentType, r.State); stale || r.UpdatedAtTick != 1 {
+			t.Errorf("row %s after migrate: state %s updated_at_tick %d", r.EntityID, r.State, r.UpdatedAtTick)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package game
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ErrUnknownPrefab is returned for a prefab key no loaded file defines.
+var ErrUnknownPrefab = errors.New("game: unknown prefab")
+
+// Prefab is a resolved entity template: its inheritance chain applied,
+// every component decoded and validated against the registry.
+type Prefab struct {
+	Key        string
+	Type       EntityType
+	Components []Component // sorted by component type
+}
+
+// prefabDef is one entry in a prefab file. A file is a JSON object of
+// key → prefabDef:
+//
+//	{
+//	  "goblin": {
+//	    "extends": "monster",
+//	    "type": "npc",
+//	    "components": {"hidden": {}, "loot_table": {"table": "goblin"}}
+//	  }
+//	}
+//
+// A child inherits its parent's type and components. A component the
+// child also names is merged field by field, child winning; naming it
+// as null drops it.
+type prefabDef struct {
+	Extends    string                     `json:"extends,omitempty"`
+	Type       EntityType                 `json:"type,omitempty"`
+	Components map[string]json.RawMessage `json:"components,omitempty"`
+
+	file string
+}
+
+// Prefabs is a loaded, validated set of prefabs. It is read-only after
+// LoadPrefabs and safe for concurrent use.
+type Prefabs struct {
+	byKey map[string]Prefab
+}
+
+// LoadPrefabs reads every *.json file under fsys, resolves inheritance,
+// and validates the lot: unknown fields, a missing or invalid type,
+// unregistered component types, component state that doesn't decode
+// strictly into its Go type, duplicate keys, and inheritance cycles are
+// all errors. Call it at startup so bad content stops the binary rather
+// than a spawn.
+func LoadPrefabs(fsys fs.FS) (*Prefabs, error) {
+	defs := map[string]prefabDef{}
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".json") {
+			return err
+		}
+		raw, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+		var file map[string]prefabDef
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&file); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		for key, def := range file {
+			if prev, ok := defs[key]; ok {
+				return fmt.Errorf("%s: prefab %q already defined in %s", path, key, prev.file)
+			}
+			def.file = path
+			defs[key] = def
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load prefabs: %w", err)
+	}
+
+	r := prefabResolver{defs: defs, done: map[string]prefabDef{}, visiting: map[string]bool{}}
+	p := &Prefabs{byKey: make(map[string]Prefab, len(defs))}
+	var errs []error
+	for _, key := range slices.Sorted(maps.Keys(defs)) {
+		prefab, err := r.build(key)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		p.byKey[key] = prefab
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("load prefabs: %w", errors.Join(errs...))
+	}
+	return p, nil
+}
+
+// Get returns the prefab named key.
+func (p *Prefabs) Get(key string) (Prefab, bool) {
+	prefab, ok := p.byKey[key]
+	return prefab, ok
+}
+
+// Keys lists every prefab key, sorted.
+func (p *Prefabs) Keys() []string {
+	return slices.Sorted(maps.Keys(p.byKey))
+}
+
+// PrefabSpawn says where and when to spawn a prefab. Overrides replace
+// the prefab's component of the same type, or add one it lacks.
+type PrefabSpawn struct {
+	SeasonID  int32
+	Tick      int64
+	Position  *PositionSpec
+	Overrides []Component
+}
+
+// Input expands prefab key into a CreateEntityInput, for callers that
+// batch spawns through CreateEntities.
+func (p *Prefabs) Input(key string, at PrefabSpawn) (CreateEntityInput, error) {
+	prefab, ok := p.byKey[key]
+	if !ok {
+		return CreateEntityInput{}, fmt.Errorf("%w: %q", ErrUnknownPrefab, key)
+	}
+	comps := slices.Clone(prefab.Components)
+	for _, o := range at.Overrides {
+		if o == nil {
+			return CreateEntityInput{}, fmt.Errorf("prefab %q: nil override", key)
+		}
+		if i := slices.IndexFunc(comps, func(c Component) bool { return c.ComponentType() == o.ComponentType() }); i >= 0 {
+			comps[i] = o
+		} else {
+			comps = append(comps, o)
+		}
+	}
+	return CreateEntityInput{
+		SeasonID:          at.SeasonID,
+		Type:              prefab.Type,
+		Tick:              at.Tick,
+		Position:          at.Position,
+		InitialComponents: comps,
+	}, nil
+}
+
+// SpawnPrefab creates one entity from prefab key via CreateEntity.
+func SpawnPrefab(ctx context.Context, tb TxBeginner, p *Prefabs, key string, at PrefabSpawn) (uuid.UUID, error) {
+	in, err := p.Input(key, at)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	id, err := CreateEntity(ctx, tb, in)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("spawn prefab %q: %w", key, err)
+	}
+	return id, nil
+}
+
+// prefabResolver flattens inheritance chains, memoizing each key.
+type prefabResolver struct {
+	defs     map[string]prefabDef
+	done     map[string]prefabDef
+	visiting map[string]bool
+}
+
+// flatten returns key's definition with every ancestor merged in.
+func (r *prefabResolver) flatten(key string) (prefabDef, error) {
+	if def, ok := r.done[key]; ok {
+		return def, nil
+	}
+	def, ok := r.defs[key]
+	if !ok {
+		return prefabDef{}, fmt.Errorf("%w: %q", ErrUnknownPrefab, key)
+	}
+	if r.visiting[key] {
+		return prefabDef{}, fmt.Errorf("prefab %q: inheritance cycle", key)
+	}
+	if def.Extends != "" {
+		r.visiting[key] = true
+		parent, err := r.flatten(def.Extends)
+		delete(r.visiting, key)
+		if err != nil {
+			return prefabDef{}, fmt.Errorf("prefab %q extends %w", key, err)
+		}
+		def = mergePrefabDefs(parent, def)
+	}
+	r.done[key] = def
+	return def, nil
+}
+
+// build flattens key and decodes its components.
+func (r *prefabResolver) build(key string) (Prefab, error) {
+	def, err := r.flatten(key)
+	if err != nil {
+		return Prefab{}, err
+	}
+	where := fmt.Sprintf("%s: prefab %q", r.defs[key].file, key)
+	if !def.Type.Valid() {
+		return Prefab{}, fmt.Errorf("%s: invalid or missing type %q", where, def.Type)
+	}
+	prefab := Prefab{Key: key, Type: def.Type}
+	for _, typ := range slices.Sorted(maps.Keys(def.Components)) {
+		c, err := decodePrefabComponent(typ, def.Components[typ])
+		if err != nil {
+			return Prefab{}, fmt.Errorf("%s: component %q: %w", where, typ, err)
+		}
+		prefab.Components = append(prefab.Components, c)
+	}
+	return prefab, nil
+}
+
+// mergePrefabDefs applies child on top of parent. null component state
+// in the child removes the parent's component.
+func mergePrefabDefs(parent, child prefabDef) prefabDef {
+	out := child
+	if out.Type == "" {
+		out.Type = parent.Type
+	}
+	out.Components = maps.Clone(parent.Components)
+	if out.Components == nil {
+		out.Components = map[string]json.RawMessage{}
+	}
+	for typ, state := range child.Components {
+		if bytes.Equal(bytes.TrimSpace(state), []byte("null")) {
+			delete(out.Components, typ)
+			continue
+		}
+		out.Components[typ] = mergeJSONObjects(out.Components[typ], state)
+	}
+	return out
+}
+
+// mergeJSONObjects overlays child's top-level fields on parent's. If
+// either isn't an object, child replaces parent outright.
+func mergeJSONObjects(parent, child json.RawMessage) json.RawMessage {
+	var p, c map[string]json.RawMessage
+	if json.Unmarshal(parent, &p) != nil || json.Unmarshal(child, &c) != nil || p == nil || c == nil {
+		return child
+	}
+	maps.Copy(p, c)
+	merged, err := json.Marshal(p)
+	if err != nil {
+		return child
+	}
+	return merged
+}
+
+// decodePrefabComponent strictly decodes hand-written state into typ's
+// registered Go type: unknown fields are typos, not forward
+// compatibility, in content files.
+func decodePrefabComponent(typ string, state json.RawMessage) (Component, error) {
+	info, ok := LookupComponent(typ)
+	if !ok {
+		return nil, ErrUnknownComponent
+	}
+	v := reflect.New(info.GoType)
+	dec := json.NewDecoder(bytes.NewReader(state))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v.Interface()); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("trailing data after state")
+	}
+	return v.Elem().Interface().(Component), nil
+}
@@ -0,0 +1,147 @@
+package game_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+	"github.com/dukerupert/walking-drum/internal/game"
+	"github.com/dukerupert/walking-drum/internal/testdb"
+)
+
+type prefabStats struct {
+	HP    int    `json:"hp"`
+	Speed int    `json:"speed"`
+	Name  string `json:"name"`
+}
+
+func (prefabStats) ComponentType() string { return "test_prefab_stats" }
+
+func init() {
+	game.RegisterComponent[prefabStats]()
+}
+
+var testPrefabs = fstest.MapFS{
+	"monsters.json": {Data: []byte(`{
+		"monster": {
+			"type": "npc",
+			"components": {
+				"test_prefab_stats": {"hp": 10, "speed": 100, "name": "monster"},
+				"hidden": {}
+			}
+		},
+		"goblin": {
+			"extends": "monster",
+			"components": {
+				"test_prefab_stats": {"hp": 7, "name": "goblin"},
+				"hidden": null
+			}
+		}
+	}`)},
+	"nested/objects.json": {Data: []byte(`{
+		"campfire": {
+			"type": "world_object",
+			"components": {"test_world_burning": {"intensity": 2}}
+		}
+	}`)},
+	"README.md": {Data: []byte("not a prefab file")},
+}
+
+func TestLoadPrefabs(t *testing.T) {
+	p, err := game.LoadPrefabs(testPrefabs)
+	if err != nil {
+		t.Fatalf("LoadPrefabs: %v", err)
+	}
+	if got := strings.Join(p.Keys(), ","); got != "campfire,goblin,monster" {
+		t.Errorf("Keys: got %s", got)
+	}
+
+	goblin, ok := p.Get("goblin")
+	if !ok {
+		t.Fatal("goblin not loaded")
+	}
+	if goblin.Type != game.EntityNPC {
+		t.Errorf("inherited type: got %q, want npc", goblin.Type)
+	}
+	if len(goblin.Components) != 1 {
+		t.Fatalf("components: got %+v, want only stats (hidden dropped by null)", goblin.Components)
+	}
+	if got := goblin.Components[0].(prefabStats); got != (prefabStats{HP: 7, Speed: 100, Name: "goblin"}) {
+		t.Errorf("merged stats: got %+v", got)
+	}
+
+	in, err := p.Input("goblin", game.PrefabSpawn{
+		SeasonID:  1,
+		Tick:      5,
+		Overrides: []game.Component{prefabStats{HP: 1}, game.Hidden{}},
+	})
+	if err != nil {
+		t.Fatalf("Input: %v", err)
+	}
+	if len(in.InitialComponents) != 2 || in.InitialComponents[0].(prefabStats).HP != 1 {
+		t.Errorf("overrides: got %+v", in.InitialComponents)
+	}
+	if got, _ := p.Get("goblin"); got.Components[0].(prefabStats).HP != 7 {
+		t.Error("Input's overrides leaked into the loaded prefab")
+	}
+	if _, err := p.Input("dragon", game.PrefabSpawn{}); !errors.Is(err, game.ErrUnknownPrefab) {
+		t.Errorf("Input unknown: got %v, want ErrUnknownPrefab", err)
+	}
+}
+
+func TestLoadPrefabsRejectsBadContent(t *testing.T) {
+	for name, tc := range map[string]struct {
+		files fstest.MapFS
+		want  string
+	}{
+		"unregistered component": {fstest.MapFS{"a.json": {Data: []byte(`{"x": {"type": "npc", "components": {"test_nope": {}}}}`)}}, "unknown component"},
+		"unknown field":          {fstest.MapFS{"a.json": {Data: []byte(`{"x": {"type": "npc", "components": {"test_prefab_stats": {"hpp": 1}}}}`)}}, "hpp"},
+		"missing type":           {fstest.MapFS{"a.json": {Data: []byte(`{"x": {}}`)}}, "missing type"},
+		"missing parent":         {fstest.MapFS{"a.json": {Data: []byte(`{"x": {"extends": "y", "type": "npc"}}`)}}, "unknown prefab"},
+		"cycle":                  {fstest.MapFS{"a.json": {Data: []byte(`{"x": {"extends": "y"}, "y": {"extends": "x"}}`)}}, "cycle"},
+		"typo in def":            {fstest.MapFS{"a.json": {Data: []byte(`{"x": {"type": "npc", "component": {}}}`)}}, "unknown field"},
+		"duplicate key": {fstest.MapFS{
+			"a.json": {Data: []byte(`{"x": {"type": "npc"}}`)},
+			"b.json": {Data: []byte(`{"x": {"type": "npc"}}`)},
+		}, "already defined"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := game.LoadPrefabs(tc.files)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("LoadPrefabs: got %v, want error containing %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestShippedPrefabsLoad(t *testing.T) {
+	if _, err := game.LoadPrefabs(os.DirFS("../../content/prefabs")); err != nil {
+		t.Fatalf("content/prefabs: %v", err)
+	}
+}
+
+func TestSpawnPrefab(t *testing.T) {
+	_, tx := testdb.WithTx(t)
+	ctx := context.Background()
+
+	p, err := game.LoadPrefabs(testPrefabs)
+	if err != nil {
+		t.Fatalf("LoadPrefabs: %v", err)
+	}
+	id, err := game.SpawnPrefab(ctx, tx, p, "campfire", game.PrefabSpawn{
+		SeasonID: 1,
+		Tick:     3,
+		Position: &game.PositionSpec{RegionID: 1, X: 4, Y: 5},
+	})
+	if err != nil {
+		t.Fatalf("SpawnPrefab: %v", err)
+	}
+	q := sqlc.New(tx)
+	if got, err := game.GetComponent[worldBurning](ctx, q, id); err != nil || got.Intensity != 2 {
+		t.Errorf("spawned component: got %+v, %v", got, err)
+	}
+}
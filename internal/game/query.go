@@ -0,0 +1,199 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+)
+
+// DefaultQueryPageSize and MaxQueryPageSize bound EntityQuery.PageSize.
+const (
+	DefaultQueryPageSize = 200
+	MaxQueryPageSize     = 5000
+)
+
+// ErrInvalidQuery wraps every EntityQuery validation failure.
+var ErrInvalidQuery = errors.New("game: invalid entity query")
+
+// EntityQuery selects live entities in a season by the components they
+// carry: every type in With, none in Without, and, if Types is set, one
+// of those entity types. It is the database-side counterpart to
+// World.Query, for systems that run over more entities than are loaded.
+type EntityQuery struct {
+	SeasonID int32
+	Types    []EntityType
+	With     []string
+	Without  []string
+
+	// PageSize is how many entities each page holds. Zero means
+	// DefaultQueryPageSize.
+	PageSize int
+}
+
+// EntityMatch is one entity an EntityQuery found, with its With
+// components decoded.
+type EntityMatch struct {
+	ID            uuid.UUID
+	Type          EntityType
+	CreatedAtTick int64
+	Components    map[string]Component
+}
+
+// MatchComponent returns m's component of type T, which must be one of
+// the query's With types to be present.
+func MatchComponent[T Component](m EntityMatch) (T, bool) {
+	c, ok := m.Components[componentType[T]()].(T)
+	return c, ok
+}
+
+// EntityPage is one page of an EntityQuery. Pass Next as the cursor for
+// the following page; Done means there isn't one.
+type EntityPage struct {
+	Matches []EntityMatch
+	Next    uuid.UUID
+	Done    bool
+}
+
+// EntityQuerier is the subset of *sqlc.Queries entity queries need.
+type EntityQuerier interface {
+	QueryEntitiesByComponents(ctx context.Context, arg sqlc.QueryEntitiesByComponentsParams) ([]sqlc.QueryEntitiesByComponentsRow, error)
+	ListComponentsForEntities(ctx context.Context, arg sqlc.ListComponentsForEntitiesParams) ([]sqlc.ListComponentsForEntitiesRow, error)
+}
+
+var _ EntityQuerier = (*sqlc.Queries)(nil)
+
+// normalize validates eq and returns it with filters sorted and
+// deduplicated. With must name at least one type — an unfiltered scan
+// belongs to ListEntitiesByTypeInSeason — and every component type must
+// be registered, so a typo fails loudly instead of matching nothing.
+func (eq EntityQuery) normalize() (EntityQuery, error) {
+	if len(eq.With) == 0 {
+		return eq, fmt.Errorf("%w: With is empty", ErrInvalidQuery)
+	}
+	switch {
+	case eq.PageSize == 0:
+		eq.PageSize = DefaultQueryPageSize
+	case eq.PageSize < 0 || eq.PageSize > MaxQueryPageSize:
+		return eq, fmt.Errorf("%w: page size %d not in [1, %d]", ErrInvalidQuery, eq.PageSize, MaxQueryPageSize)
+	}
+	for _, t := range eq.Types {
+		if !t.Valid() {
+			return eq, fmt.Errorf("%w: entity type %q", ErrInvalidQuery, t)
+		}
+	}
+	eq.With = slices.Compact(slices.Sorted(slices.Values(eq.With)))
+	eq.Without = slices.Compact(slices.Sorted(slices.Values(eq.Without)))
+	for _, typ := range append(slices.Clone(eq.With), eq.Without...) {
+		if _, ok := LookupComponent(typ); !ok {
+			return eq, fmt.Errorf("%w: %w: %q", ErrInvalidQuery, ErrUnknownComponent, typ)
+		}
+	}
+	for _, typ := range eq.Without {
+		if slices.Contains(eq.With, typ) {
+			return eq, fmt.Errorf("%w: %q is in both With and Without", ErrInvalidQuery, typ)
+		}
+	}
+	return eq, nil
+}
+
+// Page returns the matches after cursor, in ID order; uuid.Nil starts
+// from the beginning. Pages are independent reads, so an entity that
+// starts or stops matching mid-iteration may or may not be seen — the
+// usual keyset trade for never holding the whole set.
+func (eq EntityQuery) Page(ctx context.Context, q EntityQuerier, after uuid.UUID) (EntityPage, error) {
+	eq, err := eq.normalize()
+	if err != nil {
+		return EntityPage{}, err
+	}
+	types := make([]string, len(eq.Types))
+	for i, t := range eq.Types {
+		types[i] = string(t)
+	}
+	without := eq.Without
+	if without == nil {
+		without = []string{} // NULL would turn the filter into NULL too
+	}
+
+	// One extra row tells us whether another page exists. The first
+	// With type drives the keyset; the rest are checked per candidate.
+	rows, err := q.QueryEntitiesByComponents(ctx, sqlc.QueryEntitiesByComponentsParams{
+		LeadType:     eq.With[0],
+		WithTypes:    eq.With[1:],
+		After:        pgtype.UUID{Bytes: after, Valid: true},
+		SeasonID:     eq.SeasonID,
+		EntityTypes:  types,
+		WithoutTypes: without,
+		PageSize:     int32(eq.PageSize + 1),
+	})
+	if err != nil {
+		return EntityPage{}, fmt.Errorf("query entities: %w", err)
+	}
+	page := EntityPage{Done: len(rows) <= eq.PageSize}
+	rows = rows[:min(len(rows), eq.PageSize)]
+	if len(rows) == 0 {
+		page.Next = after
+		return page, nil
+	}
+
+	ids := make([]pgtype.UUID, len(rows))
+	byID := make(map[uuid.UUID]int, len(rows))
+	page.Matches = make([]EntityMatch, len(rows))
+	for i, r := range rows {
+		ids[i] = r.ID
+		byID[r.ID.Bytes] = i
+		page.Matches[i] = EntityMatch{
+			ID:            r.ID.Bytes,
+			Type:          EntityType(r.EntityType),
+			CreatedAtTick: r.CreatedAtTick,
+			Components:    make(map[string]Component, len(eq.With)),
+		}
+	}
+	page.Next = page.Matches[len(rows)-1].ID
+
+	comps, err := q.ListComponentsForEntities(ctx, sqlc.ListComponentsForEntitiesParams{
+		EntityIds:      ids,
+		ComponentTypes: eq.With,
+	})
+	if err != nil {
+		return EntityPage{}, fmt.Errorf("load components: %w", err)
+	}
+	for _, c := range comps {
+		comp, err := DecodeAny(c.ComponentType, c.State)
+		if err != nil {
+			return EntityPage{}, fmt.Errorf("entity %s: %w", uuid.UUID(c.EntityID.Bytes), err)
+		}
+		page.Matches[byID[c.EntityID.Bytes]].Components[c.ComponentType] = comp
+	}
+	return page, nil
+}
+
+// All iterates every match page by page, holding one page in memory at a
+// time. Iteration stops at the first error, which is yielded.
+func (eq EntityQuery) All(ctx context.Context, q EntityQuerier) iter.Seq2[EntityMatch, error] {
+	return func(yield func(EntityMatch, error) bool) {
+		var after uuid.UUID
+		for {
+			page, err := eq.Page(ctx, q, after)
+			if err != nil {
+				yield(EntityMatch{}, err)
+				return
+			}
+			for _, m := range page.Matches {
+				if !yield(m, nil) {
+					return
+				}
+			}
+			if page.Done {
+				return
+			}
+			after = page.Next
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package game_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+	"github.com/dukerupert/walking-drum/internal/game"
+	"github.com/dukerupert/walking-drum/internal/testdb"
+)
+
+func TestEntityQueryValidation(t *testing.T) {
+	for name, eq := range map[string]game.EntityQuery{
+		"no with":        {SeasonID: 1},
+		"unregistered":   {SeasonID: 1, With: []string{"test_no_such_component"}},
+		"with & without": {SeasonID: 1, With: []string{game.ComponentHidden}, Without: []string{game.ComponentHidden}},
+		"bad type":       {SeasonID: 1, With: []string{game.ComponentHidden}, Types: []game.EntityType{"ghost"}},
+		"page too big":   {SeasonID: 1, With: []string{game.ComponentHidden}, PageSize: game.MaxQueryPageSize + 1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// A nil querier: validation must fail before any query runs.
+			if _, err := eq.Page(context.Background(), nil, uuid.Nil); !errors.Is(err, game.ErrInvalidQuery) {
+				t.Errorf("Page: got %v, want ErrInvalidQuery", err)
+			}
+		})
+	}
+}
+
+func TestEntityQuery(t *testing.T) {
+	_, tx := testdb.WithTx(t)
+	ctx := context.Background()
+	q := sqlc.New(tx)
+
+	create := func(typ game.EntityType, comps ...game.Component) uuid.UUID {
+		t.Helper()
+		id, err := game.CreateEntity(ctx, tx, game.CreateEntityInput{
+			SeasonID: 1, Type: typ, Tick: 1, InitialComponents: comps,
+		})
+		if err != nil {
+			t.Fatalf("CreateEntity: %v", err)
+		}
+		return id
+	}
+	var want []uuid.UUID
+	for i := range 5 {
+		want = append(want, create(game.EntityNPC, worldBurning{Intensity: i}, game.Hidden{}))
+	}
+	create(game.EntityNPC, worldBurning{Intensity: 99})                 // missing Hidden
+	create(game.EntityItem, worldBurning{Intensity: 99}, game.Hidden{}) // wrong entity type
+	create(game.EntityNPC, game.Hidden{})                               // missing burning
+
+	eq := game.EntityQuery{
+		SeasonID: 1,
+		Types:    []game.EntityType{game.EntityNPC},
+		With:     []string{"test_world_burning", game.ComponentHidden},
+		PageSize: 2,
+	}
+	var (
+		got   []uuid.UUID
+		pages int
+		after uuid.UUID
+	)
+	for {
+		page, err := eq.Page(ctx, q, after)
+		if err != nil {
+			t.Fatalf("Page: %v", err)
+		}
+		pages++
+		for _, m := range page.Matches {
+			got = append(got, m.ID)
+			b, ok := game.MatchComponent[worldBurning](m)
+			if !ok || b.Intensity == 99 {
+				t.Errorf("match %s: burning component %+v, %v", m.ID, b, ok)
+			}
+		}
+		if page.Done {
+			break
+		}
+		after = page.Next
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("paged matches: got %v, want %v", got, want)
+	}
+	if pages != 3 {
+		t.Errorf("pages: got %d, want 3", pages)
+	}
+
+	// Without: only the NPC lacking Hidden is left.
+	eq = game.EntityQuery{SeasonID: 1, Types: []game.EntityType{game.EntityNPC}, With: []string{"test_world_burning"}, Without: []string{game.ComponentHidden}}
+	var n int
+	for m, err := range eq.All(ctx, q) {
+		if err != nil {
+			t.Fatalf("All: %v", err)
+		}
+		if b, _ := game.MatchComponent[worldBurning](m); b.Intensity != 99 {
+			t.Errorf("Without: matched %+v", m)
+		}
+		n++
+	}
+	if n != 1 {
+		t.Errorf("Without: got %d matches, want 1", n)
+	}
+}
+
+// Matches interleaved with another season's entities, and with
+// destroyed ones, page by season and liveness alone.
+func TestEntityQuery_AcrossSeasons(t *testing.T) {
+	_, tx := testdb.WithTx(t)
+	ctx := context.Background()
+	q := sqlc.New(tx)
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO seasons (id, name, status, world_seed, starts_at, ends_at)
+		VALUES (2, 'Season 2', 'upcoming', 7, '2026-12-01', '2027-03-01')
+	`); err != nil {
+		t.Fatalf("insert season 2: %v", err)
+	}
+	create := func(seasonID int32) uuid.UUID {
+		t.Helper()
+		id, err := game.CreateEntity(ctx, tx, game.CreateEntityInput{
+			SeasonID: seasonID, Type: game.EntityNPC, Tick: 1, InitialComponents: []game.Component{game.Hidden{}},
+		})
+		if err != nil {
+			t.Fatalf("CreateEntity: %v", err)
+		}
+		return id
+	}
+	var want []uuid.UUID
+	for i := range 7 {
+		// UUIDv7 IDs interleave the seasons in ID order.
+		id := create(1)
+		create(2)
+		if i == 3 {
+			must(t, game.DestroyEntity(ctx, tx, game.DestroyEntityInput{ID: id, Tick: 2, Reason: "killed"}))
+			continue
+		}
+		want = append(want, id)
+	}
+
+	eq := game.EntityQuery{SeasonID: 1, With: []string{game.ComponentHidden}, PageSize: 2}
+	var (
+		got   []uuid.UUID
+		pages int
+	)
+	for m, err := range eq.All(ctx, q) {
+		if err != nil {
+			t.Fatalf("All: %v", err)
+		}
+		got = append(got, m.ID)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("matches: got %v, want %v", got, want)
+	}
+	for after := uuid.Nil; ; pages++ {
+		page, err := eq.Page(ctx, q, after)
+		must(t, err)
+		if page.Done {
+			break
+		}
+		after = page.Next
+	}
+	if pages != 2 {
+		t.Errorf("full pages: got %d, want 2", pages)
+	}
+}
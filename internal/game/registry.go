@@ -0,0 +1,170 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownComponent is returned when a component_type string has no
+// registered Go type — usually a row written by a newer build, or a
+// type that was renamed without a data migration.
+var ErrUnknownComponent = errors.New("game: unknown component type")
+
+// ComponentInfo is what the registry knows about one component type.
+type ComponentInfo struct {
+	// Type is the components.component_type string.
+	Type string
+
+	// GoType is the registered struct type, for debug output.
+	GoType reflect.Type
+
+	// Version is the current schema version of the encoded state; 1
+	// unless set by WithVersion.
+	Version int
+
+	// Codec writes the type's state; JSONCodec unless set by WithCodec.
+	Codec Codec
+
+	// Transient components describe a live entity's momentary state
+	// (burning, stunned) and mean nothing once it's destroyed. Set by the
+	// Transient option.
+	Transient bool
+
+	decode       func(raw []byte) (Component, error)
+	upgraders    []Upgrader
+	fieldHistory map[int][]string
+}
+
+// ComponentOption adjusts a registration. Options are how later layers
+// attach per-type policy (transience, versioning) without widening
+// RegisterComponent's signature.
+type ComponentOption func(*ComponentInfo)
+
+var registry = struct {
+	sync.RWMutex
+	byType map[string]ComponentInfo
+}{byType: map[string]ComponentInfo{}}
+
+// RegisterComponent adds T to the registry under T's ComponentType().
+// Call it from an init func next to the type's declaration. It panics
+// on an empty type string or a second registration of the same string:
+// both are programming errors that should stop the binary at startup,
+// not surface as a decode failure mid-game.
+func RegisterComponent[T Component](opts ...ComponentOption) {
+	var zero T
+	typ := zero.ComponentType()
+	if typ == "" {
+		panic(fmt.Sprintf("game: RegisterComponent[%T]: empty component type", zero))
+	}
+	info := ComponentInfo{
+		Type:    typ,
+		GoType:  reflect.TypeFor[T](),
+		Version: 1,
+		Codec:   JSONCodec,
+		decode: func(raw []byte) (Component, error) {
+			var v T
+			if err := decodeState(typ, raw, &v); err != nil {
+				return nil, err
+			}
+			return v, nil
+		},
+	}
+	for _, opt := range opts {
+		opt(&info)
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+	if prev, ok := registry.byType[typ]; ok {
+		panic(fmt.Sprintf("game: component type %q registered twice (%v and %v)", typ, prev.GoType, info.GoType))
+	}
+	registry.byType[typ] = info
+}
+
+// LookupComponent returns the registration for typ.
+func LookupComponent(typ string) (ComponentInfo, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	info, ok := registry.byType[typ]
+	return info, ok
+}
+
+// RegisteredComponentTypes returns every registered type string, sorted.
+func RegisteredComponentTypes() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	types := make([]string, 0, len(registry.byType))
+	for typ := range registry.byType {
+		types = append(types, typ)
+	}
+	slices.Sort(types)
+	return types
+}
+
+// Transient marks a component type as transient: DestroyEntity strips it
+// when asked to.
+func Transient() ComponentOption {
+	return func(info *ComponentInfo) { info.Transient = true }
+}
+
+// TransientComponentTypes lists the registered transient types, sorted.
+func TransientComponentTypes() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	var types []string
+	for typ, info := range registry.byType {
+		if info.Transient {
+			types = append(types, typ)
+		}
+	}
+	slices.Sort(types)
+	return types
+}
+
+// DecodeAny decodes a components row without the caller knowing its Go
+// type — for admin and debug views that list whatever an entity has.
+// Gameplay code should use GetComponent[T] instead.
+func DecodeAny(typ string, raw []byte) (Component, error) {
+	info, ok := LookupComponent(typ)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownComponent, typ)
+	}
+	return info.decode(raw)
+}
+
+// checkRegistered is the write-side guard: a type that isn't registered
+// could be written but never read back by DecodeAny or the startup
+// check, so refuse it up front.
+func checkRegistered(c Component) error {
+	if _, ok := LookupComponent(c.ComponentType()); !ok {
+		return fmt.Errorf("%w: %q (%T); add a RegisterComponent call", ErrUnknownComponent, c.ComponentType(), c)
+	}
+	return nil
+}
+
+// unregistered returns the entries of types with no registration.
+func unregistered(types []string) []string {
+	var missing []string
+	for _, typ := range types {
+		if _, ok := LookupComponent(typ); !ok {
+			missing = append(missing, typ)
+		}
+	}
+	return missing
+}
+
+// UnregisteredComponentsError is returned by VerifyComponentRegistry
+// when the database holds component types this binary can't decode.
+type UnregisteredComponentsError struct {
+	Types []string
+}
+
+func (e *UnregisteredComponentsError) Error() string {
+	return fmt.Sprintf("game: components table has unregistered types: %s", strings.Join(e.Types, ", "))
+}
+
+func (e *UnregisteredComponentsError) Unwrap() error { return ErrUnknownComponent }
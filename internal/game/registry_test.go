@@ -0,0 +1,84 @@
+package game
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// registryProbe is registered only by this test file, under a type
+// string no real component will ever use.
+type registryProbe struct {
+	N int `json:"n"`
+}
+
+func (registryProbe) ComponentType() string { return "test_registry_probe" }
+
+type registryProbeDup struct{}
+
+func (registryProbeDup) ComponentType() string { return "test_registry_probe" }
+
+type emptyTypeProbe struct{}
+
+func (emptyTypeProbe) ComponentType() string { return "" }
+
+func init() {
+	RegisterComponent[registryProbe]()
+}
+
+func TestHiddenIsRegistered(t *testing.T) {
+	info, ok := LookupComponent(ComponentHidden)
+	if !ok {
+		t.Fatal("Hidden is not registered")
+	}
+	if info.GoType.Name() != "Hidden" {
+		t.Errorf("GoType: got %v, want Hidden", info.GoType)
+	}
+	if !slices.Contains(RegisteredComponentTypes(), ComponentHidden) {
+		t.Errorf("RegisteredComponentTypes() = %v, missing hidden", RegisteredComponentTypes())
+	}
+}
+
+func TestDecodeAny(t *testing.T) {
+	c, err := DecodeAny("test_registry_probe", []byte(`{"n":7}`))
+	if err != nil {
+		t.Fatalf("DecodeAny: %v", err)
+	}
+	if got, ok := c.(registryProbe); !ok || got.N != 7 {
+		t.Errorf("DecodeAny: got %#v, want registryProbe{N: 7}", c)
+	}
+
+	if _, err := DecodeAny("no_such_component", []byte("{}")); !errors.Is(err, ErrUnknownComponent) {
+		t.Errorf("DecodeAny(unknown): got %v, want ErrUnknownComponent", err)
+	}
+	if _, err := DecodeAny("test_registry_probe", []byte(`{"n":"x"}`)); err == nil {
+		t.Error("DecodeAny(bad state): got nil error")
+	}
+}
+
+func TestRegisterComponentPanics(t *testing.T) {
+	for name, register := range map[string]func(){
+		"duplicate type": func() { RegisterComponent[registryProbeDup]() },
+		"empty type":     func() { RegisterComponent[emptyTypeProbe]() },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic")
+				}
+			}()
+			register()
+		})
+	}
+}
+
+func TestUnregistered(t *testing.T) {
+	got := unregistered([]string{ComponentHidden, "ghost", "test_registry_probe", "wraith"})
+	if !slices.Equal(got, []string{"ghost", "wraith"}) {
+		t.Errorf("unregistered: got %v, want [ghost wraith]", got)
+	}
+	err := error(&UnregisteredComponentsError{Types: got})
+	if !errors.Is(err, ErrUnknownComponent) {
+		t.Errorf("UnregisteredComponentsError doesn't match ErrUnknownComponent")
+	}
+}
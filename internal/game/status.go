@@ -0,0 +1,162 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// StackRule says what happens when an effect lands on an entity that
+// already has one of the same type.
+type StackRule int
+
+const (
+	// StackRefresh replaces the existing effect: the duration restarts
+	// and the new effect's fields win.
+	StackRefresh StackRule = iota
+
+	// StackIntensity adds a stack (up to MaxStacks) and restarts the
+	// duration.
+	StackIntensity
+
+	// StackIgnore leaves the existing effect alone.
+	StackIgnore
+)
+
+// EffectState is the bookkeeping every status effect carries. Effects
+// embed it, so its fields sit alongside the effect's own in the stored
+// JSON.
+type EffectState struct {
+	StartTick int64 `json:"start_tick"`
+	Duration  int64 `json:"duration"`
+	Stacks    int32 `json:"stacks"`
+}
+
+// ExpiresAt is the last tick the effect is active on.
+func (s EffectState) ExpiresAt() int64 { return s.StartTick + s.Duration }
+
+// Active reports whether the effect is still in force at tick.
+func (s EffectState) Active(tick int64) bool { return tick <= s.ExpiresAt() }
+
+// StatusEffect is a Component with a lifetime. Implementations embed
+// EffectState and are registered with RegisterStatusEffect.
+type StatusEffect interface {
+	Component
+
+	// Status returns the embedded EffectState; WithStatus returns a copy
+	// of the effect carrying s instead.
+	Status() EffectState
+	WithStatus(s EffectState) StatusEffect
+
+	// Stacking is the effect's StackRule, and MaxStacks the cap for
+	// StackIntensity.
+	Stacking() StackRule
+	MaxStacks() int32
+
+	// OnTick runs once for every tick after the one the effect was
+	// applied on, through ExpiresAt, and returns the change to the
+	// entity's HP (negative for damage).
+	OnTick(tick int64) int32
+}
+
+// Status returns s itself, so effects embedding EffectState get the
+// StatusEffect accessor for free.
+func (s EffectState) Status() EffectState { return s }
+
+var statusEffects = struct {
+	sync.RWMutex
+	types []string
+}{}
+
+// RegisterStatusEffect registers T as a component, transient (effects
+// don't outlive their entity), and as a status effect ProcessEffects
+// walks every tick.
+func RegisterStatusEffect[T StatusEffect](opts ...ComponentOption) {
+	RegisterComponent[T](append([]ComponentOption{Transient()}, opts...)...)
+	statusEffects.Lock()
+	defer statusEffects.Unlock()
+	statusEffects.types = append(statusEffects.types, componentType[T]())
+	slices.Sort(statusEffects.types)
+}
+
+// StatusEffectTypes lists the registered status effect types, sorted.
+func StatusEffectTypes() []string {
+	statusEffects.RLock()
+	defer statusEffects.RUnlock()
+	return slices.Clone(statusEffects.types)
+}
+
+// ApplyEffect puts e on entity id at the world's current tick, following
+// e's StackRule if the entity already has one. e's StartTick and Stacks
+// are set here; the caller sets Duration and the effect's own fields.
+// It reports whether anything changed.
+func ApplyEffect(w *World, id uuid.UUID, e StatusEffect) (bool, error) {
+	tick := w.Tick()
+	s := e.Status()
+	if s.Duration <= 0 {
+		return false, fmt.Errorf("apply %s: duration %d must be positive", e.ComponentType(), s.Duration)
+	}
+	s.StartTick, s.Stacks = tick, 1
+
+	if cur, ok := w.Component(id, e.ComponentType()); ok {
+		prev := cur.(StatusEffect).Status()
+		if prev.Active(tick) {
+			switch e.Stacking() {
+			case StackIgnore:
+				return false, nil
+			case StackIntensity:
+				s.Stacks = min(prev.Stacks+1, max(e.MaxStacks(), 1))
+			}
+		}
+	}
+	if err := w.SetComponent(id, e.WithStatus(s)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// EffectTick is what one effect did on one tick.
+type EffectTick struct {
+	EntityID uuid.UUID
+	Effect   string
+	HPDelta  int32
+	Expired  bool
+}
+
+// ProcessEffects advances every status effect in w by one tick, at the
+// world's current tick: each active effect's OnTick runs, and effects
+// past their last tick are removed, which deletes their component row
+// on the next flush. Results come back in effect-type, then entity,
+// order; the caller applies the HP deltas. Call it once per tick.
+func ProcessEffects(w *World) []EffectTick {
+	tick := w.Tick()
+	var out []EffectTick
+	for _, typ := range StatusEffectTypes() {
+		for _, id := range w.Query(typ) {
+			c, _ := w.Component(id, typ)
+			e := c.(StatusEffect)
+			s := e.Status()
+			res := EffectTick{EntityID: id, Effect: typ}
+			if tick > s.StartTick && s.Active(tick) {
+				res.HPDelta = e.OnTick(tick)
+			}
+			if tick >= s.ExpiresAt() {
+				w.RemoveComponent(id, typ)
+				res.Expired = true
+			}
+			if res.HPDelta != 0 || res.Expired {
+				out = append(out, res)
+			}
+		}
+	}
+	return out
+}
+
+// HasEffect reports whether id carries an active T at the world's
+// current tick.
+func HasEffect[T StatusEffect](w *World, id uuid.UUID) bool {
+	e, ok := Get[T](w, id)
+	return ok && e.Status().Active(w.Tick())
+}
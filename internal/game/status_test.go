@@ -0,0 +1,155 @@
+package game_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/dukerupert/walking-drum/internal/game"
+)
+
+// effectClock drives a world tick by tick, the way the region loop
+// will, and collects ProcessEffects' results.
+type effectClock struct {
+	t *testing.T
+	w *game.World
+}
+
+func (c effectClock) at(tick int64) []game.EffectTick {
+	c.t.Helper()
+	c.w.SetTick(tick)
+	return game.ProcessEffects(c.w)
+}
+
+// hp sums the HP deltas for one entity over ticks from..to inclusive.
+func (c effectClock) hp(id uuid.UUID, from, to int64) int32 {
+	c.t.Helper()
+	var sum int32
+	for tick := from; tick <= to; tick++ {
+		for _, r := range c.at(tick) {
+			if r.EntityID == id {
+				sum += r.HPDelta
+			}
+		}
+	}
+	return sum
+}
+
+func newEffectWorld(t *testing.T) (effectClock, uuid.UUID) {
+	t.Helper()
+	w, ids := newTestWorld(t, 1)
+	w.SetTick(10)
+	return effectClock{t, w}, ids[0]
+}
+
+func apply(t *testing.T, w *game.World, id uuid.UUID, e game.StatusEffect) bool {
+	t.Helper()
+	changed, err := game.ApplyEffect(w, id, e)
+	if err != nil {
+		t.Fatalf("ApplyEffect(%s): %v", e.ComponentType(), err)
+	}
+	return changed
+}
+
+func TestOnFireBurnsForDurationThenExpires(t *testing.T) {
+	clock, id := newEffectWorld(t)
+	apply(t, clock.w, id, game.OnFire{EffectState: game.EffectState{Duration: 3}, Damage: 2})
+
+	if got := clock.hp(id, 10, 12); got != -4 {
+		t.Errorf("ticks 10–12: got %d HP, want -4 (no damage on the tick it lands)", got)
+	}
+	res := clock.at(13)
+	if len(res) != 1 || res[0].HPDelta != -2 || !res[0].Expired {
+		t.Fatalf("tick 13: got %+v, want final burn and expiry", res)
+	}
+	if game.Has[game.OnFire](clock.w, id) {
+		t.Error("OnFire still attached after expiry")
+	}
+	if res := clock.at(14); len(res) != 0 {
+		t.Errorf("tick 14: got %+v, want nothing", res)
+	}
+}
+
+func TestStackRules(t *testing.T) {
+	t.Run("refresh", func(t *testing.T) {
+		clock, id := newEffectWorld(t)
+		apply(t, clock.w, id, game.OnFire{EffectState: game.EffectState{Duration: 3}, Damage: 1})
+		clock.at(12)
+		if !apply(t, clock.w, id, game.OnFire{EffectState: game.EffectState{Duration: 3}, Damage: 5}) {
+			t.Fatal("refresh reported no change")
+		}
+		got, _ := game.Get[game.OnFire](clock.w, id)
+		if got.StartTick != 12 || got.ExpiresAt() != 15 || got.Damage != 5 || got.Stacks != 1 {
+			t.Errorf("after refresh: got %+v", got)
+		}
+	})
+
+	t.Run("intensity", func(t *testing.T) {
+		clock, id := newEffectWorld(t)
+		for range game.MaxPoisonStacks + 2 {
+			apply(t, clock.w, id, game.Poisoned{EffectState: game.EffectState{Duration: 10}, Damage: 1})
+		}
+		got, _ := game.Get[game.Poisoned](clock.w, id)
+		if got.Stacks != game.MaxPoisonStacks {
+			t.Errorf("stacks: got %d, want cap %d", got.Stacks, game.MaxPoisonStacks)
+		}
+		if hp := clock.hp(id, 11, 11); hp != -game.MaxPoisonStacks {
+			t.Errorf("damage at full stacks: got %d, want %d", hp, -game.MaxPoisonStacks)
+		}
+	})
+
+	t.Run("ignore", func(t *testing.T) {
+		clock, id := newEffectWorld(t)
+		apply(t, clock.w, id, game.Stunned{EffectState: game.EffectState{Duration: 2}})
+		clock.at(11)
+		if apply(t, clock.w, id, game.Stunned{EffectState: game.EffectState{Duration: 5}}) {
+			t.Error("second stun changed something")
+		}
+		if !game.HasEffect[game.Stunned](clock.w, id) {
+			t.Error("HasEffect during stun: got false")
+		}
+		clock.at(12)
+		if game.HasEffect[game.Stunned](clock.w, id) {
+			t.Error("HasEffect after stun expired: got true")
+		}
+		// Once the first stun is gone, a new one lands.
+		if !apply(t, clock.w, id, game.Stunned{EffectState: game.EffectState{Duration: 1}}) {
+			t.Error("stun after expiry didn't apply")
+		}
+	})
+}
+
+func TestRegeneratingHeals(t *testing.T) {
+	clock, id := newEffectWorld(t)
+	apply(t, clock.w, id, game.Regenerating{EffectState: game.EffectState{Duration: 4}, Heal: 3})
+	if got := clock.hp(id, 10, 20); got != 12 {
+		t.Errorf("total heal: got %d, want 12", got)
+	}
+}
+
+func TestEffectsAreTransientAndPersistExpiry(t *testing.T) {
+	transient := game.TransientComponentTypes()
+	for _, typ := range game.StatusEffectTypes() {
+		if !slices.Contains(transient, typ) {
+			t.Errorf("%s is not transient", typ)
+		}
+	}
+
+	clock, id := newEffectWorld(t)
+	if _, err := game.ApplyEffect(clock.w, id, game.OnFire{}); err == nil {
+		t.Error("ApplyEffect with zero duration: got nil error")
+	}
+	apply(t, clock.w, id, game.OnFire{EffectState: game.EffectState{Duration: 1}, Damage: 1})
+	if _, err := clock.w.TakeChanges(); err != nil {
+		t.Fatal(err)
+	}
+	clock.at(11)
+	ch, err := clock.w.TakeChanges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ch.Components) != 1 || ch.Components[0].Type != game.ComponentOnFire || !ch.Components[0].Removed {
+		t.Errorf("changes after expiry: got %+v, want the on_fire row deleted", ch.Components)
+	}
+}
@@ -3,6 +3,7 @@ package game
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/dukerupert/walking-drum/internal/db/sqlc"
 )
@@ -26,13 +27,15 @@ type SweepConfig struct {
 // Querier is the subset of *sqlc.Queries the sweep needs. Narrowed so
 // callers can pass either a pool-backed or tx-backed instance.
 type Querier interface {
-	SweepDestroyedEntities(ctx context.Context, destroyedAtTick *int64) (int64, error)
+	SweepDestroyedEntities(ctx context.Context, arg sqlc.SweepDestroyedEntitiesParams) (int64, error)
 }
 
 // SweepDestroyedEntities hard-deletes entities whose destroyed_at_tick
 // is older than (currentTick - cfg.RetentionTicks). Returns the number
-// of rows deleted. Cascading FKs handle dependent rows
-// (entity_positions, components).
+// of rows deleted. Each entity is copied into entity_archive first (see
+// GetArchivedEntity), then cascading FKs handle dependent rows
+// (entity_positions, components). Everything eligible goes in one
+// statement; at season scale use a SweepRunner, which batches.
 //
 // No-ops (returns 0, nil) when cfg.Enabled is false — the production
 // default until §6.6's preconditions are met.
@@ -44,7 +47,10 @@ func SweepDestroyedEntities(ctx context.Context, q Querier, currentTick int64, c
 		return 0, fmt.Errorf("sweep: negative retention (%d)", cfg.RetentionTicks)
 	}
 	cutoff := currentTick - cfg.RetentionTicks
-	n, err := q.SweepDestroyedEntities(ctx, &cutoff)
+	n, err := q.SweepDestroyedEntities(ctx, sqlc.SweepDestroyedEntitiesParams{
+		Cutoff:         &cutoff,
+		ArchivedAtTick: currentTick,
+	})
 	if err != nil {
 		return 0, fmt.Errorf("sweep destroyed entities: %w", err)
 	}
@@ -55,3 +61,175 @@ func SweepDestroyedEntities(ctx context.Context, q Querier, currentTick int64, c
 // need. If sqlc ever regenerates with a different signature, this will
 // fail to build instead of silently breaking the sweep at runtime.
 var _ Querier = (*sqlc.Queries)(nil)
+
+// Defaults for SweepRunnerConfig.
+const (
+	DefaultSweepBatchSize  = 500
+	DefaultSweepEveryTicks = 600
+	DefaultSweepMaxBatches = 4
+)
+
+// SweepRunnerConfig tunes a SweepRunner. Zero values pick the defaults.
+type SweepRunnerConfig struct {
+	SweepConfig
+
+	// BatchSize caps the rows each delete transaction removes, and so
+	// the locks it holds. Defaults to DefaultSweepBatchSize.
+	BatchSize int32
+
+	// EveryTicks is how many game ticks pass between runs started by
+	// OnTick. Defaults to DefaultSweepEveryTicks.
+	EveryTicks int64
+
+	// MaxBatches stops a run after this many batches, leaving the rest
+	// for the next one, so a backlog after a wipe or purge doesn't stall
+	// the tick that OnTick runs on. Defaults to DefaultSweepMaxBatches;
+	// negative means run until nothing is eligible.
+	MaxBatches int
+
+	// DryRun counts eligible rows per entity type instead of deleting
+	// them.
+	DryRun bool
+
+	// OnReport receives every run's SweepReport, from the goroutine
+	// running it. Nil discards reports.
+	OnReport func(SweepReport)
+
+	// OnError receives errors from runs started by OnTick. Nil discards
+	// them. Run returns its error directly instead.
+	OnError func(error)
+}
+
+// SweepReport describes one sweep run. Deleted and Batches stay zero on
+// a dry run; Eligible is only filled by one.
+type SweepReport struct {
+	Tick     int64
+	Cutoff   int64
+	DryRun   bool
+	Deleted  int64
+	Batches  int
+	Eligible map[EntityType]int64
+	Duration time.Duration
+}
+
+// SweepRunner is the scheduled form of SweepDestroyedEntities, and
+// archives the same way. It deletes in batches of cfg.BatchSize, each
+// in its own transaction, so a season's worth of dead entities never
+// sits under one lock, and runs every cfg.EveryTicks game ticks when
+// the tick loop calls OnTick.
+//
+// It is not safe for concurrent use; the tick loop owns it.
+type SweepRunner struct {
+	tb      TxBeginner
+	cfg     SweepRunnerConfig
+	lastRun int64
+	ran     bool
+}
+
+// NewSweepRunner returns a SweepRunner over tb with cfg's defaults
+// filled.
+func NewSweepRunner(tb TxBeginner, cfg SweepRunnerConfig) *SweepRunner {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultSweepBatchSize
+	}
+	if cfg.EveryTicks <= 0 {
+		cfg.EveryTicks = DefaultSweepEveryTicks
+	}
+	if cfg.MaxBatches == 0 {
+		cfg.MaxBatches = DefaultSweepMaxBatches
+	}
+	return &SweepRunner{tb: tb, cfg: cfg}
+}
+
+// OnTick runs a sweep if cfg.EveryTicks have passed since the last one
+// (the first call always runs). Call it from the tick loop every tick;
+// it's a no-op while the sweep is disabled. Errors go to cfg.OnError and
+// the next run is attempted on schedule.
+func (r *SweepRunner) OnTick(ctx context.Context, tick int64) {
+	if !r.cfg.Enabled || (r.ran && tick-r.lastRun < r.cfg.EveryTicks) {
+		return
+	}
+	r.lastRun, r.ran = tick, true
+	if _, err := r.Run(ctx, tick); err != nil && r.cfg.OnError != nil {
+		r.cfg.OnError(err)
+	}
+}
+
+// Run sweeps now, as of tick, and reports the run to cfg.OnReport. A
+// disabled runner returns an empty report. If a batch fails, the report
+// covers the batches already committed.
+func (r *SweepRunner) Run(ctx context.Context, tick int64) (SweepReport, error) {
+	if !r.cfg.Enabled {
+		return SweepReport{}, nil
+	}
+	if r.cfg.RetentionTicks < 0 {
+		return SweepReport{}, fmt.Errorf("sweep: negative retention (%d)", r.cfg.RetentionTicks)
+	}
+	start := time.Now()
+	rep := SweepReport{Tick: tick, Cutoff: tick - r.cfg.RetentionTicks, DryRun: r.cfg.DryRun}
+	var err error
+	if r.cfg.DryRun {
+		err = r.count(ctx, &rep)
+	} else {
+		err = r.deleteBatches(ctx, &rep)
+	}
+	rep.Duration = time.Since(start)
+	if r.cfg.OnReport != nil {
+		r.cfg.OnReport(rep)
+	}
+	return rep, err
+}
+
+func (r *SweepRunner) count(ctx context.Context, rep *SweepReport) error {
+	tx, err := r.tb.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("sweep: begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := sqlc.New(tx).CountSweepableEntitiesByType(ctx, &rep.Cutoff)
+	if err != nil {
+		return fmt.Errorf("sweep: count eligible: %w", err)
+	}
+	rep.Eligible = make(map[EntityType]int64, len(rows))
+	for _, row := range rows {
+		rep.Eligible[EntityType(row.EntityType)] = row.Eligible
+	}
+	return nil
+}
+
+func (r *SweepRunner) deleteBatches(ctx context.Context, rep *SweepReport) error {
+	for r.cfg.MaxBatches < 0 || rep.Batches < r.cfg.MaxBatches {
+		n, err := r.deleteBatch(ctx, rep.Tick, rep.Cutoff)
+		if err != nil {
+			return fmt.Errorf("sweep: batch %d: %w", rep.Batches+1, err)
+		}
+		rep.Deleted += n
+		rep.Batches++
+		if n < int64(r.cfg.BatchSize) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *SweepRunner) deleteBatch(ctx context.Context, tick, cutoff int64) (int64, error) {
+	tx, err := r.tb.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	n, err := sqlc.New(tx).SweepDestroyedEntitiesBatch(ctx, sqlc.SweepDestroyedEntitiesBatchParams{
+		Cutoff:         &cutoff,
+		BatchSize:      r.cfg.BatchSize,
+		ArchivedAtTick: tick,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+	return n, nil
+}
@@ -2,8 +2,11 @@ package game_test
 
 import (
 	"context"
+	"maps"
+	"slices"
 	"testing"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 
 	"github.com/dukerupert/walking-drum/internal/db/sqlc"
@@ -157,3 +160,137 @@ func TestSweep_CascadesToPositionAndComponents(t *testing.T) {
 		t.Error("component row should be cascade-deleted with the entity")
 	}
 }
+
+// softDeleted creates n entities of type typ and soft-deletes them at
+// destroyedAt.
+func softDeleted(t *testing.T, tx pgx.Tx, n int, typ game.EntityType, destroyedAt int64) {
+	t.Helper()
+	ctx := context.Background()
+	ins := make([]game.CreateEntityInput, n)
+	for i := range ins {
+		ins[i] = game.CreateEntityInput{SeasonID: 1, Type: typ, Tick: 1}
+	}
+	ids, err := game.CreateEntities(ctx, tx, ins)
+	must(t, err)
+	for _, id := range ids {
+		_, err := sqlc.New(tx).SoftDeleteEntity(ctx, sqlc.SoftDeleteEntityParams{
+			ID:              pgtype.UUID{Bytes: id, Valid: true},
+			DestroyedAtTick: &destroyedAt,
+		})
+		must(t, err)
+	}
+}
+
+func TestSweepRunner_Batches(t *testing.T) {
+	_, tx := testdb.WithTx(t)
+	ctx := context.Background()
+	softDeleted(t, tx, 7, game.EntityWorldObject, 10)
+	softDeleted(t, tx, 2, game.EntityWorldObject, 95) // inside the window
+
+	var reports []game.SweepReport
+	r := game.NewSweepRunner(tx, game.SweepRunnerConfig{
+		SweepConfig: game.SweepConfig{Enabled: true, RetentionTicks: 20},
+		BatchSize:   3,
+		OnReport:    func(rep game.SweepReport) { reports = append(reports, rep) },
+	})
+	rep, err := r.Run(ctx, 100)
+	must(t, err)
+	// 3 + 3 + 1: the short batch ends the run.
+	if rep.Deleted != 7 || rep.Batches != 3 || rep.Cutoff != 80 {
+		t.Errorf("report: got %+v, want 7 deleted in 3 batches at cutoff 80", rep)
+	}
+	if len(reports) != 1 || reports[0].Deleted != rep.Deleted {
+		t.Errorf("OnReport: got %+v", reports)
+	}
+
+	rep, err = r.Run(ctx, 100)
+	must(t, err)
+	if rep.Deleted != 0 || rep.Batches != 1 {
+		t.Errorf("second run: got %+v, want nothing deleted", rep)
+	}
+}
+
+func TestSweepRunner_MaxBatches(t *testing.T) {
+	_, tx := testdb.WithTx(t)
+	softDeleted(t, tx, 5, game.EntityWorldObject, 10)
+
+	r := game.NewSweepRunner(tx, game.SweepRunnerConfig{
+		SweepConfig: game.SweepConfig{Enabled: true},
+		BatchSize:   2,
+		MaxBatches:  1,
+	})
+	rep, err := r.Run(context.Background(), 100)
+	must(t, err)
+	if rep.Deleted != 2 || rep.Batches != 1 {
+		t.Errorf("got %+v, want 2 deleted in 1 batch", rep)
+	}
+
+	// Unset, a run stops at DefaultSweepMaxBatches; negative drains.
+	softDeleted(t, tx, game.DefaultSweepMaxBatches+2, game.EntityWorldObject, 10)
+	r = game.NewSweepRunner(tx, game.SweepRunnerConfig{
+		SweepConfig: game.SweepConfig{Enabled: true},
+		BatchSize:   1,
+	})
+	rep, err = r.Run(context.Background(), 100)
+	must(t, err)
+	if rep.Batches != game.DefaultSweepMaxBatches {
+		t.Errorf("default cap: got %+v, want %d batches", rep, game.DefaultSweepMaxBatches)
+	}
+	r = game.NewSweepRunner(tx, game.SweepRunnerConfig{
+		SweepConfig: game.SweepConfig{Enabled: true},
+		BatchSize:   1,
+		MaxBatches:  -1,
+	})
+	rep, err = r.Run(context.Background(), 100)
+	must(t, err)
+	if rep.Deleted != 5 {
+		t.Errorf("unbounded: got %+v, want the remaining 5 deleted", rep)
+	}
+}
+
+func TestSweepRunner_DryRun(t *testing.T) {
+	_, tx := testdb.WithTx(t)
+	softDeleted(t, tx, 3, game.EntityWorldObject, 10)
+	softDeleted(t, tx, 2, game.EntityNPC, 10)
+	softDeleted(t, tx, 4, game.EntityNPC, 95)
+
+	r := game.NewSweepRunner(tx, game.SweepRunnerConfig{
+		SweepConfig: game.SweepConfig{Enabled: true, RetentionTicks: 20},
+		DryRun:      true,
+	})
+	rep, err := r.Run(context.Background(), 100)
+	must(t, err)
+	want := map[game.EntityType]int64{game.EntityWorldObject: 3, game.EntityNPC: 2}
+	if !rep.DryRun || rep.Deleted != 0 || !maps.Equal(rep.Eligible, want) {
+		t.Errorf("got %+v, want eligible %v and nothing deleted", rep, want)
+	}
+	again, err := r.Run(context.Background(), 100)
+	must(t, err)
+	if !maps.Equal(again.Eligible, want) {
+		t.Errorf("dry run deleted rows: eligible now %v", again.Eligible)
+	}
+}
+
+func TestSweepRunner_OnTickSchedule(t *testing.T) {
+	_, tx := testdb.WithTx(t)
+	ctx := context.Background()
+
+	var ran []int64
+	r := game.NewSweepRunner(tx, game.SweepRunnerConfig{
+		SweepConfig: game.SweepConfig{Enabled: true},
+		EveryTicks:  10,
+		OnReport:    func(rep game.SweepReport) { ran = append(ran, rep.Tick) },
+		OnError:     func(err error) { t.Errorf("OnError: %v", err) },
+	})
+	for tick := int64(5); tick <= 30; tick++ {
+		r.OnTick(ctx, tick)
+	}
+	if want := []int64{5, 15, 25}; !slices.Equal(ran, want) {
+		t.Errorf("ran at ticks %v, want %v", ran, want)
+	}
+
+	disabled := game.NewSweepRunner(tx, game.SweepRunnerConfig{
+		OnReport: func(game.SweepReport) { t.Error("disabled runner reported") },
+	})
+	disabled.OnTick(ctx, 1)
+}
@@ -0,0 +1,103 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// versionKey is the field JSON-encoded state carries its schema version
+// in. It is omitted at version 1, so every payload written before
+// versioning existed — and every v1 component today, Hidden's "{}"
+// included — reads as version 1 without a rewrite. Other codecs keep
+// the version in their own header.
+const versionKey = "_v"
+
+// ErrComponentTooNew is returned when stored state has a higher version
+// than this binary's registration knows: the row was written by a newer
+// build, and decoding it with an older struct would drop fields.
+var ErrComponentTooNew = errors.New("game: component state is newer than this build")
+
+// Upgrader turns state at one version into state at the next. It sees
+// and returns the payload as a JSON object without the version field,
+// whichever codec stored it. Upgraders work on JSON rather than Go
+// structs because the struct for the old version no longer exists.
+type Upgrader func(state []byte) ([]byte, error)
+
+// WithVersion declares that the type's current schema is version, and
+// supplies the upgraders that get there: upgraders[i] takes version i+1
+// to i+2, so a type at version 3 passes two. RegisterComponent panics
+// if the count doesn't match. Never edit a shipped upgrader; add a new
+// version instead.
+func WithVersion(version int, upgraders ...Upgrader) ComponentOption {
+	return func(info *ComponentInfo) {
+		if version < 1 || len(upgraders) != version-1 {
+			panic(fmt.Sprintf("game: component %q: version %d needs %d upgraders, got %d",
+				info.Type, version, max(version-1, 0), len(upgraders)))
+		}
+		info.Version = version
+		info.upgraders = upgraders
+	}
+}
+
+// decodeState decodes stored state for typ into into, detecting the
+// codec that wrote it and running the type's upgraders if it is older
+// than the registered version. Unregistered types decode as version 1.
+func decodeState(typ string, raw []byte, into any) error {
+	info, ok := LookupComponent(typ)
+	if !ok {
+		info = ComponentInfo{Type: typ, Version: 1}
+	}
+	codec := detectCodec(raw)
+	v, err := codec.Version(raw)
+	if err != nil {
+		return fmt.Errorf("decode component %s: %s: %w", typ, codec.Name(), err)
+	}
+	switch {
+	case v == info.Version:
+		if err := codec.Decode(raw, into); err != nil {
+			return fmt.Errorf("decode component %s: %w", typ, err)
+		}
+		return nil
+	case v > info.Version:
+		return fmt.Errorf("decode component %s: %w: stored v%d, registered v%d", typ, ErrComponentTooNew, v, info.Version)
+	}
+
+	state, err := codec.ToJSON(raw, info.fieldHistory[v])
+	if err != nil {
+		return fmt.Errorf("decode component %s: v%d %s state: %w", typ, v, codec.Name(), err)
+	}
+	for ; v < info.Version; v++ {
+		if state, err = info.upgraders[v-1](state); err != nil {
+			return fmt.Errorf("decode component %s: upgrade v%d→v%d: %w", typ, v, v+1, err)
+		}
+	}
+	if err := json.Unmarshal(state, into); err != nil {
+		return fmt.Errorf("decode component %s: %w", typ, err)
+	}
+	return nil
+}
+
+// NeedsUpgrade reports whether state stored for typ is older than typ's
+// registered version.
+func NeedsUpgrade(typ string, raw []byte) (bool, error) {
+	codec := detectCodec(raw)
+	v, err := codec.Version(raw)
+	if err != nil {
+		return false, fmt.Errorf("component %s: %w", typ, err)
+	}
+	cur := 1
+	if info, ok := LookupComponent(typ); ok {
+		cur = info.Version
+	}
+	return v < cur, nil
+}
+
+// needsRewrite reports whether MigrateComponents should rewrite raw:
+// it is older than the registered version or in a different codec.
+func needsRewrite(info ComponentInfo, raw []byte) (bool, error) {
+	if detectCodec(raw) != info.Codec {
+		return true, nil
+	}
+	return NeedsUpgrade(info.Type, raw)
+}
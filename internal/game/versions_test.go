@@ -0,0 +1,116 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// versionProbe is at v3. v1 stored {"hp": n}; v2 renamed hp to health;
+// v3 added max_health, defaulting to health.
+type versionProbe struct {
+	Health    int `json:"health"`
+	MaxHealth int `json:"max_health"`
+}
+
+func (versionProbe) ComponentType() string { return "test_version_probe" }
+
+func init() {
+	RegisterComponent[versionProbe](WithVersion(3,
+		func(state []byte) ([]byte, error) {
+			var v1 struct {
+				HP int `json:"hp"`
+			}
+			if err := json.Unmarshal(state, &v1); err != nil {
+				return nil, err
+			}
+			return json.Marshal(map[string]int{"health": v1.HP})
+		},
+		func(state []byte) ([]byte, error) {
+			var v2 struct {
+				Health int `json:"health"`
+			}
+			if err := json.Unmarshal(state, &v2); err != nil {
+				return nil, err
+			}
+			return json.Marshal(map[string]int{"health": v2.Health, "max_health": v2.Health})
+		},
+	))
+}
+
+func TestEncodeStampsVersion(t *testing.T) {
+	raw, err := EncodeComponent(versionProbe{Health: 5, MaxHealth: 9})
+	if err != nil {
+		t.Fatalf("EncodeComponent: %v", err)
+	}
+	if want := `{"_v":3,"health":5,"max_health":9}`; string(raw) != want {
+		t.Errorf("encoded: got %s, want %s", raw, want)
+	}
+
+	stamped, err := stampVersion([]byte("{}"), 2)
+	if err != nil || string(stamped) != `{"_v":2}` {
+		t.Errorf(`stampVersion("{}", 2): got %s, %v`, stamped, err)
+	}
+	if _, err := stampVersion([]byte("[]"), 2); err == nil {
+		t.Error("stampVersion(array): got nil error")
+	}
+}
+
+func TestDecodeUpgradesOldState(t *testing.T) {
+	for name, raw := range map[string]string{
+		"v1, no version key": `{"hp":7}`,
+		"v2":                 `{"_v":2,"health":7}`,
+		"v3":                 `{"_v":3,"health":7,"max_health":7}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			var got versionProbe
+			if err := DecodeComponent([]byte(raw), &got); err != nil {
+				t.Fatalf("DecodeComponent: %v", err)
+			}
+			if got != (versionProbe{Health: 7, MaxHealth: 7}) {
+				t.Errorf("got %+v, want health 7 / max 7", got)
+			}
+		})
+	}
+
+	var got versionProbe
+	if err := DecodeComponent([]byte(`{"_v":4,"health":1}`), &got); !errors.Is(err, ErrComponentTooNew) {
+		t.Errorf("decode v4: got %v, want ErrComponentTooNew", err)
+	}
+}
+
+func TestNeedsUpgrade(t *testing.T) {
+	for raw, want := range map[string]bool{
+		`{"hp":1}`: true,
+		`{"_v":2}`: true,
+		`{"_v":3}`: false,
+	} {
+		got, err := NeedsUpgrade("test_version_probe", []byte(raw))
+		if err != nil || got != want {
+			t.Errorf("NeedsUpgrade(%s): got %v, %v; want %v", raw, got, err, want)
+		}
+	}
+	if got, _ := NeedsUpgrade(ComponentHidden, []byte("{}")); got {
+		t.Error("NeedsUpgrade(hidden, {}): got true")
+	}
+}
+
+func TestHiddenStaysUnversioned(t *testing.T) {
+	raw, err := EncodeComponent(Hidden{})
+	if err != nil {
+		t.Fatalf("EncodeComponent: %v", err)
+	}
+	if !bytes.Equal(raw, []byte("{}")) {
+		t.Errorf("v1 components must not carry a version key: got %s", raw)
+	}
+}
+
+func TestWithVersionPanicsOnMissingUpgraders(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+	WithVersion(3, nil)(&ComponentInfo{Type: "x"})
+}
@@ -0,0 +1,116 @@
+package game
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+)
+
+// FlagStaff is the account_flags type (DESIGN.md §5.2) that marks staff
+// and GMs, who see hidden entities.
+const FlagStaff = "staff"
+
+// Viewer is whoever a client-facing query is answering for. Anything
+// that sends entity data to a client goes through a Viewer, so Hidden
+// is enforced in one place.
+type Viewer struct {
+	// EntityID is the viewer's own entity, which it always sees, hidden
+	// or not. uuid.Nil for a viewer without one (a spectator).
+	EntityID uuid.UUID
+
+	// SeesHidden lifts the Hidden filter entirely: staff, or an entity
+	// with DetectsHidden.
+	SeesHidden bool
+}
+
+// ViewerQuerier is the subset of *sqlc.Queries ResolveViewer needs.
+type ViewerQuerier interface {
+	HasAccountFlag(ctx context.Context, arg sqlc.HasAccountFlagParams) (bool, error)
+	HasComponent(ctx context.Context, arg sqlc.HasComponentParams) (bool, error)
+}
+
+var _ ViewerQuerier = (*sqlc.Queries)(nil)
+
+// ResolveViewer builds the Viewer for accountID playing entityID
+// (uuid.Nil if none): it sees hidden entities if the account is staff
+// or the entity carries DetectsHidden.
+func ResolveViewer(ctx context.Context, q ViewerQuerier, accountID, entityID uuid.UUID) (Viewer, error) {
+	v := Viewer{EntityID: entityID}
+	staff, err := q.HasAccountFlag(ctx, sqlc.HasAccountFlagParams{
+		AccountID: pgtype.UUID{Bytes: accountID, Valid: true},
+		FlagType:  FlagStaff,
+	})
+	if err != nil {
+		return Viewer{}, fmt.Errorf("check staff flag: %w", err)
+	}
+	if staff {
+		v.SeesHidden = true
+		return v, nil
+	}
+	if entityID != uuid.Nil {
+		detects, err := q.HasComponent(ctx, sqlc.HasComponentParams{
+			EntityID:      pgtype.UUID{Bytes: entityID, Valid: true},
+			ComponentType: ComponentDetectsHidden,
+		})
+		if err != nil {
+			return Viewer{}, fmt.Errorf("check %s: %w", ComponentDetectsHidden, err)
+		}
+		v.SeesHidden = detects
+	}
+	return v, nil
+}
+
+// CanSee reports whether v may be shown entity id, using w's components.
+// It is the in-memory twin of the SQL filter, for broadcasting from a
+// World.
+func (v Viewer) CanSee(w *World, id uuid.UUID) bool {
+	return v.SeesHidden || id == v.EntityID || !Has[Hidden](w, id)
+}
+
+// VisibilityQuerier is the subset of *sqlc.Queries the visible-entity
+// queries need.
+type VisibilityQuerier interface {
+	ListVisibleEntitiesAtPosition(ctx context.Context, arg sqlc.ListVisibleEntitiesAtPositionParams) ([]sqlc.EntityPosition, error)
+	ListVisibleEntitiesInRegion(ctx context.Context, arg sqlc.ListVisibleEntitiesInRegionParams) ([]sqlc.EntityPosition, error)
+}
+
+var _ VisibilityQuerier = (*sqlc.Queries)(nil)
+
+// VisibleAtPosition is GetEntitiesAtPosition as v may see it. Use it,
+// not the raw query, for anything bound for a client.
+func VisibleAtPosition(ctx context.Context, q VisibilityQuerier, v Viewer, regionID, x, y int32) ([]sqlc.EntityPosition, error) {
+	rows, err := q.ListVisibleEntitiesAtPosition(ctx, sqlc.ListVisibleEntitiesAtPositionParams{
+		RegionID:   regionID,
+		X:          x,
+		Y:          y,
+		SeesHidden: v.SeesHidden,
+		ViewerID:   v.pgID(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("visible at (%d, %d, %d): %w", regionID, x, y, err)
+	}
+	return rows, nil
+}
+
+// VisibleInRegion is GetEntitiesInRegion as v may see it.
+func VisibleInRegion(ctx context.Context, q VisibilityQuerier, v Viewer, regionID int32) ([]sqlc.EntityPosition, error) {
+	rows, err := q.ListVisibleEntitiesInRegion(ctx, sqlc.ListVisibleEntitiesInRegionParams{
+		RegionID:   regionID,
+		SeesHidden: v.SeesHidden,
+		ViewerID:   v.pgID(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("visible in region %d: %w", regionID, err)
+	}
+	return rows, nil
+}
+
+// pgID is the viewer's entity as a query parameter; NULL for none, which
+// matches no entity.
+func (v Viewer) pgID() pgtype.UUID {
+	return pgtype.UUID{Bytes: v.EntityID, Valid: v.EntityID != uuid.Nil}
+}
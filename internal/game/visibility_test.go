@@ -0,0 +1,109 @@
+package game_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+	"github.com/dukerupert/walking-drum/internal/game"
+	"github.com/dukerupert/walking-drum/internal/testdb"
+)
+
+func TestViewerCanSee(t *testing.T) {
+	w, ids := newTestWorld(t, 3)
+	visible, hidden, self := ids[0], ids[1], ids[2]
+	must(t, w.SetComponent(hidden, game.Hidden{}))
+	must(t, w.SetComponent(self, game.Hidden{}))
+
+	player := game.Viewer{EntityID: self}
+	if !player.CanSee(w, visible) || player.CanSee(w, hidden) {
+		t.Error("player: want visible shown, hidden filtered")
+	}
+	if !player.CanSee(w, self) {
+		t.Error("player can't see its own hidden entity")
+	}
+	gm := game.Viewer{SeesHidden: true}
+	if !gm.CanSee(w, hidden) {
+		t.Error("SeesHidden viewer filtered a hidden entity")
+	}
+}
+
+func TestVisibilityQueries(t *testing.T) {
+	q, tx := testdb.WithTx(t)
+	ctx := context.Background()
+
+	spawn := func(comps ...game.Component) uuid.UUID {
+		t.Helper()
+		id, err := game.CreateEntity(ctx, tx, game.CreateEntityInput{
+			SeasonID: 1, Type: game.EntityCharacter, Tick: 1,
+			Position:          &game.PositionSpec{RegionID: 42, X: 1, Y: 1},
+			InitialComponents: comps,
+		})
+		if err != nil {
+			t.Fatalf("CreateEntity: %v", err)
+		}
+		return id
+	}
+	plain := spawn()
+	sneak := spawn(game.Hidden{})
+	watcher := spawn(game.DetectsHidden{})
+
+	account := func(email string) uuid.UUID {
+		t.Helper()
+		id, _ := uuid.NewV7()
+		if _, err := q.CreateAccount(ctx, sqlc.CreateAccountParams{
+			ID: pgtype.UUID{Bytes: id, Valid: true}, Email: email, DisplayName: email, PasswordHash: "x",
+		}); err != nil {
+			t.Fatalf("CreateAccount: %v", err)
+		}
+		return id
+	}
+	player, staff := account("player@example.com"), account("gm@example.com")
+	if _, err := tx.Exec(ctx, `INSERT INTO account_flags (account_id, flag_type) VALUES ($1, 'staff')`, staff); err != nil {
+		t.Fatalf("insert staff flag: %v", err)
+	}
+
+	ids := func(rows []sqlc.EntityPosition) []uuid.UUID {
+		var out []uuid.UUID
+		for _, r := range rows {
+			out = append(out, r.EntityID.Bytes)
+		}
+		return out
+	}
+	for name, tc := range map[string]struct {
+		account, entity uuid.UUID
+		seesSneak       bool
+	}{
+		"player":         {player, plain, false},
+		"hidden self":    {player, sneak, true},
+		"detects hidden": {player, watcher, true},
+		"staff":          {staff, uuid.Nil, true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			v, err := game.ResolveViewer(ctx, q, tc.account, tc.entity)
+			if err != nil {
+				t.Fatalf("ResolveViewer: %v", err)
+			}
+			atPos, err := game.VisibleAtPosition(ctx, q, v, 42, 1, 1)
+			if err != nil {
+				t.Fatalf("VisibleAtPosition: %v", err)
+			}
+			inRegion, err := game.VisibleInRegion(ctx, q, v, 42)
+			if err != nil {
+				t.Fatalf("VisibleInRegion: %v", err)
+			}
+			for _, got := range [][]uuid.UUID{ids(atPos), ids(inRegion)} {
+				if !slices.Contains(got, plain) || !slices.Contains(got, watcher) {
+					t.Errorf("unhidden entities missing: %v", got)
+				}
+				if slices.Contains(got, sneak) != tc.seesSneak {
+					t.Errorf("sees hidden entity: got %v, want %v", !tc.seesSneak, tc.seesSneak)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,325 @@
+package game
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+)
+
+// ErrEntityNotFound is returned when an operation names an entity the
+// world (or the database) doesn't have.
+var ErrEntityNotFound = errors.New("game: entity not found")
+
+// World is the in-memory model from DESIGN.md §2.4: live entities, their
+// positions, and one sparse map[entity]Component per component type.
+// Systems read and write it directly; it knows nothing about Postgres
+// beyond LoadWorld, so a test can build one by hand and run a system
+// against it.
+//
+// A World is owned by one goroutine (the region loop) and is not safe
+// for concurrent use. Components are stored by value: changing one means
+// calling SetComponent again, never mutating through a pointer.
+//
+// Every write is also recorded as a pending change for write-behind
+// persistence; see TakeChanges and Flusher.
+type World struct {
+	seasonID   int32
+	tick       int64
+	entities   map[uuid.UUID]Entity
+	positions  map[uuid.UUID]Position
+	components map[string]map[uuid.UUID]Component
+	dirty      dirtySet
+}
+
+// NewWorld returns an empty world for seasonID.
+func NewWorld(seasonID int32) *World {
+	return &World{
+		seasonID:   seasonID,
+		entities:   map[uuid.UUID]Entity{},
+		positions:  map[uuid.UUID]Position{},
+		components: map[string]map[uuid.UUID]Component{},
+		dirty:      newDirtySet(),
+	}
+}
+
+// SeasonID is the season this world holds.
+func (w *World) SeasonID() int32 { return w.seasonID }
+
+// Tick is the world's current tick, as last set by SetTick.
+func (w *World) Tick() int64 { return w.tick }
+
+// SetTick advances the world's clock. Component changes are stamped with
+// it when persisted, as updated_at_tick.
+func (w *World) SetTick(tick int64) { w.tick = tick }
+
+// Len is the number of entities in the world.
+func (w *World) Len() int { return len(w.entities) }
+
+// AddEntity puts e in the world as a new entity, to be inserted on the
+// next flush. A zero SeasonID is filled in with the world's; any other
+// mismatch, a destroyed entity, or an ID already present is an error.
+func (w *World) AddEntity(e Entity) error {
+	if !e.Type.Valid() {
+		return fmt.Errorf("add entity %s: invalid type %q", e.ID, e.Type)
+	}
+	if e.SeasonID == 0 {
+		e.SeasonID = w.seasonID
+	}
+	if e.SeasonID != w.seasonID {
+		return fmt.Errorf("add entity %s: season %d in a season %d world", e.ID, e.SeasonID, w.seasonID)
+	}
+	if e.IsDestroyed {
+		return fmt.Errorf("add entity %s: entity is destroyed", e.ID)
+	}
+	if _, ok := w.entities[e.ID]; ok {
+		return fmt.Errorf("add entity %s: already in world", e.ID)
+	}
+	w.entities[e.ID] = e
+	w.dirty.entities[e.ID] = struct{}{}
+	return nil
+}
+
+// Entity returns entity id, if the world has it.
+func (w *World) Entity(id uuid.UUID) (Entity, bool) {
+	e, ok := w.entities[id]
+	return e, ok
+}
+
+// RemoveEntity drops id along with its position and components, and
+// reports whether it was present. It is not a destroy: it writes nothing,
+// and discards id's unflushed changes. Destruction is write-through and
+// happens in the database first; this just evicts the entity afterwards.
+func (w *World) RemoveEntity(id uuid.UUID) bool {
+	if _, ok := w.entities[id]; !ok {
+		return false
+	}
+	delete(w.entities, id)
+	delete(w.positions, id)
+	for _, byEntity := range w.components {
+		delete(byEntity, id)
+	}
+	w.dirty.forget(id)
+	return true
+}
+
+// SetPosition places or moves p.EntityID.
+func (w *World) SetPosition(p Position) error {
+	if _, ok := w.entities[p.EntityID]; !ok {
+		return fmt.Errorf("set position: %s: %w", p.EntityID, ErrEntityNotFound)
+	}
+	w.positions[p.EntityID] = p
+	w.dirty.positions[p.EntityID] = struct{}{}
+	return nil
+}
+
+// Position returns id's position; false means the entity isn't in the
+// world or has no position (it's held in a container, say).
+func (w *World) Position(id uuid.UUID) (Position, bool) {
+	p, ok := w.positions[id]
+	return p, ok
+}
+
+// RemovePosition takes id out of the spatial world without destroying it.
+func (w *World) RemovePosition(id uuid.UUID) {
+	if _, ok := w.positions[id]; !ok {
+		return
+	}
+	delete(w.positions, id)
+	w.dirty.positions[id] = struct{}{}
+}
+
+// SetComponent attaches c to id, replacing any component of the same
+// type. As with the DB accessor, c's type must be registered. c must be
+// a value, not a pointer, or later writes through it would bypass the
+// world.
+func (w *World) SetComponent(id uuid.UUID, c Component) error {
+	if c == nil {
+		return errors.New("set component: nil component")
+	}
+	if reflect.TypeOf(c).Kind() == reflect.Pointer {
+		return fmt.Errorf("set component %s: got %T; store components by value", c.ComponentType(), c)
+	}
+	if err := checkRegistered(c); err != nil {
+		return fmt.Errorf("set component: %w", err)
+	}
+	if _, ok := w.entities[id]; !ok {
+		return fmt.Errorf("set component %s: %s: %w", c.ComponentType(), id, ErrEntityNotFound)
+	}
+	typ := c.ComponentType()
+	byEntity := w.components[typ]
+	if byEntity == nil {
+		byEntity = map[uuid.UUID]Component{}
+		w.components[typ] = byEntity
+	}
+	byEntity[id] = c
+	w.dirty.components[componentKey{id, typ}] = w.tick
+	return nil
+}
+
+// Component returns id's component of type typ. Gameplay code should
+// prefer Get[T].
+func (w *World) Component(id uuid.UUID, typ string) (Component, bool) {
+	c, ok := w.components[typ][id]
+	return c, ok
+}
+
+// RemoveComponent detaches typ from id and reports whether it was there.
+func (w *World) RemoveComponent(id uuid.UUID, typ string) bool {
+	if _, ok := w.components[typ][id]; !ok {
+		return false
+	}
+	delete(w.components[typ], id)
+	w.dirty.components[componentKey{id, typ}] = w.tick
+	return true
+}
+
+// ComponentTypes lists the component types id carries, sorted.
+func (w *World) ComponentTypes(id uuid.UUID) []string {
+	var types []string
+	for typ, byEntity := range w.components {
+		if _, ok := byEntity[id]; ok {
+			types = append(types, typ)
+		}
+	}
+	slices.Sort(types)
+	return types
+}
+
+// Query returns the entities carrying every one of types, in ID order —
+// UUIDv7, so roughly creation order — so a system iterating the result
+// behaves the same on every run. No types means every entity.
+func (w *World) Query(types ...string) []uuid.UUID {
+	var ids []uuid.UUID
+	if len(types) == 0 {
+		ids = make([]uuid.UUID, 0, len(w.entities))
+		for id := range w.entities {
+			ids = append(ids, id)
+		}
+		sortIDs(ids)
+		return ids
+	}
+
+	// Walk the smallest set and probe the rest.
+	smallest := types[0]
+	for _, typ := range types[1:] {
+		if len(w.components[typ]) < len(w.components[smallest]) {
+			smallest = typ
+		}
+	}
+next:
+	for id := range w.components[smallest] {
+		for _, typ := range types {
+			if _, ok := w.components[typ][id]; !ok {
+				continue next
+			}
+		}
+		ids = append(ids, id)
+	}
+	sortIDs(ids)
+	return ids
+}
+
+func sortIDs(ids []uuid.UUID) {
+	slices.SortFunc(ids, func(a, b uuid.UUID) int { return bytes.Compare(a[:], b[:]) })
+}
+
+// Get returns id's component of type T: Get[Hidden](w, id).
+func Get[T Component](w *World, id uuid.UUID) (T, bool) {
+	c, ok := w.components[componentType[T]()][id].(T)
+	return c, ok
+}
+
+// Has reports whether id carries a component of type T.
+func Has[T Component](w *World, id uuid.UUID) bool {
+	_, ok := w.components[componentType[T]()][id]
+	return ok
+}
+
+// All iterates every entity carrying a T, in ID order.
+func All[T Component](w *World) iter.Seq2[uuid.UUID, T] {
+	return func(yield func(uuid.UUID, T) bool) {
+		for _, id := range w.Query(componentType[T]()) {
+			c, ok := Get[T](w, id)
+			if !ok {
+				continue // removed by the loop body
+			}
+			if !yield(id, c) {
+				return
+			}
+		}
+	}
+}
+
+// WorldLoader is the subset of *sqlc.Queries LoadWorld needs.
+type WorldLoader interface {
+	ListLiveEntitiesInSeason(ctx context.Context, seasonID int32) ([]sqlc.Entity, error)
+	ListPositionsInSeason(ctx context.Context, seasonID int32) ([]sqlc.EntityPosition, error)
+	ListComponentsInSeason(ctx context.Context, seasonID int32) ([]sqlc.Component, error)
+}
+
+var _ WorldLoader = (*sqlc.Queries)(nil)
+
+// LoadWorld reads every live entity in seasonID, with positions and
+// components, into a fresh World. Pass a transaction's queries for a
+// consistent snapshot. Component state is decoded through the registry,
+// so an unregistered type fails the load.
+func LoadWorld(ctx context.Context, q WorldLoader, seasonID int32) (*World, error) {
+	w := NewWorld(seasonID)
+
+	entities, err := q.ListLiveEntitiesInSeason(ctx, seasonID)
+	if err != nil {
+		return nil, fmt.Errorf("load entities: %w", err)
+	}
+	for _, row := range entities {
+		if err := w.AddEntity(Entity{
+			ID:            row.ID.Bytes,
+			SeasonID:      row.SeasonID,
+			Type:          EntityType(row.EntityType),
+			CreatedAtTick: row.CreatedAtTick,
+		}); err != nil {
+			return nil, fmt.Errorf("load world: %w", err)
+		}
+	}
+
+	positions, err := q.ListPositionsInSeason(ctx, seasonID)
+	if err != nil {
+		return nil, fmt.Errorf("load positions: %w", err)
+	}
+	for _, row := range positions {
+		if err := w.SetPosition(Position{
+			EntityID:      row.EntityID.Bytes,
+			RegionID:      row.RegionID,
+			X:             row.X,
+			Y:             row.Y,
+			UpdatedAtTick: row.UpdatedAtTick,
+		}); err != nil {
+			return nil, fmt.Errorf("load world: %w", err)
+		}
+	}
+
+	components, err := q.ListComponentsInSeason(ctx, seasonID)
+	if err != nil {
+		return nil, fmt.Errorf("load components: %w", err)
+	}
+	for _, row := range components {
+		id := uuid.UUID(row.EntityID.Bytes)
+		c, err := DecodeAny(row.ComponentType, row.State)
+		if err != nil {
+			return nil, fmt.Errorf("load world: entity %s: %w", id, err)
+		}
+		if err := w.SetComponent(id, c); err != nil {
+			return nil, fmt.Errorf("load world: %w", err)
+		}
+	}
+	// Everything just came from the database; nothing is pending.
+	w.dirty = newDirtySet()
+	return w, nil
+}
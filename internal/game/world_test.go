@@ -0,0 +1,204 @@
+package game_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+	"github.com/dukerupert/walking-drum/internal/game"
+	"github.com/dukerupert/walking-drum/internal/testdb"
+)
+
+type worldBurning struct {
+	Intensity int `json:"intensity"`
+}
+
+func (worldBurning) ComponentType() string { return "test_world_burning" }
+
+func init() {
+	game.RegisterComponent[worldBurning]()
+}
+
+// newTestWorld builds a world with n NPCs whose IDs sort in creation
+// order, so tests can assert on Query's ordering.
+func newTestWorld(t *testing.T, n int) (*game.World, []uuid.UUID) {
+	t.Helper()
+	w := game.NewWorld(1)
+	ids := make([]uuid.UUID, n)
+	for i := range ids {
+		ids[i] = uuid.UUID{15: byte(i + 1)}
+		if err := w.AddEntity(game.Entity{ID: ids[i], Type: game.EntityNPC}); err != nil {
+			t.Fatalf("AddEntity: %v", err)
+		}
+	}
+	return w, ids
+}
+
+func TestWorldComponents(t *testing.T) {
+	w, ids := newTestWorld(t, 2)
+	a := ids[0]
+
+	if err := w.SetComponent(a, worldBurning{Intensity: 3}); err != nil {
+		t.Fatalf("SetComponent: %v", err)
+	}
+	if got, ok := game.Get[worldBurning](w, a); !ok || got.Intensity != 3 {
+		t.Errorf("Get: got %+v, %v", got, ok)
+	}
+	if game.Has[worldBurning](w, ids[1]) {
+		t.Error("Has on an entity without the component: got true")
+	}
+	if err := w.SetComponent(a, worldBurning{Intensity: 5}); err != nil {
+		t.Fatalf("SetComponent replace: %v", err)
+	}
+	if got, _ := game.Get[worldBurning](w, a); got.Intensity != 5 {
+		t.Errorf("after replace: got %d, want 5", got.Intensity)
+	}
+	if got := w.ComponentTypes(a); !slices.Equal(got, []string{"test_world_burning"}) {
+		t.Errorf("ComponentTypes: got %v", got)
+	}
+
+	if !w.RemoveComponent(a, "test_world_burning") || w.RemoveComponent(a, "test_world_burning") {
+		t.Error("RemoveComponent: want true then false")
+	}
+	if game.Has[worldBurning](w, a) {
+		t.Error("Has after remove: got true")
+	}
+}
+
+func TestWorldRejectsBadWrites(t *testing.T) {
+	w, ids := newTestWorld(t, 1)
+	stranger := uuid.UUID{15: 0xff}
+
+	if err := w.SetComponent(stranger, game.Hidden{}); !errors.Is(err, game.ErrEntityNotFound) {
+		t.Errorf("SetComponent on unknown entity: got %v, want ErrEntityNotFound", err)
+	}
+	if err := w.SetPosition(game.Position{EntityID: stranger}); !errors.Is(err, game.ErrEntityNotFound) {
+		t.Errorf("SetPosition on unknown entity: got %v, want ErrEntityNotFound", err)
+	}
+	if err := w.SetComponent(ids[0], unregisteredComponent{}); !errors.Is(err, game.ErrUnknownComponent) {
+		t.Errorf("SetComponent unregistered: got %v, want ErrUnknownComponent", err)
+	}
+	if err := w.AddEntity(game.Entity{ID: ids[0], Type: game.EntityNPC}); err == nil {
+		t.Error("AddEntity duplicate: got nil error")
+	}
+	if err := w.AddEntity(game.Entity{ID: stranger, SeasonID: 2, Type: game.EntityNPC}); err == nil {
+		t.Error("AddEntity from another season: got nil error")
+	}
+}
+
+func TestWorldQuery(t *testing.T) {
+	w, ids := newTestWorld(t, 5)
+	// Insert out of order; Query must still come back sorted.
+	for _, i := range []int{4, 1, 3} {
+		if err := w.SetComponent(ids[i], worldBurning{Intensity: i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, i := range []int{3, 0, 1} {
+		if err := w.SetComponent(ids[i], game.Hidden{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := w.Query("test_world_burning"); !slices.Equal(got, []uuid.UUID{ids[1], ids[3], ids[4]}) {
+		t.Errorf("Query(burning): got %v", got)
+	}
+	if got := w.Query("test_world_burning", game.ComponentHidden); !slices.Equal(got, []uuid.UUID{ids[1], ids[3]}) {
+		t.Errorf("Query(burning, hidden): got %v", got)
+	}
+	if got := w.Query("test_nothing_has_this"); len(got) != 0 {
+		t.Errorf("Query(absent type): got %v", got)
+	}
+	if got := w.Query(); !slices.Equal(got, ids) {
+		t.Errorf("Query(): got %v, want every entity", got)
+	}
+
+	var seen []int
+	for _, c := range game.All[worldBurning](w) {
+		seen = append(seen, c.Intensity)
+	}
+	if !slices.Equal(seen, []int{1, 3, 4}) {
+		t.Errorf("All: got %v", seen)
+	}
+}
+
+func TestWorldRemoveEntity(t *testing.T) {
+	w, ids := newTestWorld(t, 2)
+	a := ids[0]
+	if err := w.SetPosition(game.Position{EntityID: a, RegionID: 1, X: 2, Y: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.SetComponent(a, game.Hidden{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !w.RemoveEntity(a) {
+		t.Fatal("RemoveEntity: got false")
+	}
+	if _, ok := w.Position(a); ok {
+		t.Error("position survived RemoveEntity")
+	}
+	if got := w.Query(game.ComponentHidden); len(got) != 0 {
+		t.Errorf("components survived RemoveEntity: %v", got)
+	}
+	if w.Len() != 1 || w.RemoveEntity(a) {
+		t.Errorf("Len after remove: got %d, want 1", w.Len())
+	}
+}
+
+func TestLoadWorld(t *testing.T) {
+	_, tx := testdb.WithTx(t)
+	ctx := context.Background()
+	q := sqlc.New(tx)
+
+	placed, err := game.CreateEntity(ctx, tx, game.CreateEntityInput{
+		SeasonID:          1,
+		Type:              game.EntityNPC,
+		Tick:              3,
+		Position:          &game.PositionSpec{RegionID: 7, X: 1, Y: 2},
+		InitialComponents: []game.Component{worldBurning{Intensity: 2}, game.Hidden{}},
+	})
+	if err != nil {
+		t.Fatalf("CreateEntity: %v", err)
+	}
+	held, err := game.CreateEntity(ctx, tx, game.CreateEntityInput{SeasonID: 1, Type: game.EntityItem, Tick: 3})
+	if err != nil {
+		t.Fatalf("CreateEntity: %v", err)
+	}
+	dead, err := game.CreateEntity(ctx, tx, game.CreateEntityInput{SeasonID: 1, Type: game.EntityNPC, Tick: 3})
+	if err != nil {
+		t.Fatalf("CreateEntity: %v", err)
+	}
+	destroyedAt := int64(4)
+	if _, err := q.SoftDeleteEntity(ctx, sqlc.SoftDeleteEntityParams{
+		ID:              pgtype.UUID{Bytes: dead, Valid: true},
+		DestroyedAtTick: &destroyedAt,
+	}); err != nil {
+		t.Fatalf("SoftDeleteEntity: %v", err)
+	}
+
+	w, err := game.LoadWorld(ctx, q, 1)
+	if err != nil {
+		t.Fatalf("LoadWorld: %v", err)
+	}
+	if _, ok := w.Entity(dead); ok {
+		t.Error("destroyed entity was loaded")
+	}
+	if _, ok := w.Position(held); ok {
+		t.Error("unpositioned entity got a position")
+	}
+	if p, ok := w.Position(placed); !ok || p.RegionID != 7 || p.X != 1 || p.Y != 2 {
+		t.Errorf("position: got %+v, %v", p, ok)
+	}
+	if got, ok := game.Get[worldBurning](w, placed); !ok || got.Intensity != 2 {
+		t.Errorf("component: got %+v, %v", got, ok)
+	}
+	if !slices.Contains(w.Query(game.ComponentHidden), placed) {
+		t.Error("Query(hidden) missing the loaded entity")
+	}
+}
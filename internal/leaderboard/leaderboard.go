@@ -0,0 +1,249 @@
+// Package leaderboard ranks accounts within a season and keeps those
+// rankings after the season is wiped (DESIGN.md §3.5). Scores come from
+// season_participation, which game code keeps current as things happen;
+// while a season is being played its boards are ranked on read, and
+// when it ends they are frozen into leaderboard_snapshots, which the
+// wipe leaves alone. The hall of fame is the top of every frozen board.
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+)
+
+// Board names one ranking. The set mirrors the CHECK constraint on
+// leaderboard_snapshots.board and the branches of the
+// leaderboard_scores view.
+type Board string
+
+const (
+	// BoardDeepestRegion ranks by season_participation.deepest_region.
+	BoardDeepestRegion Board = "deepest_region"
+	// BoardLongestLife ranks by the longest-lived character, in ticks.
+	BoardLongestLife Board = "longest_life"
+	// BoardMostLoot ranks by total loot recovered over the season.
+	BoardMostLoot Board = "most_loot"
+)
+
+// Boards lists every board in display order.
+var Boards = []Board{BoardDeepestRegion, BoardLongestLife, BoardMostLoot}
+
+// Valid reports whether b is a known board.
+func (b Board) Valid() bool {
+	switch b {
+	case BoardDeepestRegion, BoardLongestLife, BoardMostLoot:
+		return true
+	}
+	return false
+}
+
+var (
+	ErrUnknownBoard   = errors.New("leaderboard: unknown board")
+	ErrSeasonNotFound = errors.New("leaderboard: season not found")
+)
+
+// MaxPageSize caps Limit and Radius so a client can't ask for the whole
+// board in one request.
+const MaxPageSize = 100
+
+// Entry is one placement on a board. Accounts with equal scores share a
+// Rank.
+type Entry struct {
+	Rank        int32
+	AccountID   pgtype.UUID
+	DisplayName string
+	Score       int64
+}
+
+// Page is a slice of one season's board. Frozen is true when the
+// entries come from the end-of-season snapshot rather than live data.
+type Page struct {
+	SeasonID int32
+	Board    Board
+	Frozen   bool
+	Entries  []Entry
+}
+
+// Standings returns limit entries of the board starting offset places
+// from the top. Upcoming and active seasons are ranked live; ended and
+// wiped seasons read the snapshot.
+func Standings(ctx context.Context, q *sqlc.Queries, seasonID int32, board Board, limit, offset int32) (Page, error) {
+	frozen, err := pageSetup(ctx, q, seasonID, board)
+	if err != nil {
+		return Page{}, err
+	}
+	limit = clamp(limit)
+	offset = max(offset, 0)
+
+	page := Page{SeasonID: seasonID, Board: board, Frozen: frozen, Entries: []Entry{}}
+	if frozen {
+		rows, err := q.ListLeaderboardSnapshot(ctx, sqlc.ListLeaderboardSnapshotParams{
+			SeasonID: seasonID, Board: string(board), Limit: limit, Offset: offset,
+		})
+		if err != nil {
+			return Page{}, fmt.Errorf("list %s snapshot: %w", board, err)
+		}
+		for _, r := range rows {
+			page.Entries = append(page.Entries, Entry(r))
+		}
+		return page, nil
+	}
+	rows, err := q.ListLiveLeaderboard(ctx, sqlc.ListLiveLeaderboardParams{
+		SeasonID: seasonID, Board: string(board), Limit: limit, Offset: offset,
+	})
+	if err != nil {
+		return Page{}, fmt.Errorf("list %s standings: %w", board, err)
+	}
+	for _, r := range rows {
+		page.Entries = append(page.Entries, Entry(r))
+	}
+	return page, nil
+}
+
+// AroundAccount returns the account's placement with up to radius
+// entries on either side. An account with no score on the board gets an
+// empty page, not an error.
+func AroundAccount(ctx context.Context, q *sqlc.Queries, seasonID int32, board Board, accountID pgtype.UUID, radius int32) (Page, error) {
+	frozen, err := pageSetup(ctx, q, seasonID, board)
+	if err != nil {
+		return Page{}, err
+	}
+	radius = min(max(radius, 0), MaxPageSize/2)
+
+	page := Page{SeasonID: seasonID, Board: board, Frozen: frozen, Entries: []Entry{}}
+	if frozen {
+		rows, err := q.ListLeaderboardSnapshotAroundAccount(ctx, sqlc.ListLeaderboardSnapshotAroundAccountParams{
+			SeasonID: seasonID, Board: string(board), AccountID: accountID, Radius: radius,
+		})
+		if err != nil {
+			return Page{}, fmt.Errorf("list %s snapshot around account: %w", board, err)
+		}
+		for _, r := range rows {
+			page.Entries = append(page.Entries, Entry(r))
+		}
+		return page, nil
+	}
+	rows, err := q.ListLiveLeaderboardAroundAccount(ctx, sqlc.ListLiveLeaderboardAroundAccountParams{
+		SeasonID: seasonID, Board: string(board), AccountID: accountID, Radius: radius,
+	})
+	if err != nil {
+		return Page{}, fmt.Errorf("list %s standings around account: %w", board, err)
+	}
+	for _, r := range rows {
+		page.Entries = append(page.Entries, Entry(r))
+	}
+	return page, nil
+}
+
+// Snapshot freezes every board for seasonID and returns how many rows
+// it wrote. It is idempotent, so the season's End and the wipe can both
+// call it; the first call wins.
+func Snapshot(ctx context.Context, q *sqlc.Queries, seasonID int32) (int64, error) {
+	n, err := q.SnapshotLeaderboards(ctx, seasonID)
+	if err != nil {
+		return 0, fmt.Errorf("snapshot leaderboards for season %d: %w", seasonID, err)
+	}
+	return n, nil
+}
+
+// Record is one frozen placement, as shown on a profile page or in the
+// hall of fame.
+type Record struct {
+	SeasonID    int32
+	SeasonName  string
+	Board       Board
+	Rank        int32
+	AccountID   pgtype.UUID
+	DisplayName string
+	Score       int64
+}
+
+// ForAccount lists every frozen placement accountID holds, newest
+// season first.
+func ForAccount(ctx context.Context, q *sqlc.Queries, accountID pgtype.UUID) ([]Record, error) {
+	rows, err := q.ListLeaderboardSnapshotsForAccount(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("list placements: %w", err)
+	}
+	out := make([]Record, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, Record{
+			SeasonID:    r.SeasonID,
+			SeasonName:  deref(r.SeasonName),
+			Board:       Board(r.Board),
+			Rank:        r.Rank,
+			AccountID:   accountID,
+			DisplayName: r.DisplayName,
+			Score:       r.Score,
+		})
+	}
+	return out, nil
+}
+
+// HallOfFame returns the top topN ranks of board from every frozen
+// season, newest season first. Ties can make a season contribute more
+// than topN records.
+func HallOfFame(ctx context.Context, q *sqlc.Queries, board Board, topN int32) ([]Record, error) {
+	if !board.Valid() {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownBoard, board)
+	}
+	rows, err := q.ListHallOfFame(ctx, sqlc.ListHallOfFameParams{Board: string(board), TopN: clamp(topN)})
+	if err != nil {
+		return nil, fmt.Errorf("list hall of fame: %w", err)
+	}
+	out := make([]Record, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, Record{
+			SeasonID:    r.SeasonID,
+			SeasonName:  deref(r.SeasonName),
+			Board:       board,
+			Rank:        r.Rank,
+			AccountID:   r.AccountID,
+			DisplayName: r.DisplayName,
+			Score:       r.Score,
+		})
+	}
+	return out, nil
+}
+
+// pageSetup validates board and reports whether seasonID's standings
+// are frozen.
+func pageSetup(ctx context.Context, q *sqlc.Queries, seasonID int32, board Board) (frozen bool, err error) {
+	if !board.Valid() {
+		return false, fmt.Errorf("%w: %q", ErrUnknownBoard, board)
+	}
+	s, err := q.GetSeasonByID(ctx, seasonID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, fmt.Errorf("season %d: %w", seasonID, ErrSeasonNotFound)
+		}
+		return false, fmt.Errorf("load season %d: %w", seasonID, err)
+	}
+	return isFrozen(s.Status), nil
+}
+
+// isFrozen is the status half of pageSetup. Play has stopped in ended
+// and wiped seasons, so their standings can't move.
+func isFrozen(status string) bool {
+	return status == "ended" || status == "wiped"
+}
+
+func clamp(n int32) int32 {
+	if n <= 0 || n > MaxPageSize {
+		return MaxPageSize
+	}
+	return n
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
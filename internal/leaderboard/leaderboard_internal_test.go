@@ -0,0 +1,49 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestBoardValid(t *testing.T) {
+	for _, b := range Boards {
+		if !b.Valid() {
+			t.Errorf("%q: Valid() = false", b)
+		}
+	}
+	if Board("most_kills").Valid() {
+		t.Error(`"most_kills": Valid() = true`)
+	}
+}
+
+func TestIsFrozen(t *testing.T) {
+	for status, want := range map[string]bool{
+		"upcoming": false,
+		"active":   false,
+		"ended":    true,
+		"wiped":    true,
+	} {
+		if got := isFrozen(status); got != want {
+			t.Errorf("isFrozen(%q) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestClamp(t *testing.T) {
+	for in, want := range map[int32]int32{-1: MaxPageSize, 0: MaxPageSize, 25: 25, MaxPageSize + 1: MaxPageSize} {
+		if got := clamp(in); got != want {
+			t.Errorf("clamp(%d) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestUnknownBoardFailsBeforeQuerying(t *testing.T) {
+	// A nil *sqlc.Queries would panic if anything reached the database.
+	if _, err := Standings(context.Background(), nil, 1, "most_kills", 10, 0); !errors.Is(err, ErrUnknownBoard) {
+		t.Errorf("Standings: got %v, want ErrUnknownBoard", err)
+	}
+	if _, err := HallOfFame(context.Background(), nil, "most_kills", 1); !errors.Is(err, ErrUnknownBoard) {
+		t.Errorf("HallOfFame: got %v, want ErrUnknownBoard", err)
+	}
+}
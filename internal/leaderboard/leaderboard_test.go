@@ -0,0 +1,126 @@
+package leaderboard_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+	"github.com/dukerupert/walking-drum/internal/leaderboard"
+	"github.com/dukerupert/walking-drum/internal/season"
+	"github.com/dukerupert/walking-drum/internal/testdb"
+)
+
+var inSeason1 = time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+
+func ranks(p leaderboard.Page) []int32 {
+	out := []int32{}
+	for _, e := range p.Entries {
+		out = append(out, e.Rank)
+	}
+	return out
+}
+
+func TestLeaderboardLiveThenFrozen(t *testing.T) {
+	q, tx := testdb.WithTx(t)
+	ctx := context.Background()
+
+	if _, err := season.SetSeed(ctx, q, 1, 1); err != nil {
+		t.Fatalf("SetSeed: %v", err)
+	}
+	if _, err := season.Activate(ctx, tx, 1, inSeason1); err != nil {
+		t.Fatalf("Activate: %v", err)
+	}
+
+	// Five players reaching depths 9, 7, 7, 3, 1.
+	var accs []pgtype.UUID
+	for i, depth := range []int32{9, 7, 7, 3, 1} {
+		id, _ := uuid.NewV7()
+		acc, err := q.CreateAccount(ctx, sqlc.CreateAccountParams{
+			ID:           pgtype.UUID{Bytes: id, Valid: true},
+			Email:        fmt.Sprintf("lb%d@example.com", i),
+			DisplayName:  fmt.Sprintf("Delver%d", i),
+			PasswordHash: "x",
+		})
+		if err != nil {
+			t.Fatalf("CreateAccount: %v", err)
+		}
+		if _, err := season.RecordDepth(ctx, q, acc.ID, 1, depth); err != nil {
+			t.Fatalf("RecordDepth: %v", err)
+		}
+		accs = append(accs, acc.ID)
+	}
+	if _, err := season.RecordLootRecovered(ctx, q, accs[4], 1, 250); err != nil {
+		t.Fatalf("RecordLootRecovered: %v", err)
+	}
+
+	top, err := leaderboard.Standings(ctx, q, 1, leaderboard.BoardDeepestRegion, 3, 0)
+	if err != nil {
+		t.Fatalf("Standings: %v", err)
+	}
+	if top.Frozen {
+		t.Error("active season's standings reported frozen")
+	}
+	if got := ranks(top); fmt.Sprint(got) != "[1 2 2]" {
+		t.Errorf("top 3 ranks: got %v, want [1 2 2] (tie at 7)", got)
+	}
+	rest, err := leaderboard.Standings(ctx, q, 1, leaderboard.BoardDeepestRegion, 3, 3)
+	if err != nil {
+		t.Fatalf("Standings page 2: %v", err)
+	}
+	if got := ranks(rest); fmt.Sprint(got) != "[4 5]" {
+		t.Errorf("page 2 ranks: got %v, want [4 5]", got)
+	}
+
+	around, err := leaderboard.AroundAccount(ctx, q, 1, leaderboard.BoardDeepestRegion, accs[3], 1)
+	if err != nil {
+		t.Fatalf("AroundAccount: %v", err)
+	}
+	if len(around.Entries) != 3 || around.Entries[1].AccountID != accs[3] {
+		t.Errorf("around 4th place: got %+v, want 3 entries centred on it", around.Entries)
+	}
+
+	loot, err := leaderboard.Standings(ctx, q, 1, leaderboard.BoardMostLoot, 10, 0)
+	if err != nil {
+		t.Fatalf("Standings(most_loot): %v", err)
+	}
+	if len(loot.Entries) != 1 || loot.Entries[0].Score != 250 {
+		t.Errorf("most_loot: got %+v, want only the one looter", loot.Entries)
+	}
+
+	if _, err := season.End(ctx, tx, 1, inSeason1); err != nil {
+		t.Fatalf("End: %v", err)
+	}
+	frozen, err := leaderboard.Standings(ctx, q, 1, leaderboard.BoardDeepestRegion, 10, 0)
+	if err != nil {
+		t.Fatalf("Standings after End: %v", err)
+	}
+	if !frozen.Frozen || fmt.Sprint(ranks(frozen)) != "[1 2 2 4 5]" {
+		t.Errorf("frozen board: got frozen=%v ranks %v", frozen.Frozen, ranks(frozen))
+	}
+
+	hof, err := leaderboard.HallOfFame(ctx, q, leaderboard.BoardDeepestRegion, 1)
+	if err != nil {
+		t.Fatalf("HallOfFame: %v", err)
+	}
+	if len(hof) != 1 || hof[0].AccountID != accs[0] || hof[0].SeasonName != "Season 1" {
+		t.Errorf("hall of fame: got %+v", hof)
+	}
+
+	recs, err := leaderboard.ForAccount(ctx, q, accs[4])
+	if err != nil {
+		t.Fatalf("ForAccount: %v", err)
+	}
+	if len(recs) != 2 {
+		t.Fatalf("placements for the looter: got %+v, want deepest_region and most_loot", recs)
+	}
+	for _, r := range recs {
+		if r.DisplayName != "Delver4" || r.SeasonName != "Season 1" {
+			t.Errorf("placement %s: got %+v, want Delver4 in Season 1", r.Board, r)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package season_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dukerupert/walking-drum/internal/season"
+	"github.com/dukerupert/walking-drum/internal/testdb"
+)
+
+// Season 1 is seeded by 00004_seasons.sql with world_seed = 0 and a
+// June–September 2026 window; these tests pick a "now" inside it.
+var inSeason1 = time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+
+func TestLifecycleHappyPath(t *testing.T) {
+	q, tx := testdb.WithTx(t)
+	ctx := context.Background()
+
+	if _, err := season.Activate(ctx, tx, 1, inSeason1); !errors.Is(err, season.ErrSeedNotSet) {
+		t.Fatalf("Activate with placeholder seed: got %v, want ErrSeedNotSet", err)
+	}
+	if _, err := season.SetSeed(ctx, q, 1, 1234); err != nil {
+		t.Fatalf("SetSeed: %v", err)
+	}
+
+	s, err := season.Activate(ctx, tx, 1, inSeason1)
+	if err != nil {
+		t.Fatalf("Activate: %v", err)
+	}
+	if s.Status != "active" {
+		t.Errorf("status after Activate: got %q, want active", s.Status)
+	}
+	if _, err := season.SetSeed(ctx, q, 1, 99); !errors.Is(err, season.ErrSeedLocked) {
+		t.Errorf("SetSeed on active season: got %v, want ErrSeedLocked", err)
+	}
+
+	if _, err := season.End(ctx, tx, 1, inSeason1); err != nil {
+		t.Fatalf("End: %v", err)
+	}
+	if _, err := season.Activate(ctx, tx, 1, inSeason1); !errors.Is(err, season.ErrIllegalTransition) {
+		t.Errorf("reactivating ended season: got %v, want ErrIllegalTransition", err)
+	}
+
+	wiped, err := season.MarkWiped(ctx, tx, 1, inSeason1)
+	if err != nil {
+		t.Fatalf("MarkWiped: %v", err)
+	}
+	if wiped.Status != "wiped" {
+		t.Errorf("status after MarkWiped: got %q, want wiped", wiped.Status)
+	}
+	if !wiped.WipedAt.Valid || !wiped.WipedAt.Time.Equal(inSeason1) {
+		t.Errorf("wiped_at: got %v, want %v", wiped.WipedAt, inSeason1)
+	}
+}
+
+func TestActivateRequiresPreviousSeasonClosed(t *testing.T) {
+	q, tx := testdb.WithTx(t)
+	ctx := context.Background()
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO seasons (id, name, status, world_seed, starts_at, ends_at)
+		VALUES (2, 'Season 2', 'upcoming', 7, '2026-06-01', '2026-12-01')
+	`); err != nil {
+		t.Fatalf("insert season 2: %v", err)
+	}
+
+	// Season 1 is still upcoming, so season 2 can't jump the queue.
+	if _, err := season.Activate(ctx, tx, 2, inSeason1); !errors.Is(err, season.ErrPreviousSeasonOpen) {
+		t.Fatalf("Activate season 2 over upcoming season 1: got %v, want ErrPreviousSeasonOpen", err)
+	}
+
+	if _, err := season.SetSeed(ctx, q, 1, 1); err != nil {
+		t.Fatalf("SetSeed: %v", err)
+	}
+	if _, err := season.Activate(ctx, tx, 1, inSeason1); err != nil {
+		t.Fatalf("Activate season 1: %v", err)
+	}
+	if _, err := season.Activate(ctx, tx, 2, inSeason1); !errors.Is(err, season.ErrPreviousSeasonOpen) {
+		t.Fatalf("Activate season 2 while season 1 active: got %v, want ErrPreviousSeasonOpen", err)
+	}
+	if _, err := season.End(ctx, tx, 1, inSeason1); err != nil {
+		t.Fatalf("End season 1: %v", err)
+	}
+	if _, err := season.Activate(ctx, tx, 2, inSeason1); err != nil {
+		t.Fatalf("Activate season 2 after season 1 ended: %v", err)
+	}
+}
+
+func TestActivateRejectsExpiredSeason(t *testing.T) {
+	q, tx := testdb.WithTx(t)
+	ctx := context.Background()
+
+	if _, err := season.SetSeed(ctx, q, 1, 1); err != nil {
+		t.Fatalf("SetSeed: %v", err)
+	}
+	afterEnd := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := season.Activate(ctx, tx, 1, afterEnd); !errors.Is(err, season.ErrSeasonExpired) {
+		t.Fatalf("Activate after ends_at: got %v, want ErrSeasonExpired", err)
+	}
+}
+
+func TestTransitionUnknownSeason(t *testing.T) {
+	_, tx := testdb.WithTx(t)
+	if _, err := season.End(context.Background(), tx, 404, inSeason1); !errors.Is(err, season.ErrSeasonNotFound) {
+		t.Fatalf("End(404): got %v, want ErrSeasonNotFound", err)
+	}
+}
+
+func TestSetModifiers(t *testing.T) {
+	q, tx := testdb.WithTx(t)
+	ctx := context.Background()
+
+	loot := 3.0
+	s, err := season.SetModifiers(ctx, q, 1, season.SeasonModifiers{LootMultiplier: &loot})
+	if err != nil {
+		t.Fatalf("SetModifiers: %v", err)
+	}
+	m, err := season.ParseModifiers(s.Modifiers)
+	if err != nil {
+		t.Fatalf("ParseModifiers(stored): %v", err)
+	}
+	if m.LootMultiplier == nil || *m.LootMultiplier != 3 {
+		t.Errorf("stored loot multiplier: got %v, want 3", m.LootMultiplier)
+	}
+
+	bad := -1.0
+	if _, err := season.SetModifiers(ctx, q, 1, season.SeasonModifiers{LootMultiplier: &bad}); !errors.Is(err, season.ErrInvalidModifiers) {
+		t.Errorf("SetModifiers(negative): got %v, want ErrInvalidModifiers", err)
+	}
+
+	if _, err := season.SetSeed(ctx, q, 1, 1); err != nil {
+		t.Fatalf("SetSeed: %v", err)
+	}
+	if _, err := season.Activate(ctx, tx, 1, inSeason1); err != nil {
+		t.Fatalf("Activate: %v", err)
+	}
+	r := season.NewResolver(season.DefaultModifiers())
+	if err := r.Load(ctx, q); err != nil {
+		t.Fatalf("Resolver.Load: %v", err)
+	}
+	if r.SeasonID() != 1 || r.LootMultiplier() != 3 {
+		t.Errorf("resolver: season %d loot %v, want season 1 loot 3", r.SeasonID(), r.LootMultiplier())
+	}
+
+	if _, err := season.End(ctx, tx, 1, inSeason1); err != nil {
+		t.Fatalf("End: %v", err)
+	}
+	if _, err := season.SetModifiers(ctx, q, 1, season.SeasonModifiers{}); !errors.Is(err, season.ErrModifiersLocked) {
+		t.Errorf("SetModifiers on ended season: got %v, want ErrModifiersLocked", err)
+	}
+}
+
+func TestCreateGeneratesSeed(t *testing.T) {
+	q, _ := testdb.WithTx(t)
+	s, err := season.Create(context.Background(), q, season.NewSeason{
+		ID:       2,
+		StartsAt: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		EndsAt:   time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if s.WorldSeed == 0 {
+		t.Error("Create left world_seed at 0; want a generated seed")
+	}
+}
@@ -0,0 +1,279 @@
+package season
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sync/atomic"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+)
+
+// Feature names a gameplay system a season can switch off wholesale.
+// The set is closed; ParseModifiers rejects anything else so a typo in
+// an operator's JSON can't silently leave a feature on.
+type Feature string
+
+const (
+	FeatureTrading       Feature = "trading"
+	FeatureChat          Feature = "chat"
+	FeatureCorpseLooting Feature = "corpse_looting"
+	FeatureHostileSpawns Feature = "hostile_spawns"
+)
+
+// Valid reports whether f is a known feature.
+func (f Feature) Valid() bool {
+	switch f {
+	case FeatureTrading, FeatureChat, FeatureCorpseLooting, FeatureHostileSpawns:
+		return true
+	}
+	return false
+}
+
+// maxMultiplier bounds the scaling knobs. Anything past it is far more
+// likely a decimal-point slip than a deliberate season theme.
+const maxMultiplier = 100
+
+// SeasonModifiers is the typed shape of seasons.modifiers (DESIGN.md
+// §5.3, §2.5). Every field is optional; an absent field means "use the
+// default." Pointers distinguish "absent" from an explicit value.
+type SeasonModifiers struct {
+	// ActionCosts overrides base energy costs by action name.
+	ActionCosts map[string]int32 `json:"action_costs,omitempty"`
+
+	// EnergyRegenMultiplier scales every actor's energy regen.
+	EnergyRegenMultiplier *float64 `json:"energy_regen_multiplier,omitempty"`
+
+	// LootMultiplier and DangerMultiplier scale loot tables and monster
+	// difficulty respectively.
+	LootMultiplier   *float64 `json:"loot_multiplier,omitempty"`
+	DangerMultiplier *float64 `json:"danger_multiplier,omitempty"`
+
+	// DisabledFeatures switches whole systems off for the season.
+	DisabledFeatures []Feature `json:"disabled_features,omitempty"`
+}
+
+var (
+	// ErrInvalidModifiers wraps every decode or validation failure.
+	ErrInvalidModifiers = errors.New("season: invalid modifiers")
+	ErrModifiersLocked  = errors.New("season: modifiers can only change while upcoming or active")
+)
+
+// ParseModifiers strictly decodes a seasons.modifiers blob: unknown
+// keys, trailing data, and out-of-range values are all errors. An
+// empty blob decodes to the zero value.
+func ParseModifiers(raw []byte) (SeasonModifiers, error) {
+	var m SeasonModifiers
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return m, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&m); err != nil {
+		return SeasonModifiers{}, fmt.Errorf("%w: %v", ErrInvalidModifiers, err)
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return SeasonModifiers{}, fmt.Errorf("%w: trailing data after object", ErrInvalidModifiers)
+	}
+	if err := m.Validate(); err != nil {
+		return SeasonModifiers{}, err
+	}
+	return m, nil
+}
+
+// Validate checks value ranges. Costs must be positive, multipliers
+// positive and at most maxMultiplier, features known.
+func (m SeasonModifiers) Validate() error {
+	var errs []error
+	for _, action := range slices.Sorted(maps.Keys(m.ActionCosts)) {
+		if action == "" {
+			errs = append(errs, errors.New("action_costs: empty action name"))
+		}
+		if c := m.ActionCosts[action]; c <= 0 {
+			errs = append(errs, fmt.Errorf("action_costs[%q]: cost %d must be positive", action, c))
+		}
+	}
+	for _, mult := range []struct {
+		name string
+		v    *float64
+	}{
+		{"energy_regen_multiplier", m.EnergyRegenMultiplier},
+		{"loot_multiplier", m.LootMultiplier},
+		{"danger_multiplier", m.DangerMultiplier},
+	} {
+		if name, v := mult.name, mult.v; v != nil && !(*v > 0 && *v <= maxMultiplier) {
+			errs = append(errs, fmt.Errorf("%s: %v must be in (0, %d]", name, *v, maxMultiplier))
+		}
+	}
+	for _, f := range m.DisabledFeatures {
+		if !f.Valid() {
+			errs = append(errs, fmt.Errorf("disabled_features: unknown feature %q", f))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidModifiers, errors.Join(errs...))
+	}
+	return nil
+}
+
+// Encode validates m and returns the JSONB blob for seasons.modifiers.
+func (m SeasonModifiers) Encode() ([]byte, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("encode modifiers: %w", err)
+	}
+	return b, nil
+}
+
+// SetModifiers validates m and writes it to an upcoming or active
+// season. Ended and wiped seasons are history and refuse the write.
+func SetModifiers(ctx context.Context, q *sqlc.Queries, id int32, m SeasonModifiers) (sqlc.Season, error) {
+	raw, err := m.Encode()
+	if err != nil {
+		return sqlc.Season{}, fmt.Errorf("season %d: %w", id, err)
+	}
+	s, err := q.UpdateSeasonModifiers(ctx, sqlc.UpdateSeasonModifiersParams{ID: id, Modifiers: raw})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.Season{}, fmt.Errorf("season %d: %w", id, ErrModifiersLocked)
+		}
+		return sqlc.Season{}, fmt.Errorf("set modifiers: %w", err)
+	}
+	return s, nil
+}
+
+// DefaultModifiers is the baseline every season's overrides are merged
+// onto: unit multipliers, no cost overrides, nothing disabled.
+func DefaultModifiers() SeasonModifiers {
+	one := func() *float64 { v := 1.0; return &v }
+	return SeasonModifiers{
+		EnergyRegenMultiplier: one(),
+		LootMultiplier:        one(),
+		DangerMultiplier:      one(),
+	}
+}
+
+// Merge returns base with overrides applied: per-action costs and set
+// multipliers replace base's, disabled features accumulate. Neither
+// argument is modified.
+func Merge(base, overrides SeasonModifiers) SeasonModifiers {
+	out := base
+	out.ActionCosts = maps.Clone(base.ActionCosts)
+	if len(overrides.ActionCosts) > 0 && out.ActionCosts == nil {
+		out.ActionCosts = make(map[string]int32, len(overrides.ActionCosts))
+	}
+	maps.Copy(out.ActionCosts, overrides.ActionCosts)
+	if overrides.EnergyRegenMultiplier != nil {
+		out.EnergyRegenMultiplier = overrides.EnergyRegenMultiplier
+	}
+	if overrides.LootMultiplier != nil {
+		out.LootMultiplier = overrides.LootMultiplier
+	}
+	if overrides.DangerMultiplier != nil {
+		out.DangerMultiplier = overrides.DangerMultiplier
+	}
+	out.DisabledFeatures = slices.Clone(base.DisabledFeatures)
+	for _, f := range overrides.DisabledFeatures {
+		if !slices.Contains(out.DisabledFeatures, f) {
+			out.DisabledFeatures = append(out.DisabledFeatures, f)
+		}
+	}
+	return out
+}
+
+// Resolver is the one place gameplay code asks for effective season
+// values. It holds the defaults merged with the active season's
+// overrides and is safe for concurrent reads; Load swaps in a fresh
+// snapshot atomically.
+type Resolver struct {
+	defaults SeasonModifiers
+	cur      atomic.Pointer[resolved]
+}
+
+type resolved struct {
+	seasonID int32 // 0 = no active season
+	mods     SeasonModifiers
+}
+
+// NewResolver returns a Resolver reporting defaults until Load or Set
+// is called.
+func NewResolver(defaults SeasonModifiers) *Resolver {
+	r := &Resolver{defaults: defaults}
+	r.cur.Store(&resolved{mods: Merge(defaults, SeasonModifiers{})})
+	return r
+}
+
+// Load reads the active season's modifiers and makes them current. With
+// no active season, the resolver falls back to defaults. A season whose
+// stored modifiers fail strict decoding is an error, and the previous
+// snapshot stays in place.
+func (r *Resolver) Load(ctx context.Context, q *sqlc.Queries) error {
+	s, err := q.GetActiveSeason(ctx)
+	if errors.Is(err, pgx.ErrNoRows) {
+		r.Set(0, SeasonModifiers{})
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("load active season: %w", err)
+	}
+	m, err := ParseModifiers(s.Modifiers)
+	if err != nil {
+		return fmt.Errorf("season %d: %w", s.ID, err)
+	}
+	r.Set(s.ID, m)
+	return nil
+}
+
+// Set makes overrides current for seasonID without touching the DB.
+func (r *Resolver) Set(seasonID int32, overrides SeasonModifiers) {
+	r.cur.Store(&resolved{seasonID: seasonID, mods: Merge(r.defaults, overrides)})
+}
+
+// SeasonID is the season the current snapshot came from, or 0.
+func (r *Resolver) SeasonID() int32 { return r.cur.Load().seasonID }
+
+// Effective returns a copy of the merged modifiers.
+func (r *Resolver) Effective() SeasonModifiers {
+	return Merge(r.cur.Load().mods, SeasonModifiers{})
+}
+
+// ActionCost returns the season's override for action, or base if the
+// season doesn't override it.
+func (r *Resolver) ActionCost(action string, base int32) int32 {
+	if c, ok := r.cur.Load().mods.ActionCosts[action]; ok {
+		return c
+	}
+	return base
+}
+
+// EnergyRegenMultiplier, LootMultiplier and DangerMultiplier return the
+// effective scaling factor, 1 if neither defaults nor season set one.
+func (r *Resolver) EnergyRegenMultiplier() float64 {
+	return orOne(r.cur.Load().mods.EnergyRegenMultiplier)
+}
+
+func (r *Resolver) LootMultiplier() float64 { return orOne(r.cur.Load().mods.LootMultiplier) }
+
+func (r *Resolver) DangerMultiplier() float64 { return orOne(r.cur.Load().mods.DangerMultiplier) }
+
+// FeatureEnabled reports whether f is switched on this season.
+func (r *Resolver) FeatureEnabled(f Feature) bool {
+	return !slices.Contains(r.cur.Load().mods.DisabledFeatures, f)
+}
+
+func orOne(v *float64) float64 {
+	if v == nil {
+		return 1
+	}
+	return *v
+}
@@ -0,0 +1,102 @@
+package season
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func f64(v float64) *float64 { return &v }
+
+func TestParseModifiers(t *testing.T) {
+	good := `{"action_costs":{"move":80},"loot_multiplier":2,"disabled_features":["trading"]}`
+	m, err := ParseModifiers([]byte(good))
+	if err != nil {
+		t.Fatalf("ParseModifiers(good): %v", err)
+	}
+	if m.ActionCosts["move"] != 80 || *m.LootMultiplier != 2 || m.EnergyRegenMultiplier != nil {
+		t.Errorf("decoded %+v", m)
+	}
+
+	for _, raw := range []string{"", "  ", "{}"} {
+		if _, err := ParseModifiers([]byte(raw)); err != nil {
+			t.Errorf("ParseModifiers(%q): %v", raw, err)
+		}
+	}
+
+	bad := []struct{ name, raw string }{
+		{"unknown key", `{"loot_multipler":2}`},
+		{"trailing data", `{} {}`},
+		{"zero cost", `{"action_costs":{"move":0}}`},
+		{"negative multiplier", `{"danger_multiplier":-1}`},
+		{"huge multiplier", `{"energy_regen_multiplier":1000}`},
+		{"unknown feature", `{"disabled_features":["pvp"]}`},
+		{"wrong type", `{"loot_multiplier":"2x"}`},
+	}
+	for _, tc := range bad {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ParseModifiers([]byte(tc.raw)); !errors.Is(err, ErrInvalidModifiers) {
+				t.Errorf("got %v, want ErrInvalidModifiers", err)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	base := DefaultModifiers()
+	base.ActionCosts = map[string]int32{"move": 100, "attack": 100}
+	base.DisabledFeatures = []Feature{FeatureChat}
+
+	got := Merge(base, SeasonModifiers{
+		ActionCosts:      map[string]int32{"move": 50},
+		DangerMultiplier: f64(1.5),
+		DisabledFeatures: []Feature{FeatureChat, FeatureTrading},
+	})
+
+	if got.ActionCosts["move"] != 50 || got.ActionCosts["attack"] != 100 {
+		t.Errorf("action costs: got %v", got.ActionCosts)
+	}
+	if *got.DangerMultiplier != 1.5 || *got.LootMultiplier != 1 {
+		t.Errorf("multipliers: danger %v loot %v", *got.DangerMultiplier, *got.LootMultiplier)
+	}
+	if !slices.Equal(got.DisabledFeatures, []Feature{FeatureChat, FeatureTrading}) {
+		t.Errorf("disabled features: got %v", got.DisabledFeatures)
+	}
+	if base.ActionCosts["move"] != 100 || len(base.DisabledFeatures) != 1 {
+		t.Errorf("Merge modified base: %+v", base)
+	}
+}
+
+func TestResolver(t *testing.T) {
+	r := NewResolver(DefaultModifiers())
+	if r.SeasonID() != 0 || r.ActionCost("move", 100) != 100 || r.LootMultiplier() != 1 {
+		t.Fatalf("fresh resolver isn't reporting defaults")
+	}
+
+	r.Set(3, SeasonModifiers{
+		ActionCosts:           map[string]int32{"move": 60},
+		EnergyRegenMultiplier: f64(2),
+		DisabledFeatures:      []Feature{FeatureCorpseLooting},
+	})
+	if r.SeasonID() != 3 {
+		t.Errorf("SeasonID: got %d, want 3", r.SeasonID())
+	}
+	if got := r.ActionCost("move", 100); got != 60 {
+		t.Errorf("ActionCost(move): got %d, want 60", got)
+	}
+	if got := r.ActionCost("attack", 100); got != 100 {
+		t.Errorf("ActionCost(attack): got %d, want base 100", got)
+	}
+	if r.EnergyRegenMultiplier() != 2 || r.DangerMultiplier() != 1 {
+		t.Errorf("multipliers: regen %v danger %v", r.EnergyRegenMultiplier(), r.DangerMultiplier())
+	}
+	if r.FeatureEnabled(FeatureCorpseLooting) || !r.FeatureEnabled(FeatureTrading) {
+		t.Errorf("FeatureEnabled disagrees with disabled_features")
+	}
+
+	eff := r.Effective()
+	eff.ActionCosts["move"] = 1
+	if r.ActionCost("move", 100) != 60 {
+		t.Errorf("mutating Effective() leaked into the resolver")
+	}
+}
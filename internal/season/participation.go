@@ -0,0 +1,109 @@
+package season
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+)
+
+// ErrSeasonNotActive is returned by the participation writers when the
+// season isn't the one being played. Ended and wiped seasons keep the
+// stats they closed with.
+var ErrSeasonNotActive = errors.New("season: not active")
+
+// Join records that accountID has started playing seasonID. It is safe
+// to call on every login; only the first call creates the row.
+func Join(ctx context.Context, q *sqlc.Queries, accountID pgtype.UUID, seasonID int32) (sqlc.SeasonParticipation, error) {
+	p, err := q.EnsureSeasonParticipation(ctx, sqlc.EnsureSeasonParticipationParams{
+		AccountID: accountID,
+		SeasonID:  seasonID,
+	})
+	return participationResult(p, err, seasonID, "join")
+}
+
+// RecordCharacterCreated bumps characters_made by one, joining the
+// account to the season first if needed. The increment happens in the
+// database, so concurrent calls don't lose counts.
+func RecordCharacterCreated(ctx context.Context, q *sqlc.Queries, accountID pgtype.UUID, seasonID int32) (sqlc.SeasonParticipation, error) {
+	p, err := q.IncrementCharactersMade(ctx, sqlc.IncrementCharactersMadeParams{
+		AccountID: accountID,
+		SeasonID:  seasonID,
+	})
+	return participationResult(p, err, seasonID, "record character")
+}
+
+// RecordDeath bumps deaths by one, with the same guarantees as
+// RecordCharacterCreated.
+func RecordDeath(ctx context.Context, q *sqlc.Queries, accountID pgtype.UUID, seasonID int32) (sqlc.SeasonParticipation, error) {
+	p, err := q.IncrementDeaths(ctx, sqlc.IncrementDeathsParams{
+		AccountID: accountID,
+		SeasonID:  seasonID,
+	})
+	return participationResult(p, err, seasonID, "record death")
+}
+
+// RecordDepth notes that the account reached region. deepest_region only
+// ever grows, so callers can report every region entered without
+// checking the current record first.
+func RecordDepth(ctx context.Context, q *sqlc.Queries, accountID pgtype.UUID, seasonID, region int32) (sqlc.SeasonParticipation, error) {
+	p, err := q.RecordDeepestRegion(ctx, sqlc.RecordDeepestRegionParams{
+		AccountID: accountID,
+		SeasonID:  seasonID,
+		Region:    region,
+	})
+	return participationResult(p, err, seasonID, "record depth")
+}
+
+// RecordLifeLength notes that one of the account's characters lived for
+// ticks. Only the longest life is kept; it feeds the longest-life
+// leaderboard.
+func RecordLifeLength(ctx context.Context, q *sqlc.Queries, accountID pgtype.UUID, seasonID int32, ticks int64) (sqlc.SeasonParticipation, error) {
+	p, err := q.RecordLifeLength(ctx, sqlc.RecordLifeLengthParams{
+		AccountID: accountID,
+		SeasonID:  seasonID,
+		Ticks:     ticks,
+	})
+	return participationResult(p, err, seasonID, "record life length")
+}
+
+// RecordLootRecovered adds amount to the account's loot total for the
+// season.
+func RecordLootRecovered(ctx context.Context, q *sqlc.Queries, accountID pgtype.UUID, seasonID int32, amount int64) (sqlc.SeasonParticipation, error) {
+	if amount <= 0 {
+		return sqlc.SeasonParticipation{}, fmt.Errorf("record loot: amount %d must be positive", amount)
+	}
+	p, err := q.AddLootRecovered(ctx, sqlc.AddLootRecoveredParams{
+		AccountID: accountID,
+		SeasonID:  seasonID,
+		Amount:    amount,
+	})
+	return participationResult(p, err, seasonID, "record loot")
+}
+
+// History lists every season accountID has played, newest first, for
+// the profile page. Seasons that have been wiped still appear, with
+// their final_summary.
+func History(ctx context.Context, q *sqlc.Queries, accountID pgtype.UUID) ([]sqlc.ListParticipationForAccountRow, error) {
+	rows, err := q.ListParticipationForAccount(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("list participation: %w", err)
+	}
+	return rows, nil
+}
+
+// participationResult maps the no-row case of the guarded upserts to
+// ErrSeasonNotActive.
+func participationResult(p sqlc.SeasonParticipation, err error, seasonID int32, op string) (sqlc.SeasonParticipation, error) {
+	if err == nil {
+		return p, nil
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.SeasonParticipation{}, fmt.Errorf("%s: season %d: %w", op, seasonID, ErrSeasonNotActive)
+	}
+	return sqlc.SeasonParticipation{}, fmt.Errorf("%s: %w", op, err)
+}
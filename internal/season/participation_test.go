@@ -0,0 +1,80 @@
+package season_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+	"github.com/dukerupert/walking-drum/internal/season"
+	"github.com/dukerupert/walking-drum/internal/testdb"
+)
+
+func makeAccount(t *testing.T, ctx context.Context, q *sqlc.Queries, email string) pgtype.UUID {
+	t.Helper()
+	id, _ := uuid.NewV7()
+	acc, err := q.CreateAccount(ctx, sqlc.CreateAccountParams{
+		ID:           pgtype.UUID{Bytes: id, Valid: true},
+		Email:        email,
+		DisplayName:  email,
+		PasswordHash: "x",
+	})
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	return acc.ID
+}
+
+func TestParticipation(t *testing.T) {
+	q, tx := testdb.WithTx(t)
+	ctx := context.Background()
+	acc := makeAccount(t, ctx, q, "participant@example.com")
+
+	if _, err := season.Join(ctx, q, acc, 1); !errors.Is(err, season.ErrSeasonNotActive) {
+		t.Fatalf("Join upcoming season: got %v, want ErrSeasonNotActive", err)
+	}
+	if _, err := season.SetSeed(ctx, q, 1, 1); err != nil {
+		t.Fatalf("SetSeed: %v", err)
+	}
+	if _, err := season.Activate(ctx, tx, 1, inSeason1); err != nil {
+		t.Fatalf("Activate: %v", err)
+	}
+
+	if _, err := season.Join(ctx, q, acc, 1); err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+	if _, err := season.RecordCharacterCreated(ctx, q, acc, 1); err != nil {
+		t.Fatalf("RecordCharacterCreated: %v", err)
+	}
+	if _, err := season.RecordDeath(ctx, q, acc, 1); err != nil {
+		t.Fatalf("RecordDeath: %v", err)
+	}
+	if _, err := season.RecordDepth(ctx, q, acc, 1, 5); err != nil {
+		t.Fatalf("RecordDepth(5): %v", err)
+	}
+	p, err := season.RecordDepth(ctx, q, acc, 1, 3)
+	if err != nil {
+		t.Fatalf("RecordDepth(3): %v", err)
+	}
+	if p.CharactersMade != 1 || p.Deaths != 1 || p.DeepestRegion == nil || *p.DeepestRegion != 5 {
+		t.Errorf("participation: got %+v", p)
+	}
+
+	if _, err := season.End(ctx, tx, 1, inSeason1); err != nil {
+		t.Fatalf("End: %v", err)
+	}
+	if _, err := season.RecordDeath(ctx, q, acc, 1); !errors.Is(err, season.ErrSeasonNotActive) {
+		t.Errorf("RecordDeath after End: got %v, want ErrSeasonNotActive", err)
+	}
+
+	hist, err := season.History(ctx, q, acc)
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if len(hist) != 1 || hist[0].SeasonID != 1 || hist[0].Deaths != 1 {
+		t.Errorf("history: got %+v", hist)
+	}
+}
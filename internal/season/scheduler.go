@@ -0,0 +1,262 @@
+package season
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+)
+
+// Clock is the scheduler's only source of wall-clock time. Tests hand
+// in a fake and step it forward; production uses SystemClock.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the real wall clock.
+type SystemClock struct{}
+
+// Now returns time.Now().
+func (SystemClock) Now() time.Time { return time.Now() }
+
+// EventKind identifies what the scheduler just did or announced.
+type EventKind string
+
+const (
+	EventStartAnnounced EventKind = "start_announced"
+	EventActivated      EventKind = "activated"
+	EventEndAnnounced   EventKind = "end_announced"
+	EventEnded          EventKind = "ended"
+	EventExpired        EventKind = "expired"
+	EventCancelled      EventKind = "cancelled"
+)
+
+// Event is emitted to SchedulerConfig.OnEvent. At is the scheduled
+// moment the event is about (starts_at or ends_at), not when the
+// scheduler noticed it. Lead is set only for announcements.
+type Event struct {
+	Kind     EventKind
+	SeasonID int32
+	At       time.Time
+	Lead     time.Duration
+}
+
+// DefaultAnnounceLeads is the "advance announcement (1–2 weeks)" from
+// DESIGN.md §3.5.
+var DefaultAnnounceLeads = []time.Duration{14 * 24 * time.Hour, 7 * 24 * time.Hour}
+
+// SchedulerConfig tunes a Scheduler. Zero values pick the defaults.
+type SchedulerConfig struct {
+	// Interval between passes in Run. Defaults to one minute; season
+	// boundaries don't need finer resolution.
+	Interval time.Duration
+
+	// AnnounceLeads are how far ahead of starts_at / ends_at to emit
+	// announcement events. Defaults to DefaultAnnounceLeads.
+	AnnounceLeads []time.Duration
+
+	// Clock defaults to SystemClock.
+	Clock Clock
+
+	// OnEvent receives every event, synchronously, from the goroutine
+	// running Tick. Nil discards events.
+	OnEvent func(Event)
+
+	// CancelExpired lets the scheduler cancel an upcoming season whose
+	// ends_at passed before it ever opened, so the next season can open.
+	// Cancelling is terminal, so it's off by default: the scheduler
+	// emits EventExpired once and leaves the call to an operator.
+	CancelExpired bool
+
+	// OnError receives errors from passes made by Run, which keeps
+	// going afterwards. Nil discards them. Tick returns its error
+	// directly instead.
+	OnError func(error)
+}
+
+// Scheduler flips season status at starts_at / ends_at so nobody has
+// to remember to do it by hand. All transitions go through Activate and
+// End, so it's subject to the same preconditions and row locks as an
+// operator.
+//
+// Announcement bookkeeping is in memory: a restarted scheduler re-emits
+// the most recent announcement that is due, and EventExpired. Subscribers should treat
+// announcements as at-least-once.
+type Scheduler struct {
+	tb        TxBeginner
+	cfg       SchedulerConfig
+	announced map[announceKey]bool
+}
+
+type announceKey struct {
+	seasonID int32
+	kind     EventKind
+	lead     time.Duration
+}
+
+// NewScheduler returns a Scheduler over tb with cfg's defaults filled.
+func NewScheduler(tb TxBeginner, cfg SchedulerConfig) *Scheduler {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	if cfg.AnnounceLeads == nil {
+		cfg.AnnounceLeads = DefaultAnnounceLeads
+	}
+	// Longest lead first; dueLead relies on the order.
+	cfg.AnnounceLeads = slices.Clone(cfg.AnnounceLeads)
+	slices.SortFunc(cfg.AnnounceLeads, func(a, b time.Duration) int { return cmp.Compare(b, a) })
+	if cfg.Clock == nil {
+		cfg.Clock = SystemClock{}
+	}
+	return &Scheduler{tb: tb, cfg: cfg, announced: map[announceKey]bool{}}
+}
+
+// Run makes a pass immediately, then one every cfg.Interval, until ctx
+// is cancelled. It returns nil on cancellation; pass errors go to
+// cfg.OnError.
+func (s *Scheduler) Run(ctx context.Context) error {
+	t := time.NewTicker(s.cfg.Interval)
+	defer t.Stop()
+	for {
+		if err := s.Tick(ctx); err != nil && s.cfg.OnError != nil {
+			s.cfg.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// Tick makes one scheduling pass at the clock's current time: close the
+// active season if its end has passed, report (or, with CancelExpired,
+// cancel) an upcoming season whose end passed before it ever opened,
+// open the next upcoming season if its start has passed and nothing is
+// active, and emit any announcements that have come due.
+func (s *Scheduler) Tick(ctx context.Context) error {
+	now := s.cfg.Clock.Now()
+
+	active, upcoming, err := s.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	if active != nil {
+		end := active.EndsAt.Time
+		if !now.Before(end) {
+			if _, err := End(ctx, s.tb, active.ID, now); err != nil {
+				return fmt.Errorf("scheduler: end season %d: %w", active.ID, err)
+			}
+			s.emit(Event{Kind: EventEnded, SeasonID: active.ID, At: end})
+			active = nil
+		} else {
+			s.announce(active.ID, EventEndAnnounced, end, now)
+		}
+	}
+
+	// An upcoming season that was never opened and whose window has
+	// closed can't be activated, and every season after it waits on it.
+	for upcoming != nil && !now.Before(upcoming.EndsAt.Time) {
+		if !s.cfg.CancelExpired {
+			key := announceKey{upcoming.ID, EventExpired, 0}
+			if !s.announced[key] {
+				s.announced[key] = true
+				s.emit(Event{Kind: EventExpired, SeasonID: upcoming.ID, At: upcoming.EndsAt.Time})
+			}
+			upcoming = nil
+			break
+		}
+		if _, err := Cancel(ctx, s.tb, upcoming.ID, now); err != nil {
+			return fmt.Errorf("scheduler: cancel expired season %d: %w", upcoming.ID, err)
+		}
+		s.emit(Event{Kind: EventCancelled, SeasonID: upcoming.ID, At: upcoming.EndsAt.Time})
+		if _, upcoming, err = s.load(ctx); err != nil {
+			return err
+		}
+	}
+
+	if upcoming != nil {
+		start := upcoming.StartsAt.Time
+		if active == nil && !now.Before(start) {
+			if _, err := Activate(ctx, s.tb, upcoming.ID, now); err != nil {
+				return fmt.Errorf("scheduler: activate season %d: %w", upcoming.ID, err)
+			}
+			s.emit(Event{Kind: EventActivated, SeasonID: upcoming.ID, At: start})
+		} else if now.Before(start) {
+			s.announce(upcoming.ID, EventStartAnnounced, start, now)
+		}
+	}
+	return nil
+}
+
+// load reads the active season and the next upcoming one in a single
+// read transaction. Either may be nil.
+func (s *Scheduler) load(ctx context.Context) (active, upcoming *sqlc.Season, err error) {
+	tx, err := s.tb.Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("scheduler: begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	q := sqlc.New(tx)
+
+	if a, err := q.GetActiveSeason(ctx); err == nil {
+		active = &a
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, fmt.Errorf("scheduler: load active season: %w", err)
+	}
+	if u, err := q.GetNextUpcomingSeason(ctx); err == nil {
+		upcoming = &u
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, fmt.Errorf("scheduler: load upcoming season: %w", err)
+	}
+	return active, upcoming, nil
+}
+
+// announce emits the tightest due, not-yet-sent announcement for target.
+// Looser leads that were skipped (the scheduler was down, or started
+// late) are marked sent too — there's no point announcing "two weeks
+// out" three days before the event.
+func (s *Scheduler) announce(seasonID int32, kind EventKind, target, now time.Time) {
+	lead, ok := dueLead(s.cfg.AnnounceLeads, target, now)
+	if !ok {
+		return
+	}
+	key := announceKey{seasonID, kind, lead}
+	if s.announced[key] {
+		return
+	}
+	for _, l := range s.cfg.AnnounceLeads {
+		if l >= lead {
+			s.announced[announceKey{seasonID, kind, l}] = true
+		}
+	}
+	s.emit(Event{Kind: kind, SeasonID: seasonID, At: target, Lead: lead})
+}
+
+// dueLead returns the smallest lead whose window has opened, i.e. the
+// smallest l with now >= target-l. leads must be sorted longest first.
+func dueLead(leads []time.Duration, target, now time.Time) (time.Duration, bool) {
+	var (
+		due time.Duration
+		ok  bool
+	)
+	for _, l := range leads {
+		if !now.Before(target.Add(-l)) {
+			due, ok = l, true
+		}
+	}
+	return due, ok
+}
+
+func (s *Scheduler) emit(e Event) {
+	if s.cfg.OnEvent != nil {
+		s.cfg.OnEvent(e)
+	}
+}
@@ -0,0 +1,40 @@
+package season
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnnounceEmitsTightestDueLeadOnce(t *testing.T) {
+	var got []Event
+	s := NewScheduler(nil, SchedulerConfig{
+		AnnounceLeads: []time.Duration{24 * time.Hour, 7 * 24 * time.Hour, time.Hour},
+		OnEvent:       func(e Event) { got = append(got, e) },
+	})
+	target := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	// Nothing due yet.
+	s.announce(1, EventEndAnnounced, target, target.Add(-8*24*time.Hour))
+	if len(got) != 0 {
+		t.Fatalf("events before first lead: got %d, want 0", len(got))
+	}
+
+	// Starting late: the 7-day and 1-day windows have both opened. Only
+	// the 1-day announcement goes out, and 7-day is considered sent.
+	s.announce(1, EventEndAnnounced, target, target.Add(-12*time.Hour))
+	s.announce(1, EventEndAnnounced, target, target.Add(-11*time.Hour))
+	if len(got) != 1 || got[0].Lead != 24*time.Hour {
+		t.Fatalf("late start: got %+v, want one 24h announcement", got)
+	}
+
+	s.announce(1, EventEndAnnounced, target, target.Add(-30*time.Minute))
+	if len(got) != 2 || got[1].Lead != time.Hour {
+		t.Fatalf("final lead: got %+v, want a 1h announcement second", got)
+	}
+
+	// Same season, other kind: tracked separately.
+	s.announce(1, EventStartAnnounced, target, target.Add(-30*time.Minute))
+	if len(got) != 3 || got[2].Kind != EventStartAnnounced {
+		t.Fatalf("start announcement: got %+v", got)
+	}
+}
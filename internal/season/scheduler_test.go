@@ -0,0 +1,153 @@
+package season_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dukerupert/walking-drum/internal/season"
+	"github.com/dukerupert/walking-drum/internal/testdb"
+)
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+// TestSchedulerTimeline walks season 1 (seeded June 1 – September 1,
+// 2026) from three weeks before opening to past its end without
+// waiting on a real clock.
+func TestSchedulerTimeline(t *testing.T) {
+	q, tx := testdb.WithTx(t)
+	ctx := context.Background()
+
+	if _, err := season.SetSeed(ctx, q, 1, 77); err != nil {
+		t.Fatalf("SetSeed: %v", err)
+	}
+
+	start := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	clock := &fakeClock{}
+	var events []season.Event
+	s := season.NewScheduler(tx, season.SchedulerConfig{
+		Clock:   clock,
+		OnEvent: func(e season.Event) { events = append(events, e) },
+	})
+
+	steps := []struct {
+		at   time.Time
+		want []season.EventKind
+	}{
+		{start.Add(-21 * day), nil},
+		{start.Add(-14 * day), []season.EventKind{season.EventStartAnnounced}},
+		{start.Add(-10 * day), nil},
+		{start.Add(-7 * day), []season.EventKind{season.EventStartAnnounced}},
+		{start, []season.EventKind{season.EventActivated}},
+		{start.Add(day), nil},
+		{end.Add(-14 * day), []season.EventKind{season.EventEndAnnounced}},
+		{end.Add(-7 * day), []season.EventKind{season.EventEndAnnounced}},
+		{end, []season.EventKind{season.EventEnded}},
+		{end.Add(day), nil},
+	}
+	for _, step := range steps {
+		clock.now = step.at
+		events = nil
+		if err := s.Tick(ctx); err != nil {
+			t.Fatalf("Tick at %v: %v", step.at, err)
+		}
+		if len(events) != len(step.want) {
+			t.Fatalf("Tick at %v: got events %+v, want kinds %v", step.at, events, step.want)
+		}
+		for i, k := range step.want {
+			if events[i].Kind != k || events[i].SeasonID != 1 {
+				t.Errorf("Tick at %v: event %d = %+v, want %s for season 1", step.at, i, events[i], k)
+			}
+		}
+	}
+
+	got, err := q.GetSeasonByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetSeasonByID: %v", err)
+	}
+	if got.Status != "ended" {
+		t.Errorf("final status: got %q, want ended", got.Status)
+	}
+}
+
+func TestSchedulerSurfacesPreconditionFailures(t *testing.T) {
+	_, tx := testdb.WithTx(t)
+	ctx := context.Background()
+
+	// Season 1 still has the placeholder seed; the scheduler must not
+	// paper over that.
+	clock := &fakeClock{now: time.Date(2026, 6, 2, 0, 0, 0, 0, time.UTC)}
+	s := season.NewScheduler(tx, season.SchedulerConfig{Clock: clock})
+	if err := s.Tick(ctx); err == nil {
+		t.Fatal("Tick activating an unseeded season: got nil error")
+	}
+}
+
+// A season whose whole window passed while it was still upcoming (the
+// scheduler was down) is reported once and left for an operator; with
+// CancelExpired it's cancelled and the season after it opens.
+func TestSchedulerExpiredSeason(t *testing.T) {
+	q, tx := testdb.WithTx(t)
+	ctx := context.Background()
+
+	if _, err := season.SetSeed(ctx, q, 1, 77); err != nil {
+		t.Fatalf("SetSeed: %v", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO seasons (id, name, status, world_seed, starts_at, ends_at)
+		VALUES (2, 'Season 2', 'upcoming', 7, '2026-09-15', '2026-12-01')`); err != nil {
+		t.Fatalf("insert season 2: %v", err)
+	}
+
+	clock := &fakeClock{now: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)}
+	var events []season.Event
+	cfg := season.SchedulerConfig{
+		Clock:   clock,
+		OnEvent: func(e season.Event) { events = append(events, e) },
+	}
+	tick := func(s *season.Scheduler, want ...season.Event) {
+		t.Helper()
+		events = nil
+		if err := s.Tick(ctx); err != nil {
+			t.Fatalf("Tick: %v", err)
+		}
+		if len(events) != len(want) {
+			t.Fatalf("events: got %+v, want %+v", events, want)
+		}
+		for i := range want {
+			if events[i].Kind != want[i].Kind || events[i].SeasonID != want[i].SeasonID || !events[i].At.Equal(want[i].At) {
+				t.Errorf("event %d: got %+v, want %+v", i, events[i], want[i])
+			}
+		}
+	}
+	statuses := func(want map[int32]season.Status) {
+		t.Helper()
+		for id, status := range want {
+			got, err := q.GetSeasonByID(ctx, id)
+			if err != nil {
+				t.Fatalf("GetSeasonByID(%d): %v", id, err)
+			}
+			if got.Status != string(status) {
+				t.Errorf("season %d: got status %q, want %q", id, got.Status, status)
+			}
+		}
+	}
+	season1End := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	s := season.NewScheduler(tx, cfg)
+	tick(s, season.Event{Kind: season.EventExpired, SeasonID: 1, At: season1End})
+	tick(s)
+	statuses(map[int32]season.Status{1: season.StatusUpcoming, 2: season.StatusUpcoming})
+
+	cfg.CancelExpired = true
+	tick(season.NewScheduler(tx, cfg),
+		season.Event{Kind: season.EventCancelled, SeasonID: 1, At: season1End},
+		season.Event{Kind: season.EventActivated, SeasonID: 2, At: time.Date(2026, 9, 15, 0, 0, 0, 0, time.UTC)},
+	)
+	statuses(map[int32]season.Status{1: season.StatusCancelled, 2: season.StatusActive})
+}
@@ -0,0 +1,287 @@
+// Package season owns the season lifecycle from DESIGN.md §3.5: the
+// upcoming → active → ended → wiped state machine (with upcoming →
+// cancelled for a season that never opened), the preconditions
+// each transition must satisfy, and the operations layered on top of
+// it. Transitions run in a transaction holding a row lock on the
+// season, so two operators (or an operator and the scheduler) can't
+// race each other past the checks.
+package season
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+	"github.com/dukerupert/walking-drum/internal/leaderboard"
+	"github.com/dukerupert/walking-drum/internal/worldgen/rng"
+)
+
+// Status mirrors the CHECK constraint on seasons.status. The constants
+// exist so call sites don't sprinkle string literals.
+type Status string
+
+const (
+	StatusUpcoming  Status = "upcoming"
+	StatusActive    Status = "active"
+	StatusEnded     Status = "ended"
+	StatusWiped     Status = "wiped"
+	StatusCancelled Status = "cancelled"
+)
+
+// next is the whole state machine: the legal successors of each status.
+// 'wiped' and 'cancelled' are terminal.
+var next = map[Status][]Status{
+	StatusUpcoming: {StatusActive, StatusCancelled},
+	StatusActive:   {StatusEnded},
+	StatusEnded:    {StatusWiped},
+}
+
+// TxBeginner is satisfied by both *pgxpool.Pool and pgx.Tx — the same
+// shape as game.TxBeginner, redeclared here so the season package
+// doesn't depend on game.
+type TxBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// Sentinel reasons a transition can be refused. They arrive wrapped in
+// a *TransitionError; match with errors.Is.
+var (
+	ErrSeasonNotFound      = errors.New("season: not found")
+	ErrIllegalTransition   = errors.New("season: illegal status transition")
+	ErrSeasonExpired       = errors.New("season: ends_at is not in the future")
+	ErrInvalidWindow       = errors.New("season: ends_at is not after starts_at")
+	ErrSeedNotSet          = errors.New("season: world seed not set")
+	ErrPreviousSeasonOpen  = errors.New("season: previous season has not ended")
+	ErrAnotherSeasonActive = errors.New("season: another season is already active")
+	ErrSeedLocked          = errors.New("season: seed can only change while upcoming")
+)
+
+// TransitionError reports a refused transition along with where the
+// season was and where the caller tried to take it.
+type TransitionError struct {
+	SeasonID int32
+	From, To Status
+	Err      error
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("season %d: %s → %s: %v", e.SeasonID, e.From, e.To, e.Err)
+}
+
+func (e *TransitionError) Unwrap() error { return e.Err }
+
+// Activate moves an upcoming season to active. Requires a non-zero
+// world seed, a sane date window with ends_at still in the future, and
+// the previous season (if any) to be ended, wiped or cancelled.
+func Activate(ctx context.Context, tb TxBeginner, id int32, now time.Time) (sqlc.Season, error) {
+	return Transition(ctx, tb, id, StatusActive, now)
+}
+
+// End closes play on an active season and freezes its leaderboards.
+func End(ctx context.Context, tb TxBeginner, id int32, now time.Time) (sqlc.Season, error) {
+	return Transition(ctx, tb, id, StatusEnded, now)
+}
+
+// Cancel moves an upcoming season to cancelled, for a season that
+// won't be played. It counts as closed, so the season after it can
+// open.
+func Cancel(ctx context.Context, tb TxBeginner, id int32, now time.Time) (sqlc.Season, error) {
+	return Transition(ctx, tb, id, StatusCancelled, now)
+}
+
+// MarkWiped moves an ended season to wiped and stamps wiped_at. It
+// records that the world data is gone; it does not remove it.
+func MarkWiped(ctx context.Context, tb TxBeginner, id int32, now time.Time) (sqlc.Season, error) {
+	return Transition(ctx, tb, id, StatusWiped, now)
+}
+
+// Transition moves season id to status to, validating the move against
+// the state machine and the target status's preconditions. The season
+// row is locked for the duration, and the write happens in the same
+// transaction as the checks.
+func Transition(ctx context.Context, tb TxBeginner, id int32, to Status, now time.Time) (sqlc.Season, error) {
+	tx, err := tb.Begin(ctx)
+	if err != nil {
+		return sqlc.Season{}, fmt.Errorf("begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	s, err := transitionTx(ctx, sqlc.New(tx), id, to, now)
+	if err != nil {
+		return sqlc.Season{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return sqlc.Season{}, fmt.Errorf("commit: %w", err)
+	}
+	return s, nil
+}
+
+// transitionTx is Transition's body against an already-open
+// transaction, so multi-step operations (the wipe) can include a
+// transition in a larger unit of work.
+func transitionTx(ctx context.Context, q *sqlc.Queries, id int32, to Status, now time.Time) (sqlc.Season, error) {
+	cur, err := q.GetSeasonForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.Season{}, &TransitionError{SeasonID: id, To: to, Err: ErrSeasonNotFound}
+		}
+		return sqlc.Season{}, fmt.Errorf("lock season %d: %w", id, err)
+	}
+
+	var prev *sqlc.Season
+	if to == StatusActive {
+		p, err := q.GetPreviousSeason(ctx, id)
+		switch {
+		case err == nil:
+			prev = &p
+		case errors.Is(err, pgx.ErrNoRows):
+			// First season; nothing to wait on.
+		default:
+			return sqlc.Season{}, fmt.Errorf("load previous season: %w", err)
+		}
+	}
+
+	if err := checkTransition(cur, prev, to, now); err != nil {
+		return sqlc.Season{}, err
+	}
+
+	var s sqlc.Season
+	if to == StatusWiped {
+		s, err = q.MarkSeasonWiped(ctx, sqlc.MarkSeasonWipedParams{
+			ID:      id,
+			WipedAt: pgtype.Timestamptz{Time: now, Valid: true},
+		})
+	} else {
+		s, err = q.UpdateSeasonStatus(ctx, sqlc.UpdateSeasonStatusParams{
+			ID:     id,
+			Status: string(to),
+		})
+	}
+	if err != nil {
+		if isOneActiveViolation(err) {
+			return sqlc.Season{}, &TransitionError{SeasonID: id, From: Status(cur.Status), To: to, Err: ErrAnotherSeasonActive}
+		}
+		return sqlc.Season{}, fmt.Errorf("update season %d: %w", id, err)
+	}
+
+	// Play just stopped, so the standings are final: freeze them in the
+	// same transaction that closed the season.
+	if to == StatusEnded {
+		if _, err := leaderboard.Snapshot(ctx, q, id); err != nil {
+			return sqlc.Season{}, err
+		}
+	}
+	return s, nil
+}
+
+// checkTransition is the pure half of Transition: given the locked
+// season row, its predecessor (nil for season 1), and the wall clock,
+// it decides whether the move is allowed.
+func checkTransition(cur sqlc.Season, prev *sqlc.Season, to Status, now time.Time) error {
+	from := Status(cur.Status)
+	refuse := func(err error) error {
+		return &TransitionError{SeasonID: cur.ID, From: from, To: to, Err: err}
+	}
+
+	if !slices.Contains(next[from], to) {
+		return refuse(ErrIllegalTransition)
+	}
+
+	if to == StatusActive {
+		if cur.WorldSeed == 0 {
+			return refuse(ErrSeedNotSet)
+		}
+		if !cur.EndsAt.Time.After(cur.StartsAt.Time) {
+			return refuse(ErrInvalidWindow)
+		}
+		if !cur.EndsAt.Time.After(now) {
+			return refuse(ErrSeasonExpired)
+		}
+		if prev != nil {
+			switch Status(prev.Status) {
+			case StatusEnded, StatusWiped, StatusCancelled:
+			default:
+				return refuse(ErrPreviousSeasonOpen)
+			}
+		}
+	}
+	return nil
+}
+
+// isOneActiveViolation reports whether err is the seasons_one_active
+// unique index firing — i.e. some other season got activated between
+// our checks and our write.
+func isOneActiveViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "seasons_one_active"
+}
+
+// SetSeed sets the world seed on an upcoming season. Zero is reserved
+// for "not set yet" and rejected. A missing season and one that is past
+// upcoming both report ErrSeedLocked — the UPDATE can't tell them apart.
+func SetSeed(ctx context.Context, q *sqlc.Queries, id int32, seed int64) (sqlc.Season, error) {
+	if seed == 0 {
+		return sqlc.Season{}, fmt.Errorf("season %d: %w", id, ErrSeedNotSet)
+	}
+	s, err := q.UpdateSeasonSeed(ctx, sqlc.UpdateSeasonSeedParams{ID: id, WorldSeed: seed})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.Season{}, fmt.Errorf("season %d: %w", id, ErrSeedLocked)
+		}
+		return sqlc.Season{}, fmt.Errorf("set seed: %w", err)
+	}
+	return s, nil
+}
+
+// NewSeason describes a season to create. It is JSON-tagged because the
+// wipe stores the requested next season alongside its progress record.
+type NewSeason struct {
+	ID        int32           `json:"id"`
+	Name      string          `json:"name,omitempty"`
+	WorldSeed int64           `json:"world_seed"` // 0: Create picks one
+	Modifiers SeasonModifiers `json:"modifiers"`
+	StartsAt  time.Time       `json:"starts_at"`
+	EndsAt    time.Time       `json:"ends_at"`
+}
+
+// Create inserts an upcoming season after validating its modifiers. A
+// zero WorldSeed asks for a fresh random one from rng.NewWorldSeed; pass
+// an explicit seed to reproduce a known world.
+func Create(ctx context.Context, q *sqlc.Queries, in NewSeason) (sqlc.Season, error) {
+	if in.ID <= 0 {
+		return sqlc.Season{}, fmt.Errorf("create season: invalid id %d", in.ID)
+	}
+	if !in.EndsAt.After(in.StartsAt) {
+		return sqlc.Season{}, fmt.Errorf("create season %d: %w", in.ID, ErrInvalidWindow)
+	}
+	if in.WorldSeed == 0 {
+		in.WorldSeed = rng.NewWorldSeed()
+	}
+	var name *string
+	if in.Name != "" {
+		name = &in.Name
+	}
+	mods, err := in.Modifiers.Encode()
+	if err != nil {
+		return sqlc.Season{}, fmt.Errorf("create season %d: %w", in.ID, err)
+	}
+	s, err := q.CreateSeason(ctx, sqlc.CreateSeasonParams{
+		ID:        in.ID,
+		Name:      name,
+		WorldSeed: in.WorldSeed,
+		Modifiers: mods,
+		StartsAt:  pgtype.Timestamptz{Time: in.StartsAt, Valid: true},
+		EndsAt:    pgtype.Timestamptz{Time: in.EndsAt, Valid: true},
+	})
+	if err != nil {
+		return sqlc.Season{}, fmt.Errorf("create season %d: %w", in.ID, err)
+	}
+	return s, nil
+}
@@ -0,0 +1,84 @@
+package season
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+)
+
+func ts(t time.Time) pgtype.Timestamptz { return pgtype.Timestamptz{Time: t, Valid: true} }
+
+func TestCheckTransition(t *testing.T) {
+	now := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	ready := sqlc.Season{
+		ID:        2,
+		Status:    string(StatusUpcoming),
+		WorldSeed: 42,
+		StartsAt:  ts(now.Add(-time.Hour)),
+		EndsAt:    ts(now.Add(90 * 24 * time.Hour)),
+	}
+	ended := sqlc.Season{ID: 1, Status: string(StatusEnded)}
+	active := sqlc.Season{ID: 1, Status: string(StatusActive)}
+	cancelled := sqlc.Season{ID: 1, Status: string(StatusCancelled)}
+
+	with := func(f func(*sqlc.Season)) sqlc.Season {
+		s := ready
+		f(&s)
+		return s
+	}
+
+	cases := []struct {
+		name string
+		cur  sqlc.Season
+		prev *sqlc.Season
+		to   Status
+		want error
+	}{
+		{"activate first season", ready, nil, StatusActive, nil},
+		{"activate after ended predecessor", ready, &ended, StatusActive, nil},
+		{"activate after cancelled predecessor", ready, &cancelled, StatusActive, nil},
+		{"activate while predecessor active", ready, &active, StatusActive, ErrPreviousSeasonOpen},
+		{"activate without seed", with(func(s *sqlc.Season) { s.WorldSeed = 0 }), nil, StatusActive, ErrSeedNotSet},
+		{"activate after ends_at", with(func(s *sqlc.Season) {
+			s.StartsAt = ts(now.Add(-48 * time.Hour))
+			s.EndsAt = ts(now.Add(-time.Hour))
+		}), nil, StatusActive, ErrSeasonExpired},
+		{"activate inverted window", with(func(s *sqlc.Season) {
+			s.EndsAt = s.StartsAt
+		}), nil, StatusActive, ErrInvalidWindow},
+		{"skip straight to ended", ready, nil, StatusEnded, ErrIllegalTransition},
+		{"end active", with(func(s *sqlc.Season) { s.Status = "active" }), nil, StatusEnded, nil},
+		{"reopen ended", with(func(s *sqlc.Season) { s.Status = "ended" }), nil, StatusActive, ErrIllegalTransition},
+		{"wipe ended", with(func(s *sqlc.Season) { s.Status = "ended" }), nil, StatusWiped, nil},
+		{"wipe active", with(func(s *sqlc.Season) { s.Status = "active" }), nil, StatusWiped, ErrIllegalTransition},
+		{"cancel upcoming", ready, nil, StatusCancelled, nil},
+		{"cancel active", with(func(s *sqlc.Season) { s.Status = "active" }), nil, StatusCancelled, ErrIllegalTransition},
+		{"anything after cancelled", with(func(s *sqlc.Season) { s.Status = "cancelled" }), nil, StatusActive, ErrIllegalTransition},
+		{"anything after wiped", with(func(s *sqlc.Season) { s.Status = "wiped" }), nil, StatusActive, ErrIllegalTransition},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkTransition(tc.cur, tc.prev, tc.to, now)
+			if tc.want == nil {
+				if err != nil {
+					t.Fatalf("got %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+			var te *TransitionError
+			if !errors.As(err, &te) {
+				t.Fatalf("error %T is not a *TransitionError", err)
+			}
+			if te.To != tc.to || te.From != Status(tc.cur.Status) {
+				t.Errorf("TransitionError from/to: got %s→%s, want %s→%s", te.From, te.To, tc.cur.Status, tc.to)
+			}
+		})
+	}
+}
@@ -0,0 +1,358 @@
+package season
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+	"github.com/dukerupert/walking-drum/internal/leaderboard"
+)
+
+// WipeStep is the last completed step of a wipe, as recorded in
+// season_wipes.step. Steps run in declaration order.
+type WipeStep string
+
+const (
+	WipeStarted     WipeStep = "started"     // progress row exists
+	WipeClosed      WipeStep = "closed"      // season is ended; no more play
+	WipeSnapshotted WipeStep = "snapshotted" // final_summary and leaderboards written
+	WipePurged      WipeStep = "purged"      // season-scoped rows deleted
+	WipeStamped     WipeStep = "stamped"     // season is wiped, wiped_at set
+	WipeDone        WipeStep = "done"        // next season created
+)
+
+var wipeSteps = []WipeStep{WipeStarted, WipeClosed, WipeSnapshotted, WipePurged, WipeStamped, WipeDone}
+
+var (
+	ErrWipeNotAllowed    = errors.New("season: only an active or ended season can be wiped")
+	ErrNextSeasonExists  = errors.New("season: next season already exists")
+	ErrNextSeasonInvalid = errors.New("season: next season must come after the wiped one")
+)
+
+// DefaultWipeBatchSize bounds how many entities one purge transaction
+// deletes. Cascades multiply it by each entity's dependent rows.
+const DefaultWipeBatchSize = 5000
+
+// WipeOptions configures a Wipe.
+type WipeOptions struct {
+	// SeasonID is the season to wipe. It must be active or ended, or
+	// have a wipe already in progress.
+	SeasonID int32
+
+	// Next is the season to create once the world is clear. Ignored when
+	// resuming: the plan recorded at the start of the wipe wins.
+	Next NewSeason
+
+	// DryRun reports what the wipe would do without writing anything.
+	DryRun bool
+
+	// BatchSize caps entities deleted per purge transaction. Defaults
+	// to DefaultWipeBatchSize.
+	BatchSize int32
+
+	// Clock stamps the close and wiped_at. Defaults to SystemClock.
+	Clock Clock
+}
+
+// WipeReport describes one Wipe call. In a dry run, Steps are the steps
+// that would run and RowCounts are exactly the rows that would go; in a
+// live run, they're what this call did and what was there when it
+// started purging.
+type WipeReport struct {
+	SeasonID     int32
+	NextSeasonID int32
+	DryRun       bool
+
+	// ResumedFrom is the step recorded before this call, or "" if this
+	// call started the wipe.
+	ResumedFrom WipeStep
+	Steps       []WipeStep
+
+	// RowCounts is keyed by table name: every season-scoped row the
+	// purge deletes, plus the season_participation rows the snapshot
+	// writes final_summary into.
+	RowCounts map[string]int64
+
+	// RowsDeleted counts entities deleted by this call. Cascaded rows
+	// are covered by RowCounts.
+	RowsDeleted int64
+}
+
+// wipeCounts lists the per-table counts a wipe reports. Anything that
+// hangs off entities with ON DELETE CASCADE belongs here too, so the
+// dry run stays exact.
+var wipeCounts = []struct {
+	table string
+	count func(*sqlc.Queries, context.Context, int32) (int64, error)
+}{
+	{"entities", (*sqlc.Queries).CountSeasonEntities},
+	{"entity_positions", (*sqlc.Queries).CountSeasonEntityPositions},
+	{"components", (*sqlc.Queries).CountSeasonComponents},
+	{"characters", (*sqlc.Queries).CountSeasonCharacters},
+	{"actors", (*sqlc.Queries).CountSeasonActors},
+	{"season_participation", (*sqlc.Queries).CountSeasonParticipation},
+}
+
+// Wipe runs the end-of-season process from DESIGN.md §3.5: close play,
+// snapshot each participant's stats into final_summary, delete every
+// season-scoped row, stamp the season wiped, and create the next
+// season.
+//
+// Each step commits together with its progress marker in season_wipes,
+// and the purge commits per batch, so a wipe that crashes at any point
+// picks up where it stopped when Wipe is called again for the same
+// season. Concurrent calls serialize on the progress row.
+func Wipe(ctx context.Context, tb TxBeginner, opts WipeOptions) (WipeReport, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultWipeBatchSize
+	}
+	if opts.Clock == nil {
+		opts.Clock = SystemClock{}
+	}
+	rep := WipeReport{SeasonID: opts.SeasonID, DryRun: opts.DryRun}
+
+	from, next, err := prepareWipe(ctx, tb, opts, &rep)
+	if err != nil {
+		return rep, err
+	}
+	rep.ResumedFrom = from
+	rep.NextSeasonID = next.ID
+
+	if opts.DryRun {
+		rep.Steps = stepsAfter(from)
+		return rep, nil
+	}
+	if from == "" {
+		rep.Steps = append(rep.Steps, WipeStarted)
+	}
+	for {
+		step, done, err := wipeStep(ctx, tb, opts, &rep)
+		if err != nil {
+			return rep, err
+		}
+		if step != "" {
+			rep.Steps = append(rep.Steps, step)
+		}
+		if done {
+			return rep, nil
+		}
+	}
+}
+
+// prepareWipe validates the request, counts rows, and — on a live fresh
+// run — records the progress row. It returns the previously recorded
+// step ("" if none) and the next season the wipe will create.
+func prepareWipe(ctx context.Context, tb TxBeginner, opts WipeOptions, rep *WipeReport) (WipeStep, NewSeason, error) {
+	tx, err := tb.Begin(ctx)
+	if err != nil {
+		return "", NewSeason{}, fmt.Errorf("wipe: begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	q := sqlc.New(tx)
+
+	var (
+		from WipeStep
+		next NewSeason
+	)
+	w, err := q.GetSeasonWipe(ctx, opts.SeasonID)
+	switch {
+	case err == nil:
+		from = WipeStep(w.Step)
+		if err := json.Unmarshal(w.NextSeason, &next); err != nil {
+			return "", NewSeason{}, fmt.Errorf("wipe %d: decode recorded next season: %w", opts.SeasonID, err)
+		}
+	case errors.Is(err, pgx.ErrNoRows):
+		next = opts.Next
+		if err := checkWipeStart(ctx, q, opts.SeasonID, next); err != nil {
+			return "", NewSeason{}, err
+		}
+	default:
+		return "", NewSeason{}, fmt.Errorf("wipe %d: load progress: %w", opts.SeasonID, err)
+	}
+
+	rep.RowCounts = make(map[string]int64, len(wipeCounts))
+	for _, c := range wipeCounts {
+		n, err := c.count(q, ctx, opts.SeasonID)
+		if err != nil {
+			return "", NewSeason{}, fmt.Errorf("wipe %d: count %s: %w", opts.SeasonID, c.table, err)
+		}
+		rep.RowCounts[c.table] = n
+	}
+
+	if opts.DryRun || from != "" {
+		return from, next, nil
+	}
+
+	plan, err := json.Marshal(next)
+	if err != nil {
+		return "", NewSeason{}, fmt.Errorf("wipe %d: encode next season: %w", opts.SeasonID, err)
+	}
+	if _, err := q.CreateSeasonWipe(ctx, sqlc.CreateSeasonWipeParams{
+		SeasonID:   opts.SeasonID,
+		NextSeason: plan,
+	}); err != nil {
+		return "", NewSeason{}, fmt.Errorf("wipe %d: record start: %w", opts.SeasonID, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return "", NewSeason{}, fmt.Errorf("wipe %d: commit start: %w", opts.SeasonID, err)
+	}
+	return "", next, nil
+}
+
+// checkWipeStart validates a fresh wipe: the season must be wipeable and
+// the next season must be new and numbered after it.
+func checkWipeStart(ctx context.Context, q *sqlc.Queries, id int32, next NewSeason) error {
+	s, err := q.GetSeasonByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("wipe %d: %w", id, ErrSeasonNotFound)
+		}
+		return fmt.Errorf("wipe %d: load season: %w", id, err)
+	}
+	switch Status(s.Status) {
+	case StatusActive, StatusEnded:
+	default:
+		return fmt.Errorf("wipe %d (status %s): %w", id, s.Status, ErrWipeNotAllowed)
+	}
+	if next.ID <= id {
+		return fmt.Errorf("wipe %d: next season %d: %w", id, next.ID, ErrNextSeasonInvalid)
+	}
+	if !next.EndsAt.After(next.StartsAt) {
+		return fmt.Errorf("wipe %d: next season %d: %w", id, next.ID, ErrInvalidWindow)
+	}
+	// Create validates again at the end; checking here means a bad plan
+	// fails before anything is deleted rather than after.
+	if err := next.Modifiers.Validate(); err != nil {
+		return fmt.Errorf("wipe %d: next season %d: %w", id, next.ID, err)
+	}
+	if _, err := q.GetSeasonByID(ctx, next.ID); err == nil {
+		return fmt.Errorf("wipe %d: season %d: %w", id, next.ID, ErrNextSeasonExists)
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("wipe %d: load next season: %w", id, err)
+	}
+	return nil
+}
+
+// wipeStep runs one transaction's worth of wipe: it locks the progress
+// row, performs whatever follows the recorded step, and advances the
+// marker. It returns the step it completed ("" for a purge batch that
+// left rows behind) and whether the wipe is finished.
+func wipeStep(ctx context.Context, tb TxBeginner, opts WipeOptions, rep *WipeReport) (WipeStep, bool, error) {
+	id := opts.SeasonID
+	tx, err := tb.Begin(ctx)
+	if err != nil {
+		return "", false, fmt.Errorf("wipe %d: begin: %w", id, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	q := sqlc.New(tx)
+
+	w, err := q.GetSeasonWipeForUpdate(ctx, id)
+	if err != nil {
+		return "", false, fmt.Errorf("wipe %d: lock progress: %w", id, err)
+	}
+
+	var completed WipeStep
+	switch WipeStep(w.Step) {
+	case WipeStarted:
+		s, err := q.GetSeasonByID(ctx, id)
+		if err != nil {
+			return "", false, fmt.Errorf("wipe %d: load season: %w", id, err)
+		}
+		if Status(s.Status) == StatusActive {
+			if _, err := transitionTx(ctx, q, id, StatusEnded, opts.Clock.Now()); err != nil {
+				return "", false, fmt.Errorf("wipe %d: close: %w", id, err)
+			}
+		}
+		completed = WipeClosed
+
+	case WipeClosed:
+		if _, err := q.SnapshotSeasonParticipation(ctx, id); err != nil {
+			return "", false, fmt.Errorf("wipe %d: snapshot: %w", id, err)
+		}
+		// End already froze the boards; this covers seasons that ended
+		// before leaderboards existed. Existing snapshot rows are kept.
+		if _, err := leaderboard.Snapshot(ctx, q, id); err != nil {
+			return "", false, fmt.Errorf("wipe %d: %w", id, err)
+		}
+		completed = WipeSnapshotted
+
+	case WipeSnapshotted:
+		n, err := q.DeleteSeasonEntitiesBatch(ctx, sqlc.DeleteSeasonEntitiesBatchParams{
+			SeasonID: id,
+			Limit:    opts.BatchSize,
+		})
+		if err != nil {
+			return "", false, fmt.Errorf("wipe %d: purge: %w", id, err)
+		}
+		if n > 0 {
+			if err := q.AddSeasonWipeRowsDeleted(ctx, sqlc.AddSeasonWipeRowsDeletedParams{
+				SeasonID:    id,
+				RowsDeleted: n,
+			}); err != nil {
+				return "", false, fmt.Errorf("wipe %d: record purge: %w", id, err)
+			}
+			if err := tx.Commit(ctx); err != nil {
+				return "", false, fmt.Errorf("wipe %d: commit purge batch: %w", id, err)
+			}
+			rep.RowsDeleted += n
+			return "", false, nil
+		}
+		completed = WipePurged
+
+	case WipePurged:
+		if _, err := transitionTx(ctx, q, id, StatusWiped, opts.Clock.Now()); err != nil {
+			return "", false, fmt.Errorf("wipe %d: stamp: %w", id, err)
+		}
+		completed = WipeStamped
+
+	case WipeStamped:
+		var next NewSeason
+		if err := json.Unmarshal(w.NextSeason, &next); err != nil {
+			return "", false, fmt.Errorf("wipe %d: decode next season: %w", id, err)
+		}
+		if _, err := Create(ctx, q, next); err != nil {
+			return "", false, fmt.Errorf("wipe %d: %w", id, err)
+		}
+		if err := q.CompleteSeasonWipe(ctx, id); err != nil {
+			return "", false, fmt.Errorf("wipe %d: record completion: %w", id, err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return "", false, fmt.Errorf("wipe %d: commit: %w", id, err)
+		}
+		return WipeDone, true, nil
+
+	case WipeDone:
+		return "", true, nil
+
+	default:
+		return "", false, fmt.Errorf("wipe %d: unknown step %q", id, w.Step)
+	}
+
+	if err := q.AdvanceSeasonWipe(ctx, sqlc.AdvanceSeasonWipeParams{
+		SeasonID: id,
+		Step:     string(completed),
+	}); err != nil {
+		return "", false, fmt.Errorf("wipe %d: record %s: %w", id, completed, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return "", false, fmt.Errorf("wipe %d: commit %s: %w", id, completed, err)
+	}
+	return completed, false, nil
+}
+
+// stepsAfter lists the steps still to run after from ("" = not begun).
+func stepsAfter(from WipeStep) []WipeStep {
+	if from == "" {
+		return append([]WipeStep(nil), wipeSteps...)
+	}
+	for i, s := range wipeSteps {
+		if s == from {
+			return append([]WipeStep(nil), wipeSteps[i+1:]...)
+		}
+	}
+	return nil
+}
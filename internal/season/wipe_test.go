@@ -0,0 +1,251 @@
+package season_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/dukerupert/walking-drum/internal/db/sqlc"
+	"github.com/dukerupert/walking-drum/internal/game"
+	"github.com/dukerupert/walking-drum/internal/leaderboard"
+	"github.com/dukerupert/walking-drum/internal/season"
+	"github.com/dukerupert/walking-drum/internal/testdb"
+)
+
+var season2 = season.NewSeason{
+	ID:        2,
+	Name:      "Season 2",
+	WorldSeed: 2,
+	StartsAt:  time.Date(2026, 9, 15, 0, 0, 0, 0, time.UTC),
+	EndsAt:    time.Date(2026, 12, 15, 0, 0, 0, 0, time.UTC),
+}
+
+// populateSeason1 activates season 1 and fills it with three entities
+// (two positioned, one with a component, one a character with an
+// actor) and one participant.
+func populateSeason1(t *testing.T, ctx context.Context, q *sqlc.Queries, tx pgx.Tx) pgtype.UUID {
+	t.Helper()
+	if _, err := season.SetSeed(ctx, q, 1, 1); err != nil {
+		t.Fatalf("SetSeed: %v", err)
+	}
+	if _, err := season.Activate(ctx, tx, 1, inSeason1); err != nil {
+		t.Fatalf("Activate: %v", err)
+	}
+	var ids []uuid.UUID
+	for _, in := range []game.CreateEntityInput{
+		{SeasonID: 1, Type: game.EntityCharacter, Tick: 1, Position: &game.PositionSpec{RegionID: 1}},
+		{SeasonID: 1, Type: game.EntityNPC, Tick: 1, Position: &game.PositionSpec{RegionID: 2},
+			InitialComponents: []game.Component{game.Hidden{}}},
+		{SeasonID: 1, Type: game.EntityItem, Tick: 1},
+	} {
+		id, err := game.CreateEntity(ctx, tx, in)
+		if err != nil {
+			t.Fatalf("CreateEntity: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	acc := makeAccount(t, ctx, q, "wipe@example.com")
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO season_participation (account_id, season_id, characters_made, deaths, deepest_region)
+		VALUES ($1, 1, 3, 2, 9)
+	`, acc); err != nil {
+		t.Fatalf("insert participation: %v", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO characters (entity_id, account_id, season_id, character_name, hp, hp_max, created_at_tick)
+		VALUES ($1, $2, 1, 'Wiped', 10, 10, 1)
+	`, ids[0], acc); err != nil {
+		t.Fatalf("insert character: %v", err)
+	}
+	if _, err := q.CreateActor(ctx, sqlc.CreateActorParams{
+		EntityID: pgtype.UUID{Bytes: ids[0], Valid: true}, EnergyRegen: 10, EnergyCap: 100, UpdatedAtTick: 1,
+	}); err != nil {
+		t.Fatalf("CreateActor: %v", err)
+	}
+	return acc
+}
+
+func TestWipeDryRunChangesNothing(t *testing.T) {
+	q, tx := testdb.WithTx(t)
+	ctx := context.Background()
+	populateSeason1(t, ctx, q, tx)
+
+	rep, err := season.Wipe(ctx, tx, season.WipeOptions{SeasonID: 1, Next: season2, DryRun: true})
+	if err != nil {
+		t.Fatalf("Wipe (dry run): %v", err)
+	}
+	want := map[string]int64{
+		"entities":             3,
+		"entity_positions":     2,
+		"components":           1,
+		"characters":           1,
+		"actors":               1,
+		"season_participation": 1,
+	}
+	for table, n := range want {
+		if rep.RowCounts[table] != n {
+			t.Errorf("RowCounts[%s]: got %d, want %d", table, rep.RowCounts[table], n)
+		}
+	}
+	if len(rep.Steps) == 0 || rep.Steps[len(rep.Steps)-1] != season.WipeDone {
+		t.Errorf("dry-run steps: got %v, want a plan ending in done", rep.Steps)
+	}
+
+	s, err := q.GetSeasonByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetSeasonByID: %v", err)
+	}
+	if s.Status != "active" {
+		t.Errorf("season 1 after dry run: got %q, want active", s.Status)
+	}
+	if n, _ := q.CountSeasonEntities(ctx, 1); n != 3 {
+		t.Errorf("entities after dry run: got %d, want 3", n)
+	}
+	if _, err := q.GetSeasonWipe(ctx, 1); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("dry run recorded progress: got %v, want pgx.ErrNoRows", err)
+	}
+	if _, err := q.GetSeasonByID(ctx, 2); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("dry run created season 2: got %v, want pgx.ErrNoRows", err)
+	}
+}
+
+func TestWipeEndToEnd(t *testing.T) {
+	q, tx := testdb.WithTx(t)
+	ctx := context.Background()
+	acc := populateSeason1(t, ctx, q, tx)
+
+	rep, err := season.Wipe(ctx, tx, season.WipeOptions{
+		SeasonID:  1,
+		Next:      season2,
+		BatchSize: 2, // force more than one purge batch
+		Clock:     &fakeClock{now: inSeason1},
+	})
+	if err != nil {
+		t.Fatalf("Wipe: %v", err)
+	}
+	if rep.RowsDeleted != 3 {
+		t.Errorf("RowsDeleted: got %d, want 3", rep.RowsDeleted)
+	}
+	wantSteps := []season.WipeStep{
+		season.WipeStarted, season.WipeClosed, season.WipeSnapshotted,
+		season.WipePurged, season.WipeStamped, season.WipeDone,
+	}
+	if !slices.Equal(rep.Steps, wantSteps) {
+		t.Errorf("Steps: got %v, want %v", rep.Steps, wantSteps)
+	}
+
+	s1, err := q.GetSeasonByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetSeasonByID(1): %v", err)
+	}
+	if s1.Status != "wiped" || !s1.WipedAt.Valid {
+		t.Errorf("season 1: status %q wiped_at %v, want wiped with a timestamp", s1.Status, s1.WipedAt)
+	}
+	if n, _ := q.CountSeasonEntities(ctx, 1); n != 0 {
+		t.Errorf("season 1 entities after wipe: got %d, want 0", n)
+	}
+
+	var summary []byte
+	if err := tx.QueryRow(ctx, `
+		SELECT final_summary FROM season_participation WHERE account_id = $1 AND season_id = 1
+	`, acc).Scan(&summary); err != nil {
+		t.Fatalf("read final_summary: %v", err)
+	}
+	var got struct {
+		CharactersMade int `json:"characters_made"`
+		Deaths         int `json:"deaths"`
+		DeepestRegion  int `json:"deepest_region"`
+	}
+	if err := json.Unmarshal(summary, &got); err != nil {
+		t.Fatalf("decode final_summary %s: %v", summary, err)
+	}
+	if got.CharactersMade != 3 || got.Deaths != 2 || got.DeepestRegion != 9 {
+		t.Errorf("final_summary: got %+v, want 3/2/9", got)
+	}
+
+	board, err := leaderboard.Standings(ctx, q, 1, leaderboard.BoardDeepestRegion, 10, 0)
+	if err != nil {
+		t.Fatalf("Standings: %v", err)
+	}
+	if !board.Frozen || len(board.Entries) != 1 || board.Entries[0].Score != 9 {
+		t.Errorf("season 1 deepest-region board after wipe: got %+v, want one frozen entry scoring 9", board)
+	}
+
+	s2, err := q.GetSeasonByID(ctx, 2)
+	if err != nil {
+		t.Fatalf("GetSeasonByID(2): %v", err)
+	}
+	if s2.Status != "upcoming" || s2.WorldSeed != 2 {
+		t.Errorf("season 2: status %q seed %d, want upcoming seed 2", s2.Status, s2.WorldSeed)
+	}
+
+	// A repeat call is a no-op on a finished wipe.
+	again, err := season.Wipe(ctx, tx, season.WipeOptions{SeasonID: 1})
+	if err != nil {
+		t.Fatalf("Wipe (repeat): %v", err)
+	}
+	if again.ResumedFrom != season.WipeDone || len(again.Steps) != 0 {
+		t.Errorf("repeat wipe: resumed from %q with steps %v, want done and none", again.ResumedFrom, again.Steps)
+	}
+}
+
+func TestWipeResumesAfterCrash(t *testing.T) {
+	q, tx := testdb.WithTx(t)
+	ctx := context.Background()
+	populateSeason1(t, ctx, q, tx)
+
+	// Simulate a crash partway through the purge: the season was closed
+	// and snapshotted, and one entity already went.
+	if _, err := season.End(ctx, tx, 1, inSeason1); err != nil {
+		t.Fatalf("End: %v", err)
+	}
+	plan, _ := json.Marshal(season2)
+	if _, err := q.CreateSeasonWipe(ctx, sqlc.CreateSeasonWipeParams{SeasonID: 1, NextSeason: plan}); err != nil {
+		t.Fatalf("CreateSeasonWipe: %v", err)
+	}
+	if err := q.AdvanceSeasonWipe(ctx, sqlc.AdvanceSeasonWipeParams{SeasonID: 1, Step: "snapshotted"}); err != nil {
+		t.Fatalf("AdvanceSeasonWipe: %v", err)
+	}
+	if _, err := q.DeleteSeasonEntitiesBatch(ctx, sqlc.DeleteSeasonEntitiesBatchParams{SeasonID: 1, Limit: 1}); err != nil {
+		t.Fatalf("DeleteSeasonEntitiesBatch: %v", err)
+	}
+
+	// The resuming caller passes a different next season; the recorded
+	// plan must win.
+	rep, err := season.Wipe(ctx, tx, season.WipeOptions{
+		SeasonID: 1,
+		Next:     season.NewSeason{ID: 9, StartsAt: inSeason1, EndsAt: inSeason1.Add(time.Hour)},
+		Clock:    &fakeClock{now: inSeason1},
+	})
+	if err != nil {
+		t.Fatalf("Wipe (resume): %v", err)
+	}
+	if rep.ResumedFrom != season.WipeSnapshotted {
+		t.Errorf("ResumedFrom: got %q, want snapshotted", rep.ResumedFrom)
+	}
+	if rep.NextSeasonID != 2 {
+		t.Errorf("NextSeasonID: got %d, want 2 (recorded plan)", rep.NextSeasonID)
+	}
+	if rep.RowsDeleted != 2 {
+		t.Errorf("RowsDeleted on resume: got %d, want 2", rep.RowsDeleted)
+	}
+	if _, err := q.GetSeasonByID(ctx, 9); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("season 9 should not exist: got %v", err)
+	}
+}
+
+func TestWipeRefusesUpcomingSeason(t *testing.T) {
+	_, tx := testdb.WithTx(t)
+	_, err := season.Wipe(context.Background(), tx, season.WipeOptions{SeasonID: 1, Next: season2, DryRun: true})
+	if !errors.Is(err, season.ErrWipeNotAllowed) {
+		t.Fatalf("Wipe(upcoming season): got %v, want ErrWipeNotAllowed", err)
+	}
+}
@@ -73,7 +73,7 @@ func setup() (*pgxpool.Pool, error) {
 // WithTx returns a fresh transaction and an sqlc.Queries bound to it.
 // The transaction is rolled back automatically when the test ends, so
 // tests are isolated and leave no rows behind.
-func WithTx(t *testing.T) (*sqlc.Queries, pgx.Tx) {
+func WithTx(t testing.TB) (*sqlc.Queries, pgx.Tx) {
 	t.Helper()
 	p, err := setup()
 	if err != nil {
@@ -0,0 +1,14 @@
+-- +goose Up
+
+-- Adds the terminal 'wiped' status to the season lifecycle. See
+-- DESIGN.md §3.5: a season closes ('ended'), then its world data is
+-- cleared ('wiped'). wiped_at is stamped in the same UPDATE that sets
+-- the status, so the two can't drift.
+ALTER TABLE seasons DROP CONSTRAINT seasons_status_check;
+ALTER TABLE seasons ADD CONSTRAINT seasons_status_check
+  CHECK (status IN ('upcoming', 'active', 'ended', 'wiped'));
+
+-- +goose Down
+ALTER TABLE seasons DROP CONSTRAINT seasons_status_check;
+ALTER TABLE seasons ADD CONSTRAINT seasons_status_check
+  CHECK (status IN ('upcoming', 'active', 'ended'));
@@ -0,0 +1,24 @@
+-- +goose Up
+
+-- Progress record for the end-of-season wipe (DESIGN.md §3.5). One row
+-- per wiped season. `step` is the last step that completed; the wipe
+-- orchestrator resumes from here after a crash, so every step commits
+-- together with its own advance of this column.
+CREATE TABLE season_wipes (
+  season_id       INT PRIMARY KEY REFERENCES seasons(id),
+  step            TEXT NOT NULL
+                    CHECK (step IN (
+                      'started', 'closed', 'snapshotted', 'purged', 'stamped', 'done'
+                    )),
+  -- The next season's parameters as requested when the wipe started.
+  -- Stored so a resumed wipe creates the season the operator asked
+  -- for, not whatever the resuming caller happened to pass.
+  next_season     JSONB NOT NULL,
+  rows_deleted    BIGINT NOT NULL DEFAULT 0,
+  started_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  completed_at    TIMESTAMPTZ
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS season_wipes;
@@ -0,0 +1,55 @@
+-- +goose Up
+
+-- Per-season stats feeding the leaderboards that aren't already on
+-- season_participation. Both are maintained by game code as it happens,
+-- like deepest_region: longest_life_ticks is the longest any of the
+-- account's characters survived, loot_recovered a running total.
+ALTER TABLE season_participation
+  ADD COLUMN longest_life_ticks BIGINT,
+  ADD COLUMN loot_recovered     BIGINT NOT NULL DEFAULT 0;
+
+-- One row per (season, board, participant) with a score. The boards are
+-- a closed set (DESIGN.md §3.5); adding one is a new branch here
+-- and a new constant in internal/leaderboard. A participant with no
+-- score on a board (never left region 0, never recovered anything)
+-- doesn't appear on it.
+CREATE VIEW leaderboard_scores AS
+SELECT season_id, 'deepest_region' AS board, account_id, deepest_region::BIGINT AS score
+FROM season_participation WHERE deepest_region IS NOT NULL
+UNION ALL
+SELECT season_id, 'longest_life', account_id, longest_life_ticks
+FROM season_participation WHERE longest_life_ticks IS NOT NULL
+UNION ALL
+SELECT season_id, 'most_loot', account_id, loot_recovered
+FROM season_participation WHERE loot_recovered > 0;
+
+-- Frozen standings, written when a season ends. Survives the wipe:
+-- this is what "leaderboards and hall of fame persist across seasons"
+-- means in practice. display_name is copied so the record shows the
+-- name the player had when they placed.
+CREATE TABLE leaderboard_snapshots (
+  season_id       INT NOT NULL REFERENCES seasons(id),
+  board           TEXT NOT NULL
+                    CHECK (board IN ('deepest_region', 'longest_life', 'most_loot')),
+  account_id      UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+  rank            INT NOT NULL,
+  score           BIGINT NOT NULL,
+  display_name    TEXT NOT NULL,
+  taken_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (season_id, board, account_id)
+);
+
+-- Paging a frozen board in rank order.
+CREATE INDEX leaderboard_snapshots_rank_idx
+  ON leaderboard_snapshots (season_id, board, rank);
+
+-- "This player's placements across all seasons" for profile pages.
+CREATE INDEX leaderboard_snapshots_account_idx
+  ON leaderboard_snapshots (account_id, season_id DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS leaderboard_snapshots;
+DROP VIEW IF EXISTS leaderboard_scores;
+ALTER TABLE season_participation
+  DROP COLUMN IF EXISTS loot_recovered,
+  DROP COLUMN IF EXISTS longest_life_ticks;
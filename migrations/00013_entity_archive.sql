@@ -0,0 +1,61 @@
+-- +goose Up
+
+-- Last known state of every entity the sweep hard-deleted, so
+-- moderators can still inspect a destroyed item and an admin can roll
+-- one back. snapshot holds the entities row, its position (or null),
+-- and its component rows, exactly as they were when swept:
+--
+--   {"entity": {...}, "position": {...} | null, "components": [{...}]}
+--
+-- Partitioned by season so an old season's archive can be detached or
+-- dropped wholesale once nobody needs it. Partitions are created with
+-- the season (trigger below); each sets toast_tuple_target low so all
+-- but the smallest snapshots are stored compressed.
+CREATE TABLE entity_archive (
+  season_id         INT NOT NULL REFERENCES seasons(id),
+  entity_id         UUID NOT NULL,
+  entity_type       TEXT NOT NULL,
+  destroyed_at_tick BIGINT NOT NULL,
+  archived_at_tick  BIGINT NOT NULL,
+  archived_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  snapshot          JSONB NOT NULL,
+  PRIMARY KEY (season_id, entity_id)
+) PARTITION BY LIST (season_id);
+
+-- "What was swept around tick T" for a moderator working an incident.
+CREATE INDEX entity_archive_destroyed_idx
+  ON entity_archive (season_id, destroyed_at_tick);
+
+-- +goose StatementBegin
+CREATE FUNCTION create_entity_archive_partition(season INT) RETURNS VOID
+LANGUAGE plpgsql AS $$
+BEGIN
+  EXECUTE format(
+    'CREATE TABLE IF NOT EXISTS %I PARTITION OF entity_archive
+       FOR VALUES IN (%s) WITH (toast_tuple_target = 128)',
+    'entity_archive_s' || season, season);
+END;
+$$;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION seasons_create_archive_partition() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+BEGIN
+  PERFORM create_entity_archive_partition(NEW.id);
+  RETURN NULL;
+END;
+$$;
+-- +goose StatementEnd
+
+CREATE TRIGGER seasons_archive_partition
+  AFTER INSERT ON seasons
+  FOR EACH ROW EXECUTE FUNCTION seasons_create_archive_partition();
+
+SELECT create_entity_archive_partition(id) FROM seasons;
+
+-- +goose Down
+DROP TRIGGER IF EXISTS seasons_archive_partition ON seasons;
+DROP FUNCTION IF EXISTS seasons_create_archive_partition();
+DROP FUNCTION IF EXISTS create_entity_archive_partition(INT);
+DROP TABLE IF EXISTS entity_archive;
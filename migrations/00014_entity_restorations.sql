@@ -0,0 +1,42 @@
+-- +goose Up
+
+-- Append-only audit trail for admin rollbacks of destroyed entities
+-- (DESIGN.md §3.6). One row per game.RestoreEntity call. No FK to
+-- entities: the trail has to outlive the entity if it's destroyed and
+-- swept again.
+CREATE TABLE entity_restorations (
+  id                UUID PRIMARY KEY,            -- UUIDv7, generated in Go
+  entity_id         UUID NOT NULL,
+  season_id         INT NOT NULL REFERENCES seasons(id),
+  restored_by       UUID NOT NULL REFERENCES accounts(id),
+  reason            TEXT NOT NULL,
+  -- What was undone: when the entity was destroyed and, if
+  -- DestroyEntity recorded one, why.
+  destroyed_at_tick BIGINT NOT NULL,
+  destroy_reason    TEXT,
+  restored_at_tick  BIGINT NOT NULL,
+  -- Where it was put back. NULL for an entity restored without a
+  -- position (not in the world).
+  region_id         INT,
+  x                 INT,
+  y                 INT,
+  restored_at       TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp()
+);
+
+-- Per-entity history, newest first — "has this item been rolled back
+-- before?"
+CREATE INDEX entity_restorations_entity_idx
+  ON entity_restorations (entity_id, restored_at DESC);
+
+-- Per-admin history for reviewing what a staff account has done.
+CREATE INDEX entity_restorations_admin_idx
+  ON entity_restorations (restored_by, restored_at DESC);
+
+-- RestoreEntity asks "was this missing entity swept?" knowing only its
+-- ID, not its season.
+CREATE INDEX entity_archive_entity_idx
+  ON entity_archive (entity_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS entity_archive_entity_idx;
+DROP TABLE IF EXISTS entity_restorations;
@@ -0,0 +1,40 @@
+-- +goose Up
+
+-- The per-season character record for a player-controlled entity. See
+-- DESIGN.md §7.2. A character is a subtype of entity: entity_id is both
+-- PK and FK, so hard-deleting the entity takes the character with it;
+-- the sweep archives the row into entity_archive.snapshot first, under
+-- "character".
+CREATE TABLE characters (
+  entity_id       UUID PRIMARY KEY REFERENCES entities(id) ON DELETE CASCADE,
+  account_id      UUID NOT NULL REFERENCES accounts(id),
+  season_id       INT NOT NULL REFERENCES seasons(id),
+  character_name  CITEXT NOT NULL,
+  hp              INT NOT NULL,
+  hp_max          INT NOT NULL CHECK (hp_max > 0),
+  created_at_tick BIGINT NOT NULL,
+  -- Denormalized from entities.destroyed_at_tick; set in the same
+  -- transaction.
+  died_at_tick    BIGINT
+);
+
+-- Names are unique per season, case-insensitively (CITEXT), and recycle
+-- across seasons.
+CREATE UNIQUE INDEX characters_season_name_idx
+  ON characters (season_id, character_name);
+
+-- "This player's living character this season", on every connection.
+-- UNIQUE where §7.2 has a plain index: permadeath means at most one
+-- living character per account per season, and the index is where
+-- that's cheapest to enforce race-free.
+CREATE UNIQUE INDEX characters_account_active_idx
+  ON characters (account_id, season_id)
+  WHERE died_at_tick IS NULL;
+
+-- The death sweep's input. Usually empty.
+CREATE INDEX characters_hp_zero_idx
+  ON characters (entity_id)
+  WHERE hp <= 0 AND died_at_tick IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS characters;
@@ -0,0 +1,28 @@
+-- +goose Up
+
+-- Energy and scheduling state for entities that act over time. See
+-- DESIGN.md §2.5 and §7.3. Sparse: characters, NPCs, and projectiles
+-- have rows; corpses and items don't.
+--
+-- energy is as of updated_at_tick. Current energy is computed lazily,
+-- LEAST(energy_cap, energy + energy_regen * (tick - updated_at_tick)),
+-- so regen costs no writes. It goes negative after an action costing
+-- more than the actor had; an actor acts again once it's back to zero.
+CREATE TABLE actors (
+  entity_id       UUID PRIMARY KEY REFERENCES entities(id) ON DELETE CASCADE,
+  energy          INT NOT NULL,
+  energy_regen    INT NOT NULL CHECK (energy_regen >= 0),
+  energy_cap      INT NOT NULL CHECK (energy_cap > 0),
+  -- NULL means "not scheduled". Set only through MarkActorReady and
+  -- energy deduction.
+  next_ready_tick BIGINT,
+  updated_at_tick BIGINT NOT NULL
+);
+
+-- The scheduler's hot query. Idle actors aren't in it at all.
+CREATE INDEX actors_ready_idx
+  ON actors (next_ready_tick)
+  WHERE next_ready_tick IS NOT NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS actors;
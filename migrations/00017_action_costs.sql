@@ -0,0 +1,41 @@
+-- +goose Up
+
+-- Base energy cost of each action (DESIGN.md §2.5). Gameplay data, not
+-- code: tune it here, or per season through seasons.modifiers'
+-- action_costs, which overrides these by name.
+CREATE TABLE action_costs (
+  action     TEXT PRIMARY KEY CHECK (action <> ''),
+  cost       INT NOT NULL CHECK (cost > 0),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO action_costs (action, cost) VALUES
+  ('move', 40),
+  ('attack', 200);
+
+-- Running servers cache effective costs (game.ActionCosts) and reload
+-- on this channel, so a change to a base cost, a season's overrides, or
+-- which season is active takes effect mid-season without a restart.
+-- The payload is the table that changed.
+-- +goose StatementBegin
+CREATE FUNCTION notify_action_costs() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+BEGIN
+  PERFORM pg_notify('action_costs', TG_TABLE_NAME);
+  RETURN NULL;
+END;
+$$;
+-- +goose StatementEnd
+
+CREATE TRIGGER action_costs_notify
+  AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON action_costs
+  FOR EACH STATEMENT EXECUTE FUNCTION notify_action_costs();
+
+CREATE TRIGGER seasons_action_costs_notify
+  AFTER UPDATE OF modifiers, status ON seasons
+  FOR EACH STATEMENT EXECUTE FUNCTION notify_action_costs();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS seasons_action_costs_notify ON seasons;
+DROP TABLE IF EXISTS action_costs;
+DROP FUNCTION IF EXISTS notify_action_costs();
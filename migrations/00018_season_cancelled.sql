@@ -0,0 +1,14 @@
+-- +goose Up
+
+-- Adds the terminal 'cancelled' status: an upcoming season whose
+-- window passed without it ever opening (the server was down, or the
+-- seed was never set). Without it such a season would sit in
+-- 'upcoming' for good and every later season would wait on it.
+ALTER TABLE seasons DROP CONSTRAINT seasons_status_check;
+ALTER TABLE seasons ADD CONSTRAINT seasons_status_check
+  CHECK (status IN ('upcoming', 'active', 'ended', 'wiped', 'cancelled'));
+
+-- +goose Down
+ALTER TABLE seasons DROP CONSTRAINT seasons_status_check;
+ALTER TABLE seasons ADD CONSTRAINT seasons_status_check
+  CHECK (status IN ('upcoming', 'active', 'ended', 'wiped'));
@@ -24,3 +24,9 @@ RETURNING *;
 UPDATE accounts
 SET status = 'deleted', deleted_at = NOW()
 WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: HasAccountFlag :one
+SELECT EXISTS (
+  SELECT 1 FROM account_flags
+  WHERE account_id = $1 AND flag_type = $2
+);
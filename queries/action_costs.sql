@@ -0,0 +1,13 @@
+-- name: ListActionCosts :many
+SELECT * FROM action_costs
+ORDER BY action;
+
+-- name: SetActionCost :one
+-- Creates or retunes an action's base cost. The notify trigger tells
+-- running servers to reload.
+INSERT INTO action_costs (action, cost)
+VALUES ($1, $2)
+ON CONFLICT (action) DO UPDATE
+SET cost = EXCLUDED.cost,
+    updated_at = NOW()
+RETURNING *;
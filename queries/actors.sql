@@ -0,0 +1,74 @@
+-- name: CreateActor :one
+-- Inserts an actor, idle and at full energy.
+INSERT INTO actors (
+  entity_id, energy, energy_regen, energy_cap, updated_at_tick
+) VALUES (
+  sqlc.arg(entity_id), sqlc.arg(energy_cap), sqlc.arg(energy_regen),
+  sqlc.arg(energy_cap), sqlc.arg(updated_at_tick)
+)
+RETURNING *;
+
+-- name: RestoreActor :exec
+-- Puts back an actors row exactly as archived, for
+-- game.RestoreArchivedEntity.
+INSERT INTO actors (
+  entity_id, energy, energy_regen, energy_cap, next_ready_tick, updated_at_tick
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+);
+
+-- name: GetActor :one
+SELECT * FROM actors
+WHERE entity_id = $1;
+
+-- name: GetActorForUpdate :one
+-- GetActor, locking the row for a read-modify-write of energy.
+SELECT * FROM actors
+WHERE entity_id = $1
+FOR UPDATE;
+
+-- name: MarkActorReady :one
+-- Schedules an actor at sqlc.arg(tick), or at the first tick after that
+-- its energy is back to zero if it's in debt. An actor in debt that
+-- doesn't regenerate stays unscheduled. An actor already scheduled
+-- earlier keeps its place (LEAST ignores NULLs). A destroyed entity's
+-- actor matches no row, so it can't be put back on the schedule.
+-- Mirrors game.Actor.ReadyTick; call it through game.MarkActorReady.
+UPDATE actors
+SET next_ready_tick = LEAST(next_ready_tick,
+  CASE
+    WHEN energy >= 0 THEN sqlc.arg(tick)::BIGINT
+    WHEN energy_regen > 0 THEN GREATEST(sqlc.arg(tick)::BIGINT,
+      updated_at_tick + (energy_regen - 1 - energy) / energy_regen)
+  END)
+WHERE entity_id = sqlc.arg(entity_id)
+  AND EXISTS (
+    SELECT 1 FROM entities e
+    WHERE e.id = actors.entity_id AND e.destroyed_at_tick IS NULL
+  )
+RETURNING *;
+
+-- name: UnscheduleActor :execrows
+-- Takes an actor out of the scheduler until the next MarkActorReady.
+UPDATE actors
+SET next_ready_tick = NULL
+WHERE entity_id = $1
+  AND next_ready_tick IS NOT NULL;
+
+-- name: ListReadyActors :many
+-- The scheduler's query: actors due at or before a tick, soonest first,
+-- ties broken by entity_id so every run sees the same order. Served by
+-- actors_ready_idx.
+SELECT * FROM actors
+WHERE next_ready_tick <= sqlc.arg(tick)::BIGINT
+ORDER BY next_ready_tick, entity_id
+LIMIT sqlc.arg(max_rows);
+
+-- name: SaveActorEnergy :one
+-- Writes back the result of game.Actor.Spend.
+UPDATE actors
+SET energy = $2,
+    next_ready_tick = $3,
+    updated_at_tick = $4
+WHERE entity_id = $1
+RETURNING *;
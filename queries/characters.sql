@@ -0,0 +1,60 @@
+-- name: CreateCharacter :one
+-- Inserts just the characters row. game.CreateCharacter wraps it with
+-- the entity, position, and participation writes. Characters start at
+-- full HP.
+INSERT INTO characters (
+  entity_id, account_id, season_id, character_name, hp, hp_max, created_at_tick
+) VALUES (
+  sqlc.arg(entity_id), sqlc.arg(account_id), sqlc.arg(season_id),
+  sqlc.arg(character_name), sqlc.arg(hp_max), sqlc.arg(hp_max), sqlc.arg(created_at_tick)
+)
+RETURNING *;
+
+-- name: RestoreCharacter :exec
+-- Puts back a characters row exactly as archived, for
+-- game.RestoreArchivedEntity.
+INSERT INTO characters (
+  entity_id, account_id, season_id, character_name, hp, hp_max, created_at_tick, died_at_tick
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+);
+
+-- name: GetCharacter :one
+SELECT * FROM characters
+WHERE entity_id = $1;
+
+-- name: GetLivingCharacter :one
+-- The account's living character in a season, if any. Served by
+-- characters_account_active_idx.
+SELECT * FROM characters
+WHERE account_id = $1
+  AND season_id = $2
+  AND died_at_tick IS NULL;
+
+-- name: ApplyCharacterDamage :one
+-- Subtracts $2 from a living character's HP. HP may go to zero or
+-- below; the death sweep picks it up from there. No rows means the
+-- character is missing or already dead.
+UPDATE characters
+SET hp = hp - sqlc.arg(amount)
+WHERE entity_id = $1
+  AND died_at_tick IS NULL
+RETURNING *;
+
+-- name: MarkCharacterDead :one
+-- Sets died_at_tick. The caller soft-deletes the entity in the same
+-- transaction. No rows means the character is missing or already dead.
+UPDATE characters
+SET died_at_tick = $2
+WHERE entity_id = $1
+  AND died_at_tick IS NULL
+RETURNING *;
+
+-- name: ListDyingCharacters :many
+-- Living characters at or below zero HP: the death sweep's work list,
+-- via characters_hp_zero_idx.
+SELECT * FROM characters
+WHERE hp <= 0
+  AND died_at_tick IS NULL
+ORDER BY entity_id
+LIMIT $1;
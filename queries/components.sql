@@ -17,6 +17,13 @@ RETURNING *;
 SELECT * FROM components
 WHERE entity_id = $1 AND component_type = $2;
 
+-- name: GetComponentForUpdate :one
+-- GetComponent plus a row lock, so the state read is the state the
+-- caller's following write replaces.
+SELECT * FROM components
+WHERE entity_id = $1 AND component_type = $2
+FOR UPDATE;
+
 -- name: DeleteComponent :exec
 DELETE FROM components
 WHERE entity_id = $1 AND component_type = $2;
@@ -31,3 +38,136 @@ FROM components c
 JOIN entities e ON e.id = c.entity_id
 WHERE c.component_type = $1
   AND e.destroyed_at_tick IS NULL;
+
+-- name: HasComponent :one
+SELECT EXISTS (
+  SELECT 1 FROM components
+  WHERE entity_id = $1 AND component_type = $2
+);
+
+-- name: ListDistinctComponentTypes :many
+-- Every component_type present in the table, for the startup check
+-- that the binary has a registered Go type for each. Runs once per
+-- boot, so a full scan is fine.
+SELECT DISTINCT component_type FROM components
+ORDER BY component_type;
+
+-- name: ListStaleComponents :many
+-- One keyset page of a type's rows that may need rewriting, locked.
+-- JSON objects are filtered on their _v key (missing = version 1).
+-- Binary rows keep the version inside a base64 string SQL can't read,
+-- so they're always returned, as is every object row when the type has
+-- moved off JSON; the caller makes the final call on each. The caller
+-- passes the last entity_id it saw; the zero UUID starts from the top.
+SELECT entity_id, state FROM components
+WHERE component_type = sqlc.arg(component_type)
+  AND entity_id > sqlc.arg(after)
+  AND (
+    jsonb_typeof(state) <> 'object'
+    OR NOT sqlc.arg(json_codec)::BOOLEAN
+    OR COALESCE((state->>'_v')::INT, 1) < sqlc.arg(version)::INT
+  )
+ORDER BY entity_id
+LIMIT sqlc.arg(batch_size)
+FOR UPDATE;
+
+-- name: RewriteComponentState :exec
+-- Replaces stored state without touching updated_at_tick: a schema
+-- upgrade isn't a gameplay change, and the broadcast layer shouldn't
+-- see one.
+UPDATE components
+SET state = $3
+WHERE entity_id = $1 AND component_type = $2;
+
+-- name: ListComponentsInSeason :many
+-- Every component of every live entity in a season, for the World
+-- loader. Seasons are loaded once at startup, so a full pass is fine.
+SELECT c.entity_id, c.component_type, c.state, c.created_at_tick, c.updated_at_tick
+FROM components c
+JOIN entities e ON e.id = c.entity_id
+WHERE e.season_id = $1
+  AND e.destroyed_at_tick IS NULL
+ORDER BY c.entity_id, c.component_type;
+
+-- name: UpsertComponentsBatch :execrows
+-- Write-behind counterpart to SetComponent, with the same live-entity
+-- guard as UpsertEntityPositionsBatch. created_at_tick survives the
+-- conflict path, as in SetComponent.
+INSERT INTO components (entity_id, component_type, state, created_at_tick, updated_at_tick)
+SELECT u.entity_id, u.component_type, u.state, u.tick, u.tick
+FROM (
+  SELECT
+    unnest(sqlc.arg(entity_ids)::UUID[]) AS entity_id,
+    unnest(sqlc.arg(component_types)::TEXT[]) AS component_type,
+    unnest(sqlc.arg(states)::JSONB[]) AS state,
+    unnest(sqlc.arg(ticks)::BIGINT[]) AS tick
+) u
+JOIN entities e ON e.id = u.entity_id AND e.destroyed_at_tick IS NULL
+ON CONFLICT (entity_id, component_type) DO UPDATE
+SET state = EXCLUDED.state,
+    updated_at_tick = EXCLUDED.updated_at_tick;
+
+-- name: DeleteComponentsBatch :execrows
+DELETE FROM components c
+USING (
+  SELECT
+    unnest(sqlc.arg(entity_ids)::UUID[]) AS entity_id,
+    unnest(sqlc.arg(component_types)::TEXT[]) AS component_type
+) u
+WHERE c.entity_id = u.entity_id
+  AND c.component_type = u.component_type;
+
+-- name: QueryEntitiesByComponents :many
+-- One keyset page of live entities in a season carrying lead_type, every
+-- one of with_types and none of without_types, optionally narrowed to
+-- some entity types (an empty array means any). The keyset walks
+-- components_type_entity_idx for lead_type from the cursor, so LIMIT
+-- stops it as soon as the page is full; each candidate's entity row and
+-- remaining types are probed by PK.
+SELECT e.id, e.entity_type, e.created_at_tick
+FROM components c
+JOIN entities e ON e.id = c.entity_id
+WHERE c.component_type = sqlc.arg(lead_type)
+  AND c.entity_id > sqlc.arg(after)
+  AND e.season_id = sqlc.arg(season_id)
+  AND e.destroyed_at_tick IS NULL
+  AND (cardinality(sqlc.arg(entity_types)::TEXT[]) = 0
+       OR e.entity_type = ANY(sqlc.arg(entity_types)::TEXT[]))
+  AND NOT EXISTS (
+    SELECT 1 FROM unnest(sqlc.arg(with_types)::TEXT[]) AS w(component_type)
+    WHERE NOT EXISTS (
+      SELECT 1 FROM components o
+      WHERE o.entity_id = c.entity_id
+        AND o.component_type = w.component_type
+    )
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM components x
+    WHERE x.entity_id = c.entity_id
+      AND x.component_type = ANY(sqlc.arg(without_types)::TEXT[])
+  )
+ORDER BY c.entity_id
+LIMIT sqlc.arg(page_size);
+
+-- name: ListComponentsForEntities :many
+-- The given component types for a page of entities, by PK lookup.
+SELECT entity_id, component_type, state
+FROM components
+WHERE entity_id = ANY(sqlc.arg(entity_ids)::UUID[])
+  AND component_type = ANY(sqlc.arg(component_types)::TEXT[])
+ORDER BY entity_id, component_type;
+
+-- name: DeleteComponentsOfTypes :execrows
+-- Strips the given component types from one entity; the destroy helper
+-- uses it to drop transient components.
+DELETE FROM components
+WHERE entity_id = sqlc.arg(entity_id)
+  AND component_type = ANY(sqlc.arg(component_types)::TEXT[]);
+
+-- name: CopyComponents :copyfrom
+-- COPY-based bulk insert for game.CreateEntities.
+INSERT INTO components (
+  entity_id, component_type, state, created_at_tick, updated_at_tick
+) VALUES (
+  $1, $2, $3, $4, $5
+);
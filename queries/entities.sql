@@ -15,8 +15,8 @@ WHERE id = $1;
 
 -- name: SoftDeleteEntity :one
 -- Sets destroyed_at_tick. Application code is responsible for also
--- deleting the entity_positions row in the same transaction (the
--- death/destroy helper does this); we don't cascade on soft-delete
+-- deleting the entity_positions row in the same transaction
+-- (game.DestroyEntity does this); we don't cascade on soft-delete
 -- so the audit log (Layer 6) can still inspect the entity's history.
 UPDATE entities
 SET destroyed_at_tick = $2
@@ -31,11 +31,121 @@ WHERE season_id = $1
   AND entity_type = $2
   AND destroyed_at_tick IS NULL;
 
+-- name: UndeleteEntity :one
+-- Clears destroyed_at_tick. Counterpart to SoftDeleteEntity; the
+-- application restores the position row and anything else destroy
+-- removed. Returns the row as it was before the update, for the audit
+-- trail; no rows means the entity is missing or already live.
+UPDATE entities e
+SET destroyed_at_tick = NULL
+FROM entities prev
+WHERE e.id = $1
+  AND prev.id = e.id
+  AND e.destroyed_at_tick IS NOT NULL
+RETURNING prev.*;
+
 -- name: SweepDestroyedEntities :execrows
 -- Hard-deletes entities that have been soft-deleted longer than the
--- retention window. Cascading FKs (entity_positions, components, etc.)
--- carry the deletion through. Returns rows affected so the caller can
--- log/alert. Gated by config in the Go wrapper — see DESIGN.md §6.6.
+-- retention window, archiving each into entity_archive first — in the
+-- same statement, so nothing is deleted without its archive row.
+-- Cascading FKs (entity_positions, components, etc.) carry the deletion
+-- through. Returns rows affected so the caller can log/alert. Gated by
+-- config in the Go wrapper — see DESIGN.md §6.6.
+WITH doomed AS (
+  SELECT d.* FROM entities d
+  WHERE d.destroyed_at_tick IS NOT NULL
+    AND d.destroyed_at_tick < sqlc.arg(cutoff)
+  FOR UPDATE
+), archived AS (
+  INSERT INTO entity_archive (
+    season_id, entity_id, entity_type, destroyed_at_tick, archived_at_tick, snapshot
+  )
+  SELECT d.season_id, d.id, d.entity_type, d.destroyed_at_tick, sqlc.arg(archived_at_tick),
+    jsonb_build_object(
+      'entity', to_jsonb(d),
+      'position', (SELECT to_jsonb(p) FROM entity_positions p WHERE p.entity_id = d.id),
+      'character', (SELECT to_jsonb(ch) FROM characters ch WHERE ch.entity_id = d.id),
+      'actor', (SELECT to_jsonb(ac) FROM actors ac WHERE ac.entity_id = d.id),
+      'components', COALESCE(
+        (SELECT jsonb_agg(to_jsonb(c) ORDER BY c.component_type)
+         FROM components c WHERE c.entity_id = d.id),
+        '[]'::jsonb)
+    )
+  FROM doomed d
+  RETURNING entity_id
+)
+DELETE FROM entities
+WHERE id IN (SELECT a.entity_id FROM archived a);
+
+-- name: SweepDestroyedEntitiesBatch :execrows
+-- One bounded batch of SweepDestroyedEntities, oldest destructions
+-- first so the walk follows entities_destroyed_idx. SKIP LOCKED keeps
+-- a sweep from queueing behind a transaction still touching a row; the
+-- next batch or run picks it up.
+WITH doomed AS (
+  SELECT d.* FROM entities d
+  WHERE d.destroyed_at_tick IS NOT NULL
+    AND d.destroyed_at_tick < sqlc.arg(cutoff)
+  ORDER BY d.destroyed_at_tick
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE SKIP LOCKED
+), archived AS (
+  INSERT INTO entity_archive (
+    season_id, entity_id, entity_type, destroyed_at_tick, archived_at_tick, snapshot
+  )
+  SELECT d.season_id, d.id, d.entity_type, d.destroyed_at_tick, sqlc.arg(archived_at_tick),
+    jsonb_build_object(
+      'entity', to_jsonb(d),
+      'position', (SELECT to_jsonb(p) FROM entity_positions p WHERE p.entity_id = d.id),
+      'character', (SELECT to_jsonb(ch) FROM characters ch WHERE ch.entity_id = d.id),
+      'actor', (SELECT to_jsonb(ac) FROM actors ac WHERE ac.entity_id = d.id),
+      'components', COALESCE(
+        (SELECT jsonb_agg(to_jsonb(c) ORDER BY c.component_type)
+         FROM components c WHERE c.entity_id = d.id),
+        '[]'::jsonb)
+    )
+  FROM doomed d
+  RETURNING entity_id
+)
 DELETE FROM entities
+WHERE id IN (SELECT a.entity_id FROM archived a);
+
+-- name: CountSweepableEntitiesByType :many
+-- What a sweep at this cutoff would delete, per entity type. Backs the
+-- sweep runner's dry run.
+SELECT entity_type, count(*) AS eligible
+FROM entities
 WHERE destroyed_at_tick IS NOT NULL
-  AND destroyed_at_tick < $1;
+  AND destroyed_at_tick < $1
+GROUP BY entity_type
+ORDER BY entity_type;
+
+-- name: ListLiveEntitiesInSeason :many
+-- Every live entity in a season, for warming the in-memory World.
+-- Ordered so loads are reproducible.
+SELECT * FROM entities
+WHERE season_id = $1
+  AND destroyed_at_tick IS NULL
+ORDER BY id;
+
+-- name: InsertEntitiesBatch :execrows
+-- Write-behind insert for entities created in the in-memory World. One
+-- array per column, zipped by parallel unnest() calls (equal lengths
+-- zip row by row), so a flush is one statement no matter how many rows.
+-- Rows already present are skipped, which makes a retried flush
+-- harmless.
+INSERT INTO entities (id, season_id, entity_type, created_at_tick)
+SELECT
+  unnest(sqlc.arg(ids)::UUID[]),
+  unnest(sqlc.arg(season_ids)::INT[]),
+  unnest(sqlc.arg(entity_types)::TEXT[]),
+  unnest(sqlc.arg(created_at_ticks)::BIGINT[])
+ON CONFLICT (id) DO NOTHING;
+
+-- name: CopyEntities :copyfrom
+-- COPY-based bulk insert for game.CreateEntities (world generation).
+INSERT INTO entities (
+  id, season_id, entity_type, created_at_tick
+) VALUES (
+  $1, $2, $3, $4
+);
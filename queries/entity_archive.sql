@@ -0,0 +1,26 @@
+-- name: GetArchivedEntity :one
+SELECT * FROM entity_archive
+WHERE season_id = $1 AND entity_id = $2;
+
+-- name: ListArchivedEntities :many
+-- A season's archived entities destroyed in [from_tick, to_tick], for a
+-- moderator reconstructing what happened around an incident.
+SELECT * FROM entity_archive
+WHERE season_id = sqlc.arg(season_id)
+  AND destroyed_at_tick BETWEEN sqlc.arg(from_tick) AND sqlc.arg(to_tick)
+ORDER BY destroyed_at_tick, entity_id
+LIMIT sqlc.arg(max_rows);
+
+-- name: TakeArchivedEntity :one
+-- Removes and returns an archive row, for restoring it. Deleting up
+-- front means two concurrent restores can't both succeed.
+DELETE FROM entity_archive
+WHERE season_id = $1 AND entity_id = $2
+RETURNING *;
+
+-- name: IsEntityArchived :one
+-- Whether entity $1 was swept, in any season.
+SELECT EXISTS (
+  SELECT 1 FROM entity_archive
+  WHERE entity_id = $1
+);
@@ -40,3 +40,81 @@ WHERE region_id = $1
 -- code should prefer GetEntitiesAtPosition when possible.
 SELECT * FROM entity_positions
 WHERE region_id = $1;
+
+-- name: ListPositionsInSeason :many
+-- Positions of every live entity in a season; the World loader's
+-- counterpart to ListLiveEntitiesInSeason.
+SELECT p.entity_id, p.region_id, p.x, p.y, p.updated_at_tick
+FROM entity_positions p
+JOIN entities e ON e.id = p.entity_id
+WHERE e.season_id = $1
+  AND e.destroyed_at_tick IS NULL
+ORDER BY p.entity_id;
+
+-- name: UpsertEntityPositionsBatch :execrows
+-- Write-behind counterpart to SetEntityPosition. Only live entities are
+-- written: an entity destroyed (write-through) since the change was
+-- queued must not get its position row back.
+INSERT INTO entity_positions (entity_id, region_id, x, y, updated_at_tick)
+SELECT u.entity_id, u.region_id, u.x, u.y, u.updated_at_tick
+FROM (
+  SELECT
+    unnest(sqlc.arg(entity_ids)::UUID[]) AS entity_id,
+    unnest(sqlc.arg(region_ids)::INT[]) AS region_id,
+    unnest(sqlc.arg(xs)::INT[]) AS x,
+    unnest(sqlc.arg(ys)::INT[]) AS y,
+    unnest(sqlc.arg(updated_at_ticks)::BIGINT[]) AS updated_at_tick
+) u
+JOIN entities e ON e.id = u.entity_id AND e.destroyed_at_tick IS NULL
+ON CONFLICT (entity_id) DO UPDATE
+SET region_id = EXCLUDED.region_id,
+    x = EXCLUDED.x,
+    y = EXCLUDED.y,
+    updated_at_tick = EXCLUDED.updated_at_tick;
+
+-- name: DeleteEntityPositionsBatch :execrows
+DELETE FROM entity_positions
+WHERE entity_id = ANY(sqlc.arg(entity_ids)::UUID[]);
+
+-- name: CopyEntityPositions :copyfrom
+-- COPY-based bulk insert for game.CreateEntities. Plain insert, not an
+-- upsert: the entities are brand new.
+INSERT INTO entity_positions (
+  entity_id, region_id, x, y, updated_at_tick
+) VALUES (
+  $1, $2, $3, $4, $5
+);
+
+-- name: ListVisibleEntitiesAtPosition :many
+-- GetEntitiesAtPosition for a client's eyes: entities carrying the
+-- hidden component are left out unless the viewer can see hidden
+-- things. A viewer always sees its own entity. Wrapped by
+-- game.VisibleAtPosition.
+SELECT p.entity_id, p.region_id, p.x, p.y, p.updated_at_tick
+FROM entity_positions p
+WHERE p.region_id = sqlc.arg(region_id)
+  AND p.x = sqlc.arg(x)
+  AND p.y = sqlc.arg(y)
+  AND (
+    sqlc.arg(sees_hidden)::BOOLEAN
+    OR p.entity_id = sqlc.arg(viewer_id)
+    OR NOT EXISTS (
+      SELECT 1 FROM components c
+      WHERE c.entity_id = p.entity_id AND c.component_type = 'hidden'
+    )
+  );
+
+-- name: ListVisibleEntitiesInRegion :many
+-- GetEntitiesInRegion with the same hidden filter as
+-- ListVisibleEntitiesAtPosition.
+SELECT p.entity_id, p.region_id, p.x, p.y, p.updated_at_tick
+FROM entity_positions p
+WHERE p.region_id = sqlc.arg(region_id)
+  AND (
+    sqlc.arg(sees_hidden)::BOOLEAN
+    OR p.entity_id = sqlc.arg(viewer_id)
+    OR NOT EXISTS (
+      SELECT 1 FROM components c
+      WHERE c.entity_id = p.entity_id AND c.component_type = 'hidden'
+    )
+  );
@@ -0,0 +1,14 @@
+-- name: AppendEntityRestoration :one
+-- Append-only, like moderation_actions.
+INSERT INTO entity_restorations (
+  id, entity_id, season_id, restored_by, reason,
+  destroyed_at_tick, destroy_reason, restored_at_tick, region_id, x, y
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+)
+RETURNING *;
+
+-- name: ListEntityRestorations :many
+SELECT * FROM entity_restorations
+WHERE entity_id = $1
+ORDER BY restored_at DESC;
@@ -0,0 +1,95 @@
+-- name: ListLiveLeaderboard :many
+-- A season's current standings on one board, computed on read. Ties
+-- share a rank (RANK, not ROW_NUMBER); account_id breaks the tie for a
+-- stable page order.
+SELECT
+  RANK() OVER (ORDER BY ls.score DESC)::INT AS rank,
+  ls.account_id,
+  a.display_name,
+  ls.score::BIGINT AS score
+FROM leaderboard_scores ls
+JOIN accounts a ON a.id = ls.account_id
+WHERE ls.season_id = $1 AND ls.board = $2
+ORDER BY rank, ls.account_id
+LIMIT $3 OFFSET $4;
+
+-- name: ListLiveLeaderboardAroundAccount :many
+-- The rows within sqlc.arg(radius) positions of the account, itself
+-- included. Empty if the account has no score on the board.
+WITH ranked AS (
+  SELECT
+    RANK() OVER (ORDER BY ls.score DESC)::INT AS rank,
+    ROW_NUMBER() OVER (ORDER BY ls.score DESC, ls.account_id) AS pos,
+    ls.account_id,
+    a.display_name,
+    ls.score::BIGINT AS score
+  FROM leaderboard_scores ls
+  JOIN accounts a ON a.id = ls.account_id
+  WHERE ls.season_id = sqlc.arg(season_id) AND ls.board = sqlc.arg(board)
+)
+SELECT r.rank, r.account_id, r.display_name, r.score
+FROM ranked r
+JOIN (SELECT pos FROM ranked WHERE account_id = sqlc.arg(account_id)) me
+  ON r.pos BETWEEN me.pos - sqlc.arg(radius)::INT AND me.pos + sqlc.arg(radius)::INT
+ORDER BY r.pos;
+
+-- name: SnapshotLeaderboards :execrows
+-- Freezes every board for the season. Idempotent: a row that already
+-- exists is kept, so re-running after a crash (or from both End and the
+-- wipe) changes nothing.
+INSERT INTO leaderboard_snapshots (season_id, board, account_id, rank, score, display_name)
+SELECT
+  ls.season_id,
+  ls.board,
+  ls.account_id,
+  RANK() OVER (PARTITION BY ls.board ORDER BY ls.score DESC),
+  ls.score,
+  a.display_name
+FROM leaderboard_scores ls
+JOIN accounts a ON a.id = ls.account_id
+WHERE ls.season_id = $1
+ON CONFLICT (season_id, board, account_id) DO NOTHING;
+
+-- name: CountLeaderboardSnapshots :one
+SELECT count(*) FROM leaderboard_snapshots
+WHERE season_id = $1;
+
+-- name: ListLeaderboardSnapshot :many
+SELECT rank, account_id, display_name, score
+FROM leaderboard_snapshots
+WHERE season_id = $1 AND board = $2
+ORDER BY rank, account_id
+LIMIT $3 OFFSET $4;
+
+-- name: ListLeaderboardSnapshotAroundAccount :many
+WITH ranked AS (
+  SELECT
+    rank,
+    ROW_NUMBER() OVER (ORDER BY rank, account_id) AS pos,
+    account_id,
+    display_name,
+    score
+  FROM leaderboard_snapshots
+  WHERE season_id = sqlc.arg(season_id) AND board = sqlc.arg(board)
+)
+SELECT r.rank, r.account_id, r.display_name, r.score
+FROM ranked r
+JOIN (SELECT pos FROM ranked WHERE account_id = sqlc.arg(account_id)) me
+  ON r.pos BETWEEN me.pos - sqlc.arg(radius)::INT AND me.pos + sqlc.arg(radius)::INT
+ORDER BY r.pos;
+
+-- name: ListLeaderboardSnapshotsForAccount :many
+-- Every frozen placement the account holds, newest season first.
+SELECT ls.season_id, s.name AS season_name, ls.board, ls.rank, ls.display_name, ls.score
+FROM leaderboard_snapshots ls
+JOIN seasons s ON s.id = ls.season_id
+WHERE ls.account_id = $1
+ORDER BY ls.season_id DESC, ls.board;
+
+-- name: ListHallOfFame :many
+-- The top sqlc.arg(top_n) ranks of one board in every frozen season.
+SELECT ls.season_id, s.name AS season_name, ls.rank, ls.account_id, ls.display_name, ls.score
+FROM leaderboard_snapshots ls
+JOIN seasons s ON s.id = ls.season_id
+WHERE ls.board = sqlc.arg(board) AND ls.rank <= sqlc.arg(top_n)::INT
+ORDER BY ls.season_id DESC, ls.rank, ls.account_id;
@@ -0,0 +1,80 @@
+-- name: EnsureSeasonParticipation :one
+-- Registers the account in the season on first play; a no-op (that
+-- still returns the row) on every later call.
+--
+-- This and the other writes below are INSERT ... SELECT FROM seasons
+-- WHERE status = 'active': a season that isn't being played produces no
+-- row to insert and so no conflict to update, which keeps a closed
+-- season's stats frozen. The caller sees pgx.ErrNoRows.
+INSERT INTO season_participation (account_id, season_id)
+SELECT sqlc.arg(account_id), s.id FROM seasons s
+WHERE s.id = sqlc.arg(season_id) AND s.status = 'active'
+ON CONFLICT (account_id, season_id) DO UPDATE
+  SET account_id = EXCLUDED.account_id
+RETURNING *;
+
+-- name: IncrementCharactersMade :one
+INSERT INTO season_participation AS sp (account_id, season_id, characters_made)
+SELECT sqlc.arg(account_id), s.id, 1 FROM seasons s
+WHERE s.id = sqlc.arg(season_id) AND s.status = 'active'
+ON CONFLICT (account_id, season_id) DO UPDATE
+  SET characters_made = sp.characters_made + 1
+RETURNING *;
+
+-- name: IncrementDeaths :one
+INSERT INTO season_participation AS sp (account_id, season_id, deaths)
+SELECT sqlc.arg(account_id), s.id, 1 FROM seasons s
+WHERE s.id = sqlc.arg(season_id) AND s.status = 'active'
+ON CONFLICT (account_id, season_id) DO UPDATE
+  SET deaths = sp.deaths + 1
+RETURNING *;
+
+-- name: RecordDeepestRegion :one
+-- Keeps the maximum: reaching a shallower region later doesn't lower
+-- the record. GREATEST ignores the NULL of a participant with no depth
+-- recorded yet.
+INSERT INTO season_participation AS sp (account_id, season_id, deepest_region)
+SELECT sqlc.arg(account_id), s.id, sqlc.arg(region)::int FROM seasons s
+WHERE s.id = sqlc.arg(season_id) AND s.status = 'active'
+ON CONFLICT (account_id, season_id) DO UPDATE
+  SET deepest_region = GREATEST(sp.deepest_region, EXCLUDED.deepest_region)
+RETURNING *;
+
+-- name: GetSeasonParticipation :one
+SELECT * FROM season_participation
+WHERE account_id = $1 AND season_id = $2;
+
+-- name: ListParticipationForAccount :many
+-- Profile-page history: one row per season played, newest season first.
+SELECT
+  sp.season_id,
+  s.name AS season_name,
+  s.status AS season_status,
+  s.starts_at,
+  s.ends_at,
+  sp.characters_made,
+  sp.deaths,
+  sp.deepest_region,
+  sp.final_summary
+FROM season_participation sp
+JOIN seasons s ON s.id = sp.season_id
+WHERE sp.account_id = $1
+ORDER BY sp.season_id DESC;
+
+-- name: RecordLifeLength :one
+-- Keeps the longest life: called when a character dies with how many
+-- ticks it lived.
+INSERT INTO season_participation AS sp (account_id, season_id, longest_life_ticks)
+SELECT sqlc.arg(account_id), s.id, sqlc.arg(ticks)::BIGINT FROM seasons s
+WHERE s.id = sqlc.arg(season_id) AND s.status = 'active'
+ON CONFLICT (account_id, season_id) DO UPDATE
+  SET longest_life_ticks = GREATEST(sp.longest_life_ticks, EXCLUDED.longest_life_ticks)
+RETURNING *;
+
+-- name: AddLootRecovered :one
+INSERT INTO season_participation AS sp (account_id, season_id, loot_recovered)
+SELECT sqlc.arg(account_id), s.id, sqlc.arg(amount)::BIGINT FROM seasons s
+WHERE s.id = sqlc.arg(season_id) AND s.status = 'active'
+ON CONFLICT (account_id, season_id) DO UPDATE
+  SET loot_recovered = sp.loot_recovered + EXCLUDED.loot_recovered
+RETURNING *;
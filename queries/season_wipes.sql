@@ -0,0 +1,86 @@
+-- name: CreateSeasonWipe :one
+INSERT INTO season_wipes (
+  season_id, step, next_season
+) VALUES (
+  $1, 'started', $2
+)
+RETURNING *;
+
+-- name: GetSeasonWipe :one
+SELECT * FROM season_wipes
+WHERE season_id = $1;
+
+-- name: GetSeasonWipeForUpdate :one
+-- Each wipe step locks the progress row first, so two orchestrators
+-- pointed at the same season take turns and the loser sees the
+-- winner's step instead of repeating it.
+SELECT * FROM season_wipes
+WHERE season_id = $1
+FOR UPDATE;
+
+-- name: AdvanceSeasonWipe :exec
+UPDATE season_wipes
+SET step = $2, updated_at = NOW()
+WHERE season_id = $1;
+
+-- name: CompleteSeasonWipe :exec
+UPDATE season_wipes
+SET step = 'done', updated_at = NOW(), completed_at = NOW()
+WHERE season_id = $1;
+
+-- name: AddSeasonWipeRowsDeleted :exec
+UPDATE season_wipes
+SET rows_deleted = rows_deleted + $2, updated_at = NOW()
+WHERE season_id = $1;
+
+-- name: SnapshotSeasonParticipation :execrows
+-- Freezes each participant's season stats into final_summary — the
+-- hall-of-fame record that outlives the wipe (DESIGN.md §5.4).
+UPDATE season_participation
+SET final_summary = jsonb_build_object(
+  'characters_made', characters_made,
+  'deaths', deaths,
+  'deepest_region', deepest_region,
+  'longest_life_ticks', longest_life_ticks,
+  'loot_recovered', loot_recovered
+)
+WHERE season_id = $1;
+
+-- name: DeleteSeasonEntitiesBatch :execrows
+-- Deletes up to $2 of the season's entities. Everything season-scoped
+-- below the entity (positions, components, per-type tables) goes with
+-- it via ON DELETE CASCADE. Bounded so a season's worth of rows isn't
+-- one giant lock-holding statement; the caller loops until it returns 0.
+DELETE FROM entities
+WHERE id IN (
+  SELECT b.id FROM entities b
+  WHERE b.season_id = $1
+  LIMIT $2
+);
+
+-- name: CountSeasonEntities :one
+SELECT count(*) FROM entities
+WHERE season_id = $1;
+
+-- name: CountSeasonEntityPositions :one
+SELECT count(*) FROM entity_positions p
+JOIN entities e ON e.id = p.entity_id
+WHERE e.season_id = $1;
+
+-- name: CountSeasonComponents :one
+SELECT count(*) FROM components c
+JOIN entities e ON e.id = c.entity_id
+WHERE e.season_id = $1;
+
+-- name: CountSeasonCharacters :one
+SELECT count(*) FROM characters
+WHERE season_id = $1;
+
+-- name: CountSeasonActors :one
+SELECT count(*) FROM actors a
+JOIN entities e ON e.id = a.entity_id
+WHERE e.season_id = $1;
+
+-- name: CountSeasonParticipation :one
+SELECT count(*) FROM season_participation
+WHERE season_id = $1;
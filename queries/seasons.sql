@@ -6,10 +6,72 @@ WHERE status = 'active';
 SELECT * FROM seasons
 WHERE id = $1;
 
+-- name: GetSeasonForUpdate :one
+-- Row-locks the season for the rest of the transaction. Every lifecycle
+-- transition goes through this so two operators racing on the same
+-- season serialize instead of both passing the precondition checks.
+SELECT * FROM seasons
+WHERE id = $1
+FOR UPDATE;
+
+-- name: GetPreviousSeason :one
+-- The season immediately before $1 by number. Activation requires it
+-- to be closed; season 1 has no predecessor (pgx.ErrNoRows).
+SELECT * FROM seasons
+WHERE id < $1
+ORDER BY id DESC
+LIMIT 1;
+
 -- name: UpdateSeasonStatus :one
--- Caller is responsible for transition validity. The unique partial index
--- on (status) WHERE status='active' ensures at most one active season.
+-- Raw status write. Application code should go through the season
+-- package's transition functions, which validate the move and hold a
+-- row lock; this query is the primitive they sit on. The unique partial
+-- index on (status) WHERE status='active' ensures at most one active
+-- season.
 UPDATE seasons
 SET status = $2
 WHERE id = $1
 RETURNING *;
+
+-- name: MarkSeasonWiped :one
+-- Terminal transition. Sets status and wiped_at together.
+UPDATE seasons
+SET status = 'wiped', wiped_at = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateSeasonSeed :one
+-- Seeds can only change while the season is still upcoming; once the
+-- world has been generated from a seed, changing it would orphan the
+-- generated content.
+UPDATE seasons
+SET world_seed = $2
+WHERE id = $1 AND status = 'upcoming'
+RETURNING *;
+
+-- name: GetNextUpcomingSeason :one
+-- The upcoming season that should open next: earliest start, season
+-- number as the tie-break. The scheduler polls this.
+SELECT * FROM seasons
+WHERE status = 'upcoming'
+ORDER BY starts_at, id
+LIMIT 1;
+
+-- name: CreateSeason :one
+-- New seasons always start out upcoming; the lifecycle functions take
+-- it from there.
+INSERT INTO seasons (
+  id, name, status, world_seed, modifiers, starts_at, ends_at
+) VALUES (
+  $1, $2, 'upcoming', $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: UpdateSeasonModifiers :one
+-- Modifiers are editable until the season ends; after that they're
+-- part of the historical record. Validation happens in Go before this
+-- runs — the column is plain JSONB.
+UPDATE seasons
+SET modifiers = $2
+WHERE id = $1 AND status IN ('upcoming', 'active')
+RETURNING *;